@@ -1,20 +1,84 @@
 package main
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"travel-ar-backend/internal/auth"
+	"travel-ar-backend/internal/recommend"
+	"travel-ar-backend/internal/search"
 	"travel-ar-backend/internal/server"
+	"travel-ar-backend/internal/token"
+	"travel-ar-backend/internal/translation"
+	"travel-ar-backend/pkg/database"
 )
 
 func main() {
 
 	auth.NewAuth()
+	tokens, err := token.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	token.SetDefault(tokens)
+	database.ConnectDatabase()
+	startTranslationJob(context.Background())
+	startSuggestionJob(context.Background())
+	startCoVisitationJob(context.Background())
 	server := server.NewServer()
 
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic("cannot start server")
 	}
 
 }
+
+// startTranslationJob 配置了翻译服务时，在后台定期补全缺失译文
+// 间隔由 TRANSLATOR_INTERVAL 指定（如 30m），默认 10 分钟
+func startTranslationJob(ctx context.Context) {
+	tr := translation.FromEnv()
+	if tr == nil {
+		return
+	}
+	interval := 10 * time.Minute
+	if v := os.Getenv("TRANSLATOR_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid TRANSLATOR_INTERVAL: %v", err)
+		}
+		interval = d
+	}
+	go translation.RunJob(ctx, database.GetDB(), tr, interval, 200)
+}
+
+// startSuggestionJob 在后台定期重建输入联想候选
+// 间隔由 SUGGESTION_INTERVAL 指定（如 30m），默认 10 分钟
+func startSuggestionJob(ctx context.Context) {
+	interval := 10 * time.Minute
+	if v := os.Getenv("SUGGESTION_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid SUGGESTION_INTERVAL: %v", err)
+		}
+		interval = d
+	}
+	go search.RunSuggestionJob(ctx, database.GetDB(), interval)
+}
+
+// startCoVisitationJob 在后台定期重建推荐用的共同访问矩阵
+// 间隔由 COVISITATION_INTERVAL 指定（如 30m），默认 1 小时
+func startCoVisitationJob(ctx context.Context) {
+	interval := time.Hour
+	if v := os.Getenv("COVISITATION_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid COVISITATION_INTERVAL: %v", err)
+		}
+		interval = d
+	}
+	go recommend.RunJob(ctx, database.GetDB(), interval)
+}
@@ -29,6 +29,11 @@ func NewAuth() {
 
 	fmt.Printf("googleClientId: %s\n", googleClientId)
 	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+	// Web 登录回调地址，需与 Google 控制台登记的一致，对应路由 GET /api/auth/google/callback
+	googleCallbackURL := os.Getenv("GOOGLE_CALLBACK_URL")
+	if googleCallbackURL == "" {
+		googleCallbackURL = "http://localhost:3000/api/auth/google/callback"
+	}
 
 	store := sessions.NewCookieStore([]byte(key))
 	store.MaxAge(MaxAge)
@@ -41,6 +46,6 @@ func NewAuth() {
 	gothic.Store = store
 
 	goth.UseProviders(
-		google.New(googleClientId, googleClientSecret, "http://localhost:3000/api/auth/google/callback"),
+		google.New(googleClientId, googleClientSecret, googleCallbackURL),
 	)
 }
@@ -0,0 +1,82 @@
+// Package campaign 根据访问记录计算集章活动的完成进度
+package campaign
+
+import (
+	"sort"
+	"time"
+)
+
+// 完成规则
+const (
+	RuleAll     = "all"     // 访问全部设施
+	RuleAny     = "any"     // 访问任意 K 个设施
+	RuleOrdered = "ordered" // 按顺序访问全部设施
+)
+
+// Visit 一次设施访问
+type Visit struct {
+	FacilityID int
+	At         time.Time
+}
+
+// Progress 活动进度
+type Progress struct {
+	Visited     []int      // 已计入进度的设施（ordered 规则下为已按顺序完成的设施）
+	Required    int        // 完成所需的设施数
+	Completed   bool       // 是否已完成
+	CompletedAt *time.Time // 满足完成条件的访问时间
+	NextID      int        // ordered 规则下应访问的下一个设施，其它规则或已完成时为 0
+}
+
+// Evaluate 计算进度；facilities 为活动设施（ordered 规则下按顺序排列），required 为 any 规则的 K
+// visits 中不属于活动设施的记录会被忽略
+func Evaluate(rule string, required int, facilities []int, visits []Visit) Progress {
+	sorted := append([]Visit(nil), visits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	p := Progress{Visited: []int{}, Required: len(facilities)}
+	if rule == RuleAny {
+		p.Required = required
+		if p.Required <= 0 || p.Required > len(facilities) {
+			p.Required = len(facilities)
+		}
+	}
+
+	if rule == RuleOrdered {
+		next := 0
+		for _, v := range sorted {
+			if next < len(facilities) && v.FacilityID == facilities[next] {
+				p.Visited = append(p.Visited, v.FacilityID)
+				next++
+				if next == len(facilities) {
+					at := v.At
+					p.CompletedAt = &at
+				}
+			}
+		}
+		p.Completed = len(facilities) > 0 && next == len(facilities)
+		if !p.Completed && next < len(facilities) {
+			p.NextID = facilities[next]
+		}
+		return p
+	}
+
+	member := make(map[int]bool, len(facilities))
+	for _, id := range facilities {
+		member[id] = true
+	}
+	seen := map[int]bool{}
+	for _, v := range sorted {
+		if !member[v.FacilityID] || seen[v.FacilityID] {
+			continue
+		}
+		seen[v.FacilityID] = true
+		p.Visited = append(p.Visited, v.FacilityID)
+		if len(p.Visited) == p.Required && p.Required > 0 {
+			at := v.At
+			p.CompletedAt = &at
+		}
+	}
+	p.Completed = p.Required > 0 && len(p.Visited) >= p.Required
+	return p
+}
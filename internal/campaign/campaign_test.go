@@ -0,0 +1,46 @@
+package campaign
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func visits(ids ...int) []Visit {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	out := make([]Visit, len(ids))
+	for i, id := range ids {
+		out[i] = Visit{FacilityID: id, At: base.Add(time.Duration(i) * time.Hour)}
+	}
+	return out
+}
+
+func TestEvaluateAll(t *testing.T) {
+	p := Evaluate(RuleAll, 0, []int{1, 2, 3}, visits(3, 9, 1, 3))
+	if p.Completed || !reflect.DeepEqual(p.Visited, []int{3, 1}) {
+		t.Fatalf("partial: %+v", p)
+	}
+	p = Evaluate(RuleAll, 0, []int{1, 2, 3}, visits(3, 1, 2))
+	if !p.Completed || p.CompletedAt == nil || p.CompletedAt.Hour() != 11 {
+		t.Fatalf("complete: %+v", p)
+	}
+}
+
+func TestEvaluateAny(t *testing.T) {
+	p := Evaluate(RuleAny, 2, []int{1, 2, 3}, visits(1, 1, 3))
+	if !p.Completed || p.Required != 2 || p.CompletedAt.Hour() != 11 {
+		t.Fatalf("any 2 of 3: %+v", p)
+	}
+}
+
+func TestEvaluateOrdered(t *testing.T) {
+	// 3 在 2 之前访问不计入，之后重新访问 3 才完成
+	p := Evaluate(RuleOrdered, 0, []int{1, 2, 3}, visits(1, 3, 2))
+	if p.Completed || p.NextID != 3 || !reflect.DeepEqual(p.Visited, []int{1, 2}) {
+		t.Fatalf("out of order: %+v", p)
+	}
+	p = Evaluate(RuleOrdered, 0, []int{1, 2, 3}, visits(1, 3, 2, 3))
+	if !p.Completed || p.NextID != 0 {
+		t.Fatalf("in order: %+v", p)
+	}
+}
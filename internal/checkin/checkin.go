@@ -0,0 +1,79 @@
+// Package checkin 生成与校验设施签到用的轮换签名二维码内容
+package checkin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prefix 二维码内容前缀，同时作为签名版本号
+const prefix = "TRAR1"
+
+var (
+	// ErrInvalid 二维码内容格式错误或签名不符
+	ErrInvalid = errors.New("checkin: invalid payload")
+	// ErrExpired 二维码已过期
+	ErrExpired = errors.New("checkin: payload expired")
+)
+
+// Signer 按固定周期轮换的二维码签名器
+// 二维码内容为 TRAR1.<设施ID>.<周期序号>.<HMAC-SHA256>，当前周期与上一周期的内容均有效
+type Signer struct {
+	secret []byte
+	period time.Duration
+}
+
+// NewSigner 创建签名器，period 为二维码轮换周期
+func NewSigner(secret []byte, period time.Duration) *Signer {
+	return &Signer{secret: secret, period: period}
+}
+
+// Issue 生成设施在 now 时刻的二维码内容，返回内容与本周期结束时间
+func (s *Signer) Issue(facilityID int, now time.Time) (string, time.Time) {
+	window := s.window(now)
+	payload := fmt.Sprintf("%s.%d.%d", prefix, facilityID, window)
+	expiresAt := time.Unix(0, (window+1)*int64(s.period))
+	return payload + "." + s.sign(payload), expiresAt
+}
+
+// Verify 校验二维码内容，返回设施ID
+func (s *Signer) Verify(payload string, now time.Time) (int, error) {
+	parts := strings.Split(payload, ".")
+	if len(parts) != 4 || parts[0] != prefix {
+		return 0, ErrInvalid
+	}
+	facilityID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	window, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	signed := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.sign(signed))) {
+		return 0, ErrInvalid
+	}
+	// 允许上一周期的内容，避免恰好在轮换时扫码失败
+	current := s.window(now)
+	if window > current || window < current-1 {
+		return 0, ErrExpired
+	}
+	return facilityID, nil
+}
+
+func (s *Signer) window(t time.Time) int64 {
+	return t.UnixNano() / int64(s.period)
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
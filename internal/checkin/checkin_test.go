@@ -0,0 +1,49 @@
+package checkin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueVerify(t *testing.T) {
+	s := NewSigner([]byte("secret"), 5*time.Minute)
+	now := time.Date(2025, 4, 1, 10, 2, 0, 0, time.UTC)
+
+	payload, expiresAt := s.Issue(42, now)
+	if !expiresAt.Equal(time.Date(2025, 4, 1, 10, 5, 0, 0, time.UTC)) {
+		t.Errorf("expiresAt = %v", expiresAt)
+	}
+	if id, err := s.Verify(payload, now); err != nil || id != 42 {
+		t.Fatalf("Verify = %d, %v", id, err)
+	}
+	// 上一周期的内容仍有效，再往前则过期
+	if _, err := s.Verify(payload, now.Add(5*time.Minute)); err != nil {
+		t.Errorf("previous window: %v", err)
+	}
+	if _, err := s.Verify(payload, now.Add(10*time.Minute)); !errors.Is(err, ErrExpired) {
+		t.Errorf("stale payload err = %v, want ErrExpired", err)
+	}
+	if _, err := s.Verify(payload, now.Add(-5*time.Minute)); !errors.Is(err, ErrExpired) {
+		t.Errorf("future payload err = %v, want ErrExpired", err)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	s := NewSigner([]byte("secret"), time.Minute)
+	now := time.Now()
+	payload, _ := s.Issue(1, now)
+
+	forged := strings.Replace(payload, "TRAR1.1.", "TRAR1.2.", 1)
+	if _, err := s.Verify(forged, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("forged facility err = %v, want ErrInvalid", err)
+	}
+	other := NewSigner([]byte("other"), time.Minute)
+	if _, err := other.Verify(payload, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong secret err = %v, want ErrInvalid", err)
+	}
+	if _, err := s.Verify("not-a-payload", now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("garbage err = %v, want ErrInvalid", err)
+	}
+}
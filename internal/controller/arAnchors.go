@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/hours"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateARAnchor godoc
+// @Summary 新建AR锚点
+// @Description 为设施新建AR锚点，绑定标记识别码与3D模型/叠加素材
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param anchor body model.ARAnchorReqCreate true "AR锚点信息"
+// @Success 200 {object} model.Response[model.ARAnchor]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/ar_anchors [post]
+func CreateARAnchor(c *gin.Context) {
+	var req model.ARAnchorReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !validateARAnchor(c, db, 0, req) {
+		return
+	}
+
+	anchor := model.ARAnchor{}
+	applyARAnchor(&anchor, req)
+	if err := db.Create(&anchor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.ARAnchor]{Success: true, Data: anchor})
+}
+
+// UpdateARAnchor godoc
+// @Summary 更新AR锚点
+// @Description 更新AR锚点信息
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param anchor body model.ARAnchorReqEdit true "AR锚点信息"
+// @Success 200 {object} model.Response[model.ARAnchor]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/ar_anchors [put]
+func UpdateARAnchor(c *gin.Context) {
+	var req model.ARAnchorReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var anchor model.ARAnchor
+	if err := db.First(&anchor, req.AnchorID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "AR锚点不存在"})
+		return
+	}
+	if !validateARAnchor(c, db, anchor.AnchorID, req.ARAnchorReqCreate) {
+		return
+	}
+
+	applyARAnchor(&anchor, req.ARAnchorReqCreate)
+	if err := db.Save(&anchor).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.ARAnchor]{Success: true, Data: anchor})
+}
+
+// DeleteARAnchor godoc
+// @Summary 删除AR锚点
+// @Description 删除一个AR锚点
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param anchor_id path int true "AR锚点ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/ar_anchors/{anchor_id} [delete]
+func DeleteARAnchor(c *gin.Context) {
+	anchorID, _ := strconv.Atoi(c.Param("anchor_id"))
+	db := database.GetDB()
+	if err := db.Delete(&model.ARAnchor{}, anchorID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetARAnchor godoc
+// @Summary 获取单个AR锚点
+// @Description 获取AR锚点信息
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param anchor_id path int true "AR锚点ID"
+// @Success 200 {object} model.Response[model.ARAnchor]
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/ar_anchors/{anchor_id} [get]
+func GetARAnchor(c *gin.Context) {
+	anchorID, _ := strconv.Atoi(c.Param("anchor_id"))
+	db := database.GetDB()
+	var anchor model.ARAnchor
+	if err := db.First(&anchor, anchorID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "AR锚点不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.ARAnchor]{Success: true, Data: anchor})
+}
+
+// ListARAnchors godoc
+// @Summary 获取AR锚点列表
+// @Description 获取AR锚点分页列表，可按设施筛选、按名称或识别码搜索
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param req body model.ARAnchorReqList true "分页与搜索"
+// @Success 200 {object} model.ListResponse[model.ARAnchor]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/ar_anchors/list [post]
+func ListARAnchors(c *gin.Context) {
+	var req model.ARAnchorReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var anchors []model.ARAnchor
+	var total int64
+
+	query := db.Model(&model.ARAnchor{})
+	if req.FacilityID != 0 {
+		query = query.Where("facility_id = ?", req.FacilityID)
+	}
+	if req.Keyword != "" {
+		query = query.Where("anchor_name LIKE ? OR marker_code LIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("anchor_id").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&anchors)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.ARAnchor]{
+		Success: true,
+		Total:   total,
+		List:    anchors,
+	})
+}
+
+// ResolveARMarker godoc
+// @Summary 解析AR标记
+// @Description 根据扫描到的标记识别码，一次返回所属设施、锚点参数与AR素材；锚点未启用或不在启用时段时返回404
+// @Tags ARAnchors
+// @Accept json
+// @Produce json
+// @Param marker_code path string true "标记识别码"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.ARResolveResult]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/ar_anchors/resolve/{marker_code} [get]
+func ResolveARMarker(c *gin.Context) {
+	db := database.GetDB()
+	var anchor model.ARAnchor
+	if err := db.Where("marker_code = ?", c.Param("marker_code")).First(&anchor).Error; err != nil ||
+		!arAnchorActive(anchor, time.Now()) {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "标记不存在或当前未启用"})
+		return
+	}
+
+	var facility model.Facility
+	if err := db.First(&facility, anchor.FacilityID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "设施不存在"})
+		return
+	}
+	localizeFacilities(c, db, []*model.Facility{&facility})
+
+	// 只取元数据，素材内容由客户端按 URL 下载
+	var file model.File
+	if err := db.Select("file_id", "file_name", "file_type", "file_size").First(&file, anchor.AssetFileID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "AR素材不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response[model.ARResolveResult]{Success: true, Data: model.ARResolveResult{
+		Facility: facility,
+		Anchor:   anchor,
+		Asset: model.ARAsset{
+			FileID:   file.FileID,
+			FileName: file.FileName,
+			FileType: file.FileType,
+			FileSize: file.FileSize,
+			URL:      fmt.Sprintf("/api/files/%d/content", file.FileID),
+		},
+	}})
+}
+
+// validateARAnchor 校验锚点请求，失败时写入响应并返回 false；anchorID 为更新时的自身ID
+func validateARAnchor(c *gin.Context, db *gorm.DB, anchorID int, req model.ARAnchorReqCreate) bool {
+	if err := db.First(&model.Facility{}, req.FacilityID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "设施不存在"})
+		return false
+	}
+	if err := db.Select("file_id").First(&model.File{}, req.AssetFileID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "AR素材文件不存在"})
+		return false
+	}
+	if req.MarkerFileID != nil {
+		if err := db.Select("file_id").First(&model.File{}, *req.MarkerFileID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "标记参考图不存在"})
+			return false
+		}
+	}
+	if req.ActiveFrom != nil && req.ActiveUntil != nil && !req.ActiveUntil.After(*req.ActiveFrom) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "启用结束时间须晚于开始时间"})
+		return false
+	}
+	if (req.DailyStart == nil) != (req.DailyEnd == nil) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "每日启用时段须同时指定开始与结束时刻"})
+		return false
+	}
+	if req.DailyStart != nil {
+		if _, err := hours.ParseRange(*req.DailyStart, *req.DailyEnd); err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return false
+		}
+	}
+	var count int64
+	db.Model(&model.ARAnchor{}).Where("marker_code = ? AND anchor_id <> ?", req.MarkerCode, anchorID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, model.BaseResponse{Success: false, ErrMessage: "标记识别码已被使用"})
+		return false
+	}
+	return true
+}
+
+func applyARAnchor(anchor *model.ARAnchor, req model.ARAnchorReqCreate) {
+	anchor.FacilityID = req.FacilityID
+	anchor.AnchorName = req.AnchorName
+	anchor.MarkerType = req.MarkerType
+	anchor.MarkerCode = req.MarkerCode
+	anchor.MarkerFileID = req.MarkerFileID
+	anchor.AssetFileID = req.AssetFileID
+	anchor.Altitude = req.Altitude
+	anchor.Heading = req.Heading
+	anchor.Scale = req.Scale
+	if anchor.Scale == 0 {
+		anchor.Scale = 1
+	}
+	anchor.ActiveFrom = req.ActiveFrom
+	anchor.ActiveUntil = req.ActiveUntil
+	anchor.DailyStart = req.DailyStart
+	anchor.DailyEnd = req.DailyEnd
+	anchor.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// arAnchorActive 判断锚点在 now 时刻是否处于启用状态
+func arAnchorActive(anchor model.ARAnchor, now time.Time) bool {
+	if !anchor.IsActive {
+		return false
+	}
+	if anchor.ActiveFrom != nil && now.Before(*anchor.ActiveFrom) {
+		return false
+	}
+	if anchor.ActiveUntil != nil && !now.Before(*anchor.ActiveUntil) {
+		return false
+	}
+	if anchor.DailyStart != nil && anchor.DailyEnd != nil {
+		r, err := hours.ParseRange(*anchor.DailyStart, *anchor.DailyEnd)
+		if err != nil || !r.Contains(now) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateARAnchorKeepsInactive(t *testing.T) {
+	db := setupDB(t)
+	facility := createFacility(t, db)
+	asset := model.File{FileName: "lantern.glb", FileType: "model/gltf-binary", FileData: []byte("glTF"), Location: "test", RelatedID: facility.FacilityID}
+	if err := db.Create(&asset).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Delete(&model.File{}, asset.FileID) })
+	active := false
+
+	w := serve(t, CreateARAnchor, request{
+		method: http.MethodPost,
+		target: "/api/ar_anchors",
+		body: model.ARAnchorReqCreate{
+			FacilityID:  facility.FacilityID,
+			AnchorName:  "雷門",
+			MarkerType:  "qr",
+			MarkerCode:  fmt.Sprintf("test-kaminarimon-%d", time.Now().UnixNano()),
+			AssetFileID: asset.FileID,
+			IsActive:    &active,
+		},
+		role: model.RoleAdmin,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	created := decode[model.Response[model.ARAnchor]](t, w).Data
+	t.Cleanup(func() { db.Delete(&model.ARAnchor{}, created.AnchorID) })
+
+	id := strconv.Itoa(created.AnchorID)
+	w = serve(t, GetARAnchor, request{
+		method: http.MethodGet,
+		target: "/api/ar_anchors/" + id,
+		params: gin.Params{{Key: "anchor_id", Value: id}},
+	})
+	if got := decode[model.Response[model.ARAnchor]](t, w).Data; got.IsActive {
+		t.Fatalf("is_active = true, want false")
+	}
+}
@@ -6,10 +6,8 @@ import (
 	"travel-ar-backend/internal/model"
 	"travel-ar-backend/pkg/database"
 
-	"strings"
-
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
 )
 
 // CreateArticle godoc
@@ -26,32 +24,13 @@ import (
 // @Security ApiKeyAuth
 // @Router /api/articles [post]
 func CreateArticle(c *gin.Context) {
-	// 1. 获取并校验access token
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(401, model.BaseResponse{Success: false, ErrMessage: "未登录，缺少token"})
-		return
-	}
-	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-	type UserIDClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
-	}
-	claims := &UserIDClaims{}
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
-	if err != nil || !token.Valid {
-		c.JSON(401, model.BaseResponse{Success: false, ErrMessage: "token无效或已过期"})
-		return
-	}
-	// 2. 解析请求体
+	// 1. 解析请求体（登录校验由 JWTAuth 中间件完成）
 	var req model.ArticleReqCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
-	// 3. 创建文章
+	// 2. 创建文章
 	article := model.Article{
 		Title:        req.Title,
 		BodyText:     req.BodyText,
@@ -115,7 +94,16 @@ func DeleteArticle(c *gin.Context) {
 	id := c.Param("article_id")
 	articleID, _ := strconv.Atoi(id)
 	db := database.GetDB()
-	if err := db.Delete(&model.Article{}, articleID).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteFavorites(tx, model.EntityArticle, articleID); err != nil {
+			return err
+		}
+		if err := deleteTaggings(tx, model.EntityArticle, articleID); err != nil {
+			return err
+		}
+		return tx.Delete(&model.Article{}, articleID).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
@@ -129,6 +117,7 @@ func DeleteArticle(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param article_id path int true "文章ID"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.Response[model.Article]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
@@ -142,7 +131,10 @@ func GetArticle(c *gin.Context) {
 		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "文章不存在"})
 		return
 	}
-	c.JSON(http.StatusOK, model.Response[model.Article]{Success: true, Data: article})
+	articles := []model.Article{article}
+	localizeArticles(c, db, articles)
+	fillArticleFavorites(c, db, articles)
+	c.JSON(http.StatusOK, model.Response[model.Article]{Success: true, Data: articles[0]})
 }
 
 // ListArticles godoc
@@ -152,6 +144,7 @@ func GetArticle(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param req body model.ArticleReqList true "分页与搜索"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.ListResponse[model.Article]
 // @Failure 400 {object} model.BaseResponse
 // @Router /api/articles/list [post]
@@ -165,8 +158,11 @@ func ListArticles(c *gin.Context) {
 	var articles []model.Article
 	var total int64
 
-	db.Model(&model.Article{}).Count(&total)
-	db.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&articles)
+	query := filterByTags(db.Model(&model.Article{}), model.EntityArticle, "articles.article_id", req.TagIDs, req.TagMatch)
+	query.Count(&total)
+	query.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&articles)
+	localizeArticles(c, db, articles)
+	fillArticleFavorites(c, db, articles)
 
 	c.JSON(http.StatusOK, model.ListResponse[model.Article]{
 		Success: true,
@@ -1,29 +1,22 @@
 package controller
 
 import (
-	"encoding/json"
-	"fmt"
-	"math/rand"
-	"net/http"
+	"errors"
+	"log"
 	"time"
 
 	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/token"
 	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-const secretKey = "my_secret_key"
-const refreshSecretKey = "my_refresh_secret_key"
-
-type Claims struct {
-	Username string `json:"username"`
-	jwt.RegisteredClaims
-}
-
 // Login godoc
 // @Summary 登录
 // @Description 登录
@@ -31,9 +24,11 @@ type Claims struct {
 // @Accept json
 // @Produce json
 // @Param payload body model.LoginRequest true "登录请求"
+// @Param X-Device-Name header string false "设备名称，显示在登录会话列表中"
 // @Success 200 {object} model.Response[model.AuthResponse]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 401 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse "邮箱尚未验证或账号已停用"
 // @Failure 500 {object} model.BaseResponse
 // @Router /api/auth/login [post]
 func Login(c *gin.Context) {
@@ -52,45 +47,28 @@ func Login(c *gin.Context) {
 		c.JSON(401, model.BaseResponse{Success: false, ErrMessage: "密码错误"})
 		return
 	}
-	accessToken, err := generateAccessToken(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "Token生成失败"})
-		return
-	}
-	refreshToken, err := generateRefreshTokenJWT(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken生成失败"})
+	if user.Status == model.UserStatusPending {
+		c.JSON(403, model.BaseResponse{Success: false, ErrMessage: "邮箱尚未验证，请先完成邮箱验证"})
 		return
 	}
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	if err := db.Create(&model.RefreshToken{
-		UserID:       user.UserID,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		Revoked:      false,
-	}).Error; err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken存储失败"})
+	if user.Status != model.UserStatusActive {
+		c.JSON(403, model.BaseResponse{Success: false, ErrMessage: "账号已停用"})
 		return
 	}
-	c.JSON(200, model.Response[model.AuthResponse]{
-		Success: true,
-		Data: model.AuthResponse{
-			User:         user,
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-		},
-	})
+	respondAuth(c, db, user)
 }
 
 // Register godoc
 // @Summary 用户注册
-// @Description 用户注册，创建新用户
+// @Description 用户注册，创建待验证（pending）的新用户并向邮箱发送验证码；通过 /api/verify 验证后才能登录。邮箱已注册但未验证时改用本次设置的密码、解除该账号的第三方登录方式，并重新发送验证码（受发送间隔限制）
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param payload body model.RegisterRequest true "注册请求"
-// @Success 200 {object} model.Response[model.AuthResponse]
+// @Param lang query string false "邮件语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.User]
 // @Failure 400 {object} model.BaseResponse
+// @Failure 429 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
 // @Router /api/auth/register [post]
 func Register(c *gin.Context) {
@@ -107,28 +85,28 @@ func Register(c *gin.Context) {
 	}
 
 	// 密码强度校验
-	if len(req.Password) < 6 {
+	if len(req.Password) < minPasswordLength {
 		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "密码长度不能少于6位"})
 		return
 	}
 
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "密码加密失败"})
+		return
+	}
+
 	db := database.GetDB()
 	var user model.User
 	if err := db.Where("email = ?", req.Email).First(&user).Error; err == nil {
 		// 已存在该邮箱
-		if user.Status == "pending" {
-			// 未激活，更新验证码和过期时间
-			verifyCode := fmt.Sprintf("%04d", rand.Intn(10000))
-			verifyExpire := time.Now().Add(10 * time.Minute)
-			user.VerifyCode = verifyCode
-			user.VerifyCodeExpire = &verifyExpire
-			if err := db.Save(&user).Error; err != nil {
-				c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "验证码更新失败: " + err.Error()})
+		if user.Status == model.UserStatusPending {
+			// 未激活的账号可能由他人抢先注册：改用本次的密码，解除第三方身份并作废待绑定的身份，再重新发送验证码
+			if err := resetPendingUser(db, user, string(hashedPwd)); err != nil {
+				c.JSON(500, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 				return
 			}
-			// TODO: 发送验证码到邮箱 user.Email，内容为 verifyCode
-			// sendVerifyCodeToEmail(user.Email, verifyCode)
-			c.JSON(200, model.BaseResponse{Success: true, ErrMessage: "验证码已重新发送，请查收邮箱"})
+			resendVerifyCode(c, db, req.Email)
 			return
 		} else {
 			c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "邮箱已被注册"})
@@ -136,55 +114,60 @@ func Register(c *gin.Context) {
 		}
 	}
 
-	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "密码加密失败"})
-		return
-	}
-
-	// 生成4位验证码
-	verifyCode := fmt.Sprintf("%04d", rand.Intn(10000))
-	verifyExpire := time.Now().Add(10 * time.Minute)
-
+	now := time.Now()
+	verifyExpire := now.Add(verifyCodeTTL)
 	user = model.User{
 		Email:            req.Email,
 		Password:         string(hashedPwd),
 		Provider:         "email",
-		Status:           "pending", // 注册后状态为pending，待激活
-		VerifyCode:       verifyCode,
+		Status:           model.UserStatusPending, // 注册后状态为pending，待激活
+		Role:             model.RoleTourist,
+		VerifyCode:       randcode.Digits(verifyCodeLength),
 		VerifyCodeExpire: &verifyExpire,
+		VerifySentAt:     &now,
 	}
 	if err := db.Create(&user).Error; err != nil {
 		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
 
-	// TODO: 发送验证码到邮箱 user.Email，内容为 verifyCode
-	// sendVerifyCodeToEmail(user.Email, verifyCode)
-
-	accessToken, err := generateAccessToken(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "Token生成失败"})
+	// 发送失败时用户已创建，可通过 /api/verify/resend 重新发送
+	if err := sendVerifyCode(c, user); err != nil {
+		log.Printf("send verify code to user %d: %v", user.UserID, err)
+		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "验证邮件发送失败，请稍后重新发送"})
 		return
 	}
+	c.JSON(200, model.Response[model.User]{Success: true, Data: user})
+}
 
-	refreshToken, err := generateRefreshTokenJWT(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken生成失败"})
-		return
-	}
+// resetPendingUser 以邮箱重新注册未激活的账号：替换密码、清除第三方身份，并作废尚未使用的绑定凭证
+// 验证通过后账号只能以本次设置的密码登录
+func resetPendingUser(db *gorm.DB, user model.User, hashedPwd string) error {
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password":   hashedPwd,
+			"provider":   "email",
+			"google_id":  "",
+			"apple_id":   "",
+			"line_id":    "",
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.AccountLink{}).
+			Where("user_id = ? AND used_at IS NULL", user.UserID).
+			Update("used_at", now).Error
+	})
+}
 
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	if err := db.Create(&model.RefreshToken{
-		UserID:       user.UserID,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		Revoked:      false,
-	}).Error; err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken存储失败"})
+// respondAuth 为用户签发 access token 与 refresh token 并返回登录结果，每次登录开启一个新会话
+func respondAuth(c *gin.Context, db *gorm.DB, user model.User) {
+	accessToken, refreshToken, err := issueTokens(c, db, user, newSessionID(), deviceName(c))
+	if err != nil {
+		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "Token生成失败"})
 		return
 	}
-
 	c.JSON(200, model.Response[model.AuthResponse]{
 		Success: true,
 		Data: model.AuthResponse{
@@ -195,227 +178,135 @@ func Register(c *gin.Context) {
 	})
 }
 
-// 生成短时access token（15分钟）
-func generateAccessToken(userID int) (string, error) {
-	expirationTime := time.Now().Add(15 * time.Minute)
-	type UserIDClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
-	}
-	claims := &UserIDClaims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
-}
-
-// 生成长时refresh token（7天）
-func generateRefreshTokenJWT(userID int) (string, error) {
-	expirationTime := time.Now().Add(7 * 24 * time.Hour)
-	type RefreshClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
-	}
-	claims := &RefreshClaims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(refreshSecretKey))
-}
-
 // RefreshToken godoc
 // @Summary 刷新Access Token
-// @Description 使用Refresh Token刷新Access Token
+// @Description 使用Refresh Token刷新Access Token。每次刷新都会返回新的Refresh Token，旧的立即失效；已轮换的Refresh Token再次使用时视为被盗用，该会话全部失效。请求体未提供时使用 refresh_token Cookie，新令牌同时写入 Cookie
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param payload body model.RefreshTokenRequest true "刷新Token请求"
+// @Param payload body model.RefreshTokenRequest false "刷新Token请求"
 // @Success 200 {object} model.Response[model.RefreshTokenResponse]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 401 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
-// @Router /api/auth/refresh [post]
+// @Router /api/refresh [post]
 func RefreshToken(c *gin.Context) {
 	var req model.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+	if !bindOptionalJSON(c, &req) {
 		return
 	}
-	// 1. 校验refreshToken格式和签名
-	refreshTokenStr := req.RefreshToken
-	type RefreshClaims struct {
-		UserID int `json:"user_id"`
-		jwt.RegisteredClaims
+	if req.RefreshToken = refreshTokenOrCookie(c, req.RefreshToken); req.RefreshToken == "" {
+		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "缺少refresh token"})
+		return
 	}
-	claims := &RefreshClaims{}
-	token, err := jwt.ParseWithClaims(refreshTokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(refreshSecretKey), nil
-	})
-	if err != nil || !token.Valid {
+	// 1. 校验refreshToken格式和签名
+	if _, err := token.Default().ParseRefresh(req.RefreshToken); err != nil {
 		c.JSON(401, model.BaseResponse{Success: false, ErrMessage: "refresh token无效"})
 		return
 	}
-	// 2. 查库校验refreshToken是否存在且未撤销且未过期
+
+	// 2. 查库校验并轮换：作废旧令牌，在同一会话中签发新令牌
 	db := database.GetDB()
-	var dbToken model.RefreshToken
-	if err := db.Where("refresh_token = ? AND revoked = false AND expires_at > ?", refreshTokenStr, time.Now()).First(&dbToken).Error; err != nil {
-		c.JSON(401, model.BaseResponse{Success: false, ErrMessage: "refresh token无效或已过期"})
+	var user model.User
+	var refreshToken, sessionID string
+	var rejected *errVerifyRejected
+	var refreshExpires time.Time
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var current model.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(req.RefreshToken)).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				rejected = &errVerifyRejected{401, "refresh token无效或已过期"}
+				return nil
+			}
+			return err
+		}
+		now := time.Now()
+		if current.RotatedAt != nil {
+			// 已轮换的令牌被再次使用：令牌可能已泄露，作废整个会话
+			log.Printf("refresh token reuse detected: user %d, session %s", current.UserID, current.FamilyID)
+			rejected = &errVerifyRejected{401, "refresh token已失效，请重新登录"}
+			return revokeSessions(tx, current.UserID, current.FamilyID)
+		}
+		if current.Revoked || !current.ExpiresAt.After(now) {
+			rejected = &errVerifyRejected{401, "refresh token无效或已过期"}
+			return nil
+		}
+		// 读取用户最新角色与状态
+		if err := tx.First(&user, current.UserID).Error; err != nil {
+			rejected = &errVerifyRejected{401, "用户不存在"}
+			return nil
+		}
+		if user.Status != model.UserStatusActive {
+			rejected = &errVerifyRejected{401, "账号不可用"}
+			return revokeSessions(tx, user.UserID, current.FamilyID)
+		}
+		var next model.RefreshToken
+		sessionID = current.FamilyID
+		var err error
+		refreshToken, next, err = issueRefreshToken(tx, c, user.UserID, current.FamilyID, current.DeviceName)
+		if err != nil {
+			return err
+		}
+		refreshExpires = next.ExpiresAt
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked":     true,
+			"rotated_at":  now,
+			"replaced_by": next.TokenID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken生成失败"})
 		return
 	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+
 	// 3. 生成新的access token
-	accessToken, err := generateAccessToken(claims.UserID)
+	accessToken, err := token.Default().IssueAccess(user.UserID, user.Role, sessionID)
 	if err != nil {
 		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "Token生成失败"})
 		return
 	}
+	token.Default().SetCookies(c.Writer, accessToken, refreshToken, refreshExpires)
 	c.JSON(200, model.Response[model.RefreshTokenResponse]{Success: true, Data: model.RefreshTokenResponse{
 		AccessToken:  accessToken,
-		RefreshToken: refreshTokenStr,
+		RefreshToken: refreshToken,
 	}})
 }
 
 // RevokeRefreshToken godoc
 // @Summary 登出
-// @Description 使refresh token失效（登出）
+// @Description 使refresh token所属的会话失效（登出）并删除令牌 Cookie。请求体未提供时使用 refresh_token Cookie
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param payload body model.RevokeTokenRequest true "登出请求"
+// @Param payload body model.RevokeTokenRequest false "登出请求"
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
-// @Router /api/auth/logout [post]
+// @Router /api/logout [post]
 func RevokeRefreshToken(c *gin.Context) {
 	var req model.RevokeTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: err.Error()})
-		return
-	}
-	db := database.GetDB()
-	result := db.Model(&model.RefreshToken{}).Where("refresh_token = ? AND revoked = false", req.RefreshToken).Update("revoked", true)
-	if result.Error != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
-		return
-	}
-	if result.RowsAffected == 0 {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "无效或已撤销的refresh token"})
-		return
-	}
-	c.JSON(200, model.BaseResponse{Success: true})
-}
-
-// GoogleUserInfo 用于解析Google返回的用户信息
-type GoogleUserInfo struct {
-	Sub           string `json:"sub"`
-	Email         string `json:"email"`
-	EmailVerified string `json:"email_verified"`
-	Name          string `json:"name"`
-	Picture       string `json:"picture"`
-}
-
-func getGoogleUserInfo(idToken string) (*GoogleUserInfo, error) {
-	resp, err := http.Get("https://oauth2.googleapis.com/tokeninfo?id_token=" + idToken)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("invalid google id_token")
-	}
-	var info GoogleUserInfo
-	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		return nil, err
-	}
-	return &info, nil
-}
-
-// GoogleAuth godoc
-// @Summary Google社交登录/注册
-// @Description Google社交登录/注册
-// @Tags Auth
-// @Accept json
-// @Produce json
-// @Param payload body model.GoogleAuthRequest true "Google登录请求"
-// @Success 200 {object} model.Response[model.AuthResponse]
-// @Failure 400 {object} model.BaseResponse
-// @Failure 500 {object} model.BaseResponse
-// @Router /api/auth/google [post]
-func GoogleAuth(c *gin.Context) {
-	var req model.GoogleAuthRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "参数错误: " + err.Error()})
+	if !bindOptionalJSON(c, &req) {
 		return
 	}
-
-	// 验证id_token，获取Google用户信息
-	userInfo, err := getGoogleUserInfo(req.IdToken)
-	if err != nil {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "Google token无效"})
+	if req.RefreshToken = refreshTokenOrCookie(c, req.RefreshToken); req.RefreshToken == "" {
+		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "缺少refresh token"})
 		return
 	}
-	if userInfo.Email == "" || userInfo.Sub == "" {
-		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "Google用户信息不完整"})
-		return
-	}
-
+	token.Default().ClearCookies(c.Writer)
 	db := database.GetDB()
-	var user model.User
-	if err := db.Where("google_id = ?", userInfo.Sub).First(&user).Error; err == nil {
-		// 已存在，直接登录
-		if user.Status != "active" {
-			user.Status = "active"
-			db.Save(&user)
-		}
-	} else {
-		// 不存在，注册
-		user = model.User{
-			Email:    userInfo.Email,
-			GoogleID: userInfo.Sub,
-			Name:     userInfo.Name,
-			Provider: "google",
-			Status:   "active",
-		}
-		if err := db.Create(&user).Error; err != nil {
-			c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "用户注册失败: " + err.Error()})
-			return
-		}
-	}
-
-	accessToken, err := generateAccessToken(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "Token生成失败"})
-		return
-	}
-
-	refreshToken, err := generateRefreshTokenJWT(user.UserID)
-	if err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken生成失败"})
+	var record model.RefreshToken
+	if err := db.Where("token_hash = ? AND revoked = false", hashToken(req.RefreshToken)).First(&record).Error; err != nil {
+		c.JSON(400, model.BaseResponse{Success: false, ErrMessage: "无效或已撤销的refresh token"})
 		return
 	}
-
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-	if err := db.Create(&model.RefreshToken{
-		UserID:       user.UserID,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		Revoked:      false,
-	}).Error; err != nil {
-		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: "RefreshToken存储失败"})
+	if err := revokeSessions(db, record.UserID, record.FamilyID); err != nil {
+		c.JSON(500, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
-
-	c.JSON(200, model.Response[model.AuthResponse]{
-		Success: true,
-		Data: model.AuthResponse{
-			User:         user,
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-		},
-	})
+	c.JSON(200, model.BaseResponse{Success: true})
 }
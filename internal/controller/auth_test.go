@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"travel-ar-backend/internal/model"
+)
+
+func TestLoginRequiresActiveAccount(t *testing.T) {
+	db := setupDB(t)
+	cases := []struct {
+		status  string
+		code    int
+		message string
+	}{
+		{model.UserStatusActive, http.StatusOK, ""},
+		{model.UserStatusPending, http.StatusForbidden, "邮箱尚未验证，请先完成邮箱验证"},
+		{model.UserStatusDisabled, http.StatusForbidden, "账号已停用"},
+		{"locked", http.StatusForbidden, "账号已停用"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.status, func(t *testing.T) {
+			user := createUser(t, db, testEmail(t), "secret123", tc.status)
+			w := serve(t, Login, request{
+				method: http.MethodPost,
+				target: "/api/auth/login",
+				body:   model.LoginRequest{Email: user.Email, Password: "secret123"},
+			})
+			if w.Code != tc.code {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tc.code, w.Body.String())
+			}
+			if got := decode[model.BaseResponse](t, w).ErrMessage; got != tc.message {
+				t.Fatalf("message = %q, want %q", got, tc.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,549 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/campaign"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateCampaign godoc
+// @Summary 新建集章活动
+// @Description 新建集章活动，指定活动设施、时间段与完成规则（all 全部 / any 任意K个 / ordered 按顺序）
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign body model.CampaignReqCreate true "活动信息"
+// @Success 200 {object} model.Response[model.Campaign]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/campaigns [post]
+func CreateCampaign(c *gin.Context) {
+	var req model.CampaignReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !validateCampaign(c, db, req) {
+		return
+	}
+
+	var item model.Campaign
+	applyCampaign(&item, req)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		if err := saveCampaignFacilities(tx, item.CampaignID, req.FacilityIDs); err != nil {
+			return err
+		}
+		return saveCampaignStores(tx, item.CampaignID, req.StoreIDs)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Campaign]{Success: true, Data: item})
+}
+
+// UpdateCampaign godoc
+// @Summary 更新集章活动
+// @Description 更新活动信息，设施列表整体替换
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign body model.CampaignReqEdit true "活动信息"
+// @Success 200 {object} model.Response[model.Campaign]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/campaigns [put]
+func UpdateCampaign(c *gin.Context) {
+	var req model.CampaignReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var item model.Campaign
+	if err := db.First(&item, req.CampaignID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "活动不存在"})
+		return
+	}
+	if !validateCampaign(c, db, req.CampaignReqCreate) {
+		return
+	}
+
+	applyCampaign(&item, req.CampaignReqCreate)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&item).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("campaign_id = ?", item.CampaignID).Delete(&model.CampaignFacility{}).Error; err != nil {
+			return err
+		}
+		if err := saveCampaignFacilities(tx, item.CampaignID, req.FacilityIDs); err != nil {
+			return err
+		}
+		if err := tx.Where("campaign_id = ?", item.CampaignID).Delete(&model.CampaignStore{}).Error; err != nil {
+			return err
+		}
+		return saveCampaignStores(tx, item.CampaignID, req.StoreIDs)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Campaign]{Success: true, Data: item})
+}
+
+// DeleteCampaign godoc
+// @Summary 删除集章活动
+// @Description 删除活动及其设施、合作商铺、奖励记录
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign_id path int true "活动ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/campaigns/{campaign_id} [delete]
+func DeleteCampaign(c *gin.Context) {
+	campaignID, _ := strconv.Atoi(c.Param("campaign_id"))
+	db := database.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("campaign_id = ?", campaignID).Delete(&model.CampaignFacility{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("campaign_id = ?", campaignID).Delete(&model.CampaignStore{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("campaign_id = ?", campaignID).Delete(&model.CampaignReward{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Campaign{}, campaignID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetCampaign godoc
+// @Summary 获取集章活动
+// @Description 获取活动详情及活动设施
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign_id path int true "活动ID"
+// @Success 200 {object} model.Response[model.Campaign]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/campaigns/{campaign_id} [get]
+func GetCampaign(c *gin.Context) {
+	campaignID, _ := strconv.Atoi(c.Param("campaign_id"))
+	db := database.GetDB()
+	var item model.Campaign
+	if err := db.First(&item, campaignID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "活动不存在"})
+		return
+	}
+	fillCampaignFacilities(db, []*model.Campaign{&item})
+	c.JSON(http.StatusOK, model.Response[model.Campaign]{Success: true, Data: item})
+}
+
+// ListCampaigns godoc
+// @Summary 获取集章活动列表
+// @Description 获取活动分页列表，可只查进行中的活动
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param req body model.CampaignReqList true "分页与搜索"
+// @Success 200 {object} model.ListResponse[model.Campaign]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/campaigns/list [post]
+func ListCampaigns(c *gin.Context) {
+	var req model.CampaignReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var campaigns []model.Campaign
+	var total int64
+
+	query := db.Model(&model.Campaign{})
+	if req.Keyword != "" {
+		query = query.Where("campaign_name LIKE ?", "%"+req.Keyword+"%")
+	}
+	if req.OngoingNow {
+		now := time.Now()
+		query = query.Where("is_active = ? AND start_at <= ? AND end_at > ?", true, now, now)
+	}
+	query.Count(&total)
+	query.Order("start_at DESC").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&campaigns)
+
+	list := make([]*model.Campaign, 0, len(campaigns))
+	for i := range campaigns {
+		list = append(list, &campaigns[i])
+	}
+	fillCampaignFacilities(db, list)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.Campaign]{
+		Success: true,
+		Total:   total,
+		List:    campaigns,
+	})
+}
+
+// GetCampaignProgress godoc
+// @Summary 获取我的活动进度
+// @Description 根据当前用户在活动期间的访问记录计算进度；已完成但尚未发放奖励且活动仍启用时补发
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign_id path int true "活动ID"
+// @Success 200 {object} model.Response[model.CampaignProgress]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/campaigns/{campaign_id}/progress [get]
+func GetCampaignProgress(c *gin.Context) {
+	campaignID, _ := strconv.Atoi(c.Param("campaign_id"))
+	db := database.GetDB()
+	var item model.Campaign
+	if err := db.First(&item, campaignID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "活动不存在"})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+	var result model.CampaignProgress
+	err := db.Transaction(func(tx *gorm.DB) error {
+		progress, err := evaluateCampaign(tx, item, userID)
+		if err != nil {
+			return err
+		}
+		result = model.CampaignProgress{
+			CampaignID: item.CampaignID,
+			RuleType:   item.RuleType,
+			Required:   progress.Required,
+			VisitedIDs: progress.Visited,
+			Completed:  progress.Completed,
+		}
+		if progress.NextID != 0 {
+			result.NextFacilityID = &progress.NextID
+		}
+		if !progress.Completed {
+			return nil
+		}
+		if item.IsActive {
+			result.Reward, err = issueCampaignReward(tx, item.CampaignID, userID)
+			return err
+		}
+		// 已停用的活动不再补发，仅返回已发放的奖励
+		var reward model.CampaignReward
+		err = tx.Where("campaign_id = ? AND user_id = ?", item.CampaignID, userID).First(&reward).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err == nil {
+			result.Reward = &reward
+		}
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.CampaignProgress]{Success: true, Data: result})
+}
+
+// ListMyCampaignRewards godoc
+// @Summary 获取我的活动奖励
+// @Description 获取当前用户已获得的活动奖励及兑换码
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ListResponse[model.CampaignReward]
+// @Security ApiKeyAuth
+// @Router /api/campaigns/rewards/mine [get]
+func ListMyCampaignRewards(c *gin.Context) {
+	db := database.GetDB()
+	var rewards []model.CampaignReward
+	db.Where("user_id = ?", c.GetInt("user_id")).Order("issued_at DESC").Find(&rewards)
+	c.JSON(http.StatusOK, model.ListResponse[model.CampaignReward]{
+		Success: true,
+		Total:   int64(len(rewards)),
+		List:    rewards,
+	})
+}
+
+// RedeemMyStoreCampaignReward godoc
+// @Summary 核销活动奖励
+// @Description 活动合作商铺的店主或店员凭兑换码核销奖励，每个兑换码只能核销一次；非本店参与的活动的兑换码按不存在处理
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.CampaignReqRedeem true "兑换码"
+// @Success 200 {object} model.Response[model.CampaignReward]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/campaign_rewards/redeem [post]
+func RedeemMyStoreCampaignReward(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.CampaignReqRedeem
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+
+	var reward model.CampaignReward
+	var rejected *errVerifyRejected
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("coupon_code = ?", req.CouponCode).First(&reward).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				rejected = &errVerifyRejected{http.StatusNotFound, "兑换码不存在"}
+				return nil
+			}
+			return err
+		}
+		// 本店未参与的活动按不存在处理，避免泄露其他活动的兑换码
+		var partner int64
+		if err := tx.Model(&model.CampaignStore{}).
+			Where("campaign_id = ? AND store_id = ?", reward.CampaignID, storeID).Count(&partner).Error; err != nil {
+			return err
+		}
+		if partner == 0 {
+			rejected = &errVerifyRejected{http.StatusNotFound, "兑换码不存在"}
+			return nil
+		}
+		if reward.RedeemedAt != nil {
+			rejected = &errVerifyRejected{http.StatusConflict, "兑换码已核销"}
+			return nil
+		}
+		now := time.Now()
+		operator := c.GetInt("user_id")
+		reward.RedeemedAt = &now
+		reward.RedeemedBy = &operator
+		reward.RedeemedStoreID = &storeID
+		return tx.Model(&reward).Updates(map[string]interface{}{
+			"redeemed_at":       now,
+			"redeemed_by":       operator,
+			"redeemed_store_id": storeID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.CampaignReward]{Success: true, Data: reward})
+}
+
+// GetCampaignStats godoc
+// @Summary 获取活动参与统计
+// @Description 统计活动期间的参与人数、完成人数、核销数及各设施访问人数
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaign_id path int true "活动ID"
+// @Success 200 {object} model.Response[model.CampaignStats]
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/campaigns/{campaign_id}/stats [get]
+func GetCampaignStats(c *gin.Context) {
+	campaignID, _ := strconv.Atoi(c.Param("campaign_id"))
+	db := database.GetDB()
+	var item model.Campaign
+	if err := db.First(&item, campaignID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "活动不存在"})
+		return
+	}
+
+	stats := model.CampaignStats{CampaignID: item.CampaignID, Facilities: []model.CampaignFacilityStat{}}
+	visits := db.Model(&model.VisitHistory{}).
+		Where("is_active = ? AND scan_at >= ? AND scan_at < ?", true, item.StartAt, item.EndAt).
+		Where("facility_id IN (?)", db.Model(&model.CampaignFacility{}).Select("facility_id").Where("campaign_id = ?", item.CampaignID))
+	visits.Session(&gorm.Session{}).Distinct("user_id").Count(&stats.Participants)
+	visits.Session(&gorm.Session{}).Select("facility_id, COUNT(DISTINCT user_id) AS visitors").
+		Group("facility_id").Order("facility_id").Scan(&stats.Facilities)
+	db.Model(&model.CampaignReward{}).Where("campaign_id = ?", item.CampaignID).Count(&stats.Completed)
+	db.Model(&model.CampaignReward{}).Where("campaign_id = ? AND redeemed_at IS NOT NULL", item.CampaignID).Count(&stats.Redeemed)
+
+	c.JSON(http.StatusOK, model.Response[model.CampaignStats]{Success: true, Data: stats})
+}
+
+// validateCampaign 校验活动设施、合作商铺与完成规则，失败时写入响应并返回 false
+func validateCampaign(c *gin.Context, db *gorm.DB, req model.CampaignReqCreate) bool {
+	var count int64
+	db.Model(&model.Facility{}).Where("facility_id IN ?", req.FacilityIDs).Count(&count)
+	if int(count) != len(req.FacilityIDs) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "部分设施不存在"})
+		return false
+	}
+	if len(req.StoreIDs) > 0 {
+		db.Model(&model.Store{}).Where("store_id IN ?", req.StoreIDs).Count(&count)
+		if int(count) != len(req.StoreIDs) {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "部分商铺不存在"})
+			return false
+		}
+	}
+	if req.RuleType == campaign.RuleAny && (req.RequiredCount < 1 || req.RequiredCount > len(req.FacilityIDs)) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "required_count 须在 1 到设施数之间"})
+		return false
+	}
+	return true
+}
+
+func applyCampaign(item *model.Campaign, req model.CampaignReqCreate) {
+	item.CampaignName = req.CampaignName
+	item.Description = req.Description
+	item.StartAt = req.StartAt
+	item.EndAt = req.EndAt
+	item.RuleType = req.RuleType
+	item.RequiredCount = req.RequiredCount
+	item.RewardDescription = req.RewardDescription
+	item.IsActive = req.IsActive == nil || *req.IsActive
+	item.FacilityIDs = req.FacilityIDs
+	item.StoreIDs = req.StoreIDs
+	if item.StoreIDs == nil {
+		item.StoreIDs = []int{}
+	}
+}
+
+func saveCampaignFacilities(tx *gorm.DB, campaignID int, facilityIDs []int) error {
+	rows := make([]model.CampaignFacility, len(facilityIDs))
+	for i, id := range facilityIDs {
+		rows[i] = model.CampaignFacility{CampaignID: campaignID, FacilityID: id, StepOrder: i + 1}
+	}
+	return tx.Create(&rows).Error
+}
+
+func saveCampaignStores(tx *gorm.DB, campaignID int, storeIDs []int) error {
+	if len(storeIDs) == 0 {
+		return nil
+	}
+	rows := make([]model.CampaignStore, len(storeIDs))
+	for i, id := range storeIDs {
+		rows[i] = model.CampaignStore{CampaignID: campaignID, StoreID: id}
+	}
+	return tx.Create(&rows).Error
+}
+
+// fillCampaignFacilities 按顺序填充活动设施ID与合作商铺ID
+func fillCampaignFacilities(db *gorm.DB, campaigns []*model.Campaign) {
+	if len(campaigns) == 0 {
+		return
+	}
+	ids := make([]int, len(campaigns))
+	index := make(map[int]*model.Campaign, len(campaigns))
+	for i, item := range campaigns {
+		ids[i] = item.CampaignID
+		index[item.CampaignID] = item
+		item.FacilityIDs = []int{}
+		item.StoreIDs = []int{}
+	}
+	var rows []model.CampaignFacility
+	db.Where("campaign_id IN ?", ids).Order("campaign_id").Order("step_order").Find(&rows)
+	for _, r := range rows {
+		index[r.CampaignID].FacilityIDs = append(index[r.CampaignID].FacilityIDs, r.FacilityID)
+	}
+	var stores []model.CampaignStore
+	db.Where("campaign_id IN ?", ids).Order("campaign_id").Order("store_id").Find(&stores)
+	for _, r := range stores {
+		index[r.CampaignID].StoreIDs = append(index[r.CampaignID].StoreIDs, r.StoreID)
+	}
+}
+
+// evaluateCampaign 根据用户在活动期间的访问记录计算进度
+func evaluateCampaign(db *gorm.DB, item model.Campaign, userID int) (campaign.Progress, error) {
+	var facilityIDs []int
+	if err := db.Model(&model.CampaignFacility{}).Where("campaign_id = ?", item.CampaignID).
+		Order("step_order").Pluck("facility_id", &facilityIDs).Error; err != nil {
+		return campaign.Progress{}, err
+	}
+	var histories []model.VisitHistory
+	if err := db.Where("user_id = ? AND is_active = ? AND facility_id IN ? AND scan_at >= ? AND scan_at < ?",
+		userID, true, facilityIDs, item.StartAt, item.EndAt).
+		Order("scan_at").Find(&histories).Error; err != nil {
+		return campaign.Progress{}, err
+	}
+	visits := make([]campaign.Visit, len(histories))
+	for i, h := range histories {
+		visits[i] = campaign.Visit{FacilityID: h.FacilityID, At: h.ScanAt}
+	}
+	return campaign.Evaluate(item.RuleType, item.RequiredCount, facilityIDs, visits), nil
+}
+
+// issueCampaignReward 发放活动奖励，已发放时返回原记录
+func issueCampaignReward(tx *gorm.DB, campaignID, userID int) (*model.CampaignReward, error) {
+	reward := model.CampaignReward{
+		CampaignID: campaignID,
+		UserID:     userID,
+		CouponCode: randcode.New(10),
+		IssuedAt:   time.Now(),
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "campaign_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&reward).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("campaign_id = ? AND user_id = ?", campaignID, userID).First(&reward).Error; err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// issueCampaignRewards 用户访问设施后，检查包含该设施的进行中活动，返回本次新完成的奖励
+func issueCampaignRewards(tx *gorm.DB, userID, facilityID int, now time.Time) ([]model.CampaignReward, error) {
+	var campaigns []model.Campaign
+	if err := tx.Where("is_active = ? AND start_at <= ? AND end_at > ?", true, now, now).
+		Where("campaign_id IN (?)", tx.Model(&model.CampaignFacility{}).Select("campaign_id").Where("facility_id = ?", facilityID)).
+		Where("campaign_id NOT IN (?)", tx.Model(&model.CampaignReward{}).Select("campaign_id").Where("user_id = ?", userID)).
+		Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+	rewards := []model.CampaignReward{}
+	for _, item := range campaigns {
+		progress, err := evaluateCampaign(tx, item, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !progress.Completed {
+			continue
+		}
+		reward, err := issueCampaignReward(tx, item.CampaignID, userID)
+		if err != nil {
+			return nil, err
+		}
+		rewards = append(rewards, *reward)
+	}
+	return rewards, nil
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateCampaignKeepsInactive(t *testing.T) {
+	db := setupDB(t)
+	facility := createFacility(t, db)
+	active := false
+	start := time.Now().Truncate(time.Second)
+
+	w := serve(t, CreateCampaign, request{
+		method: http.MethodPost,
+		target: "/api/campaigns",
+		body: model.CampaignReqCreate{
+			CampaignName: "浅草集章",
+			StartAt:      start,
+			EndAt:        start.AddDate(0, 1, 0),
+			RuleType:     "all",
+			FacilityIDs:  []int{facility.FacilityID},
+			IsActive:     &active,
+		},
+		role: model.RoleAdmin,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	created := decode[model.Response[model.Campaign]](t, w).Data
+	t.Cleanup(func() {
+		db.Where("campaign_id = ?", created.CampaignID).Delete(&model.CampaignFacility{})
+		db.Delete(&model.Campaign{}, created.CampaignID)
+	})
+
+	id := strconv.Itoa(created.CampaignID)
+	w = serve(t, GetCampaign, request{
+		method: http.MethodGet,
+		target: "/api/campaigns/" + id,
+		params: gin.Params{{Key: "campaign_id", Value: id}},
+	})
+	if got := decode[model.Response[model.Campaign]](t, w).Data; got.IsActive {
+		t.Fatalf("is_active = true, want false")
+	}
+}
+
+func TestRedeemMyStoreCampaignRewardScopedToPartnerStores(t *testing.T) {
+	db := setupDB(t)
+	partner, other := createStore(t, db), createStore(t, db)
+	operator := createUser(t, db, testEmail(t), "secret123", model.UserStatusActive)
+	visitor := createUser(t, db, testEmail(t), "secret123", model.UserStatusActive)
+	start := time.Now().Truncate(time.Second)
+	item := model.Campaign{CampaignName: "浅草集章", StartAt: start, EndAt: start.AddDate(0, 1, 0), RuleType: "all", IsActive: true}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	reward := model.CampaignReward{CampaignID: item.CampaignID, UserID: visitor.UserID, CouponCode: "T" + strconv.FormatInt(time.Now().UnixNano()%1e12, 10), IssuedAt: start}
+	if err := db.Create(&model.CampaignStore{CampaignID: item.CampaignID, StoreID: partner.StoreID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&reward).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&model.StoreMember{StoreID: other.StoreID, UserID: operator.UserID, MemberRole: model.StoreMemberStaff}).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Where("user_id = ?", operator.UserID).Delete(&model.StoreMember{})
+		db.Where("campaign_id = ?", item.CampaignID).Delete(&model.CampaignReward{})
+		db.Where("campaign_id = ?", item.CampaignID).Delete(&model.CampaignStore{})
+		db.Delete(&model.Campaign{}, item.CampaignID)
+	})
+	redeem := func(storeID int) *httptest.ResponseRecorder {
+		id := strconv.Itoa(storeID)
+		return serve(t, RedeemMyStoreCampaignReward, request{
+			method: http.MethodPost,
+			target: "/api/my/stores/" + id + "/campaign_rewards/redeem",
+			body:   model.CampaignReqRedeem{CouponCode: reward.CouponCode},
+			params: gin.Params{{Key: "store_id", Value: id}},
+			userID: operator.UserID,
+			role:   model.RoleTourist,
+		})
+	}
+
+	// 本店未参与该活动
+	if w := redeem(other.StoreID); w.Code != http.StatusNotFound {
+		t.Fatalf("non-partner store: status = %d, want 404", w.Code)
+	}
+	// 不是合作商铺的成员
+	if w := redeem(partner.StoreID); w.Code != http.StatusForbidden {
+		t.Fatalf("non-member: status = %d, want 403", w.Code)
+	}
+	if err := db.Create(&model.StoreMember{StoreID: partner.StoreID, UserID: operator.UserID, MemberRole: model.StoreMemberStaff}).Error; err != nil {
+		t.Fatal(err)
+	}
+	w := redeem(partner.StoreID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("partner staff: status = %d, body %s", w.Code, w.Body.String())
+	}
+	if got := decode[model.Response[model.CampaignReward]](t, w).Data; got.RedeemedStoreID == nil || *got.RedeemedStoreID != partner.StoreID {
+		t.Fatalf("redeemed_store_id = %v, want %d", got.RedeemedStoreID, partner.StoreID)
+	}
+	if w := redeem(partner.StoreID); w.Code != http.StatusConflict {
+		t.Fatalf("second redeem: status = %d, want 409", w.Code)
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/checkin"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	checkinQRPeriod = 5 * time.Minute  // 二维码轮换周期
+	checkinRadiusM  = 300.0            // 提供 GPS 时允许的最大距离（米）
+	checkinCooldown = 30 * time.Minute // 同一设施重复签到的冷却时间
+)
+
+// checkinSigner 使用环境变量 CHECKIN_SECRET 创建签名器，未配置时返回 nil
+func checkinSigner() *checkin.Signer {
+	secret := os.Getenv("CHECKIN_SECRET")
+	if secret == "" {
+		return nil
+	}
+	return checkin.NewSigner([]byte(secret), checkinQRPeriod)
+}
+
+// GetCheckinQR godoc
+// @Summary 获取设施签到二维码
+// @Description 获取设施当前周期的签名二维码内容，供设施现场的显示终端定期刷新
+// @Tags Checkin
+// @Accept json
+// @Produce json
+// @Param facility_id path int true "设施ID"
+// @Success 200 {object} model.Response[model.CheckinQR]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/checkin/qr/{facility_id} [get]
+func GetCheckinQR(c *gin.Context) {
+	facilityID, _ := strconv.Atoi(c.Param("facility_id"))
+	signer := checkinSigner()
+	if signer == nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "未配置签到密钥"})
+		return
+	}
+	db := database.GetDB()
+	if err := db.Select("facility_id").First(&model.Facility{}, facilityID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "设施不存在"})
+		return
+	}
+	payload, expiresAt := signer.Issue(facilityID, time.Now())
+	c.JSON(http.StatusOK, model.Response[model.CheckinQR]{Success: true, Data: model.CheckinQR{
+		FacilityID: facilityID,
+		Payload:    payload,
+		ExpiresAt:  expiresAt,
+	}})
+}
+
+// ScanCheckin godoc
+// @Summary 扫码签到
+// @Description 校验设施二维码签名后为当前用户创建访问记录，扫码时间取服务器时间；提供 GPS 时校验与设施的距离，冷却时间内重复扫码不会重复记录；完成集章活动时同时发放奖励
+// @Tags Checkin
+// @Accept json
+// @Produce json
+// @Param req body model.CheckinReqScan true "二维码内容与设备位置"
+// @Success 200 {object} model.Response[model.CheckinResult]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/checkin/scan [post]
+func ScanCheckin(c *gin.Context) {
+	var req model.CheckinReqScan
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "经纬度须同时提供"})
+		return
+	}
+	signer := checkinSigner()
+	if signer == nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "未配置签到密钥"})
+		return
+	}
+
+	now := time.Now()
+	facilityID, err := signer.Verify(req.Payload, now)
+	if errors.Is(err, checkin.ErrExpired) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "二维码已过期，请重新扫描"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "二维码无效"})
+		return
+	}
+
+	db := database.GetDB()
+	var facility model.Facility
+	if err := db.First(&facility, facilityID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "设施不存在"})
+		return
+	}
+	if req.Latitude != nil &&
+		geo.Distance(*req.Latitude, *req.Longitude, facility.Latitude, facility.Longitude) > checkinRadiusM {
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "当前位置不在设施附近"})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+	var result model.CheckinResult
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 按用户与设施加事务级锁，防止并发扫码重复记录
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", userID, facilityID).Error; err != nil {
+			return err
+		}
+		err := tx.Where("user_id = ? AND facility_id = ? AND scan_at > ?", userID, facilityID, now.Add(-checkinCooldown)).
+			Order("scan_at DESC").First(&result.History).Error
+		if err == nil {
+			result.Duplicate = true
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		result.History = model.VisitHistory{
+			UserID:     userID,
+			FacilityID: facilityID,
+			ScanAt:     now,
+			IsActive:   true,
+		}
+		if err := tx.Create(&result.History).Error; err != nil {
+			return err
+		}
+		result.Rewards, err = issueCampaignRewards(tx, userID, facilityID, now)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.CheckinResult]{Success: true, Data: result})
+}
@@ -19,6 +19,7 @@ import (
 // @Success 200 {object} model.Response[model.Comment]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/comments [post]
 func CreateComment(c *gin.Context) {
 	var req model.CommentReqCreate
@@ -52,6 +53,7 @@ func CreateComment(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/comments [put]
 func UpdateComment(c *gin.Context) {
 	var req model.CommentReqEdit
@@ -79,6 +81,7 @@ func UpdateComment(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/comments/{comment_id} [delete]
 func DeleteComment(c *gin.Context) {
 	id := c.Param("comment_id")
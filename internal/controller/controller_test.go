@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/dbtest"
+	"travel-ar-backend/internal/mailer"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/token"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var migrateOnce sync.Once
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	token.SetDefault(token.New("test-access-secret", "test-refresh-secret", false))
+	mailer.SetDefault(&mailer.Memory{})
+}
+
+// setupDB 将全局连接指向测试数据库并建表
+func setupDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := dbtest.Open(t)
+	database.DB = db
+	migrateOnce.Do(database.AutoMigrate)
+	return db
+}
+
+// request 描述一次 handler 调用
+type request struct {
+	method string
+	target string
+	body   interface{}
+	params gin.Params
+	userID int    // 写入上下文的 user_id（0 表示未登录）
+	role   string // 写入上下文的 role
+}
+
+// serve 直接调用 handler，返回响应记录
+func serve(t *testing.T, handler gin.HandlerFunc, req request) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	if req.body != nil {
+		if err := json.NewEncoder(&body).Encode(req.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(req.method, req.target, &body)
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = req.params
+	if req.userID != 0 {
+		c.Set("user_id", req.userID)
+	}
+	if req.role != "" {
+		c.Set("role", req.role)
+	}
+	handler(c)
+	return w
+}
+
+// decode 解析响应体
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var out T
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	return out
+}
+
+// createStore 插入一个测试商铺，测试结束后删除
+func createStore(t *testing.T, db *gorm.DB) model.Store {
+	t.Helper()
+	store := model.Store{
+		StoreName:     "テスト食堂",
+		StoreCategory: "restaurant",
+		Location:      "浅草",
+		Address:       "東京都台東区浅草1-1-1",
+		Latitude:      35.7148,
+		Longitude:     139.7967,
+		BusinessHours: "10:00-20:00",
+		PhoneNumber:   "03-0000-0000",
+	}
+	if err := db.Create(&store).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Delete(&model.Store{}, store.StoreID) })
+	return store
+}
+
+// createFacility 插入一个测试设施，测试结束后删除
+func createFacility(t *testing.T, db *gorm.DB) model.Facility {
+	t.Helper()
+	facility := model.Facility{FacilityName: "浅草寺", Location: "浅草", Latitude: 35.7148, Longitude: 139.7967}
+	if err := db.Create(&facility).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Delete(&model.Facility{}, facility.FacilityID) })
+	return facility
+}
+
+// testEmail 返回本次测试独有的邮箱地址
+func testEmail(t *testing.T) string {
+	name := strings.ToLower(strings.NewReplacer("/", "-", " ", "-").Replace(t.Name()))
+	return fmt.Sprintf("%s-%d@example.com", name, time.Now().UnixNano())
+}
+
+// createUser 插入一个邮箱注册的测试用户，测试结束后删除其登录会话与账号
+func createUser(t *testing.T, db *gorm.DB, email, password, status string) model.User {
+	t.Helper()
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := model.User{Email: email, Password: string(hashed), Provider: "email", Status: status, Role: model.RoleTourist}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Where("user_id = ?", user.UserID).Delete(&model.RefreshToken{})
+		db.Delete(&model.User{}, user.UserID)
+	})
+	return user
+}
@@ -0,0 +1,644 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/hours"
+	"travel-ar-backend/pkg/i18n"
+	"travel-ar-backend/pkg/randcode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errCouponRejected 领取或核销条件不满足，status 与 message 直接返回给客户端
+type errCouponRejected struct {
+	status  int
+	message string
+}
+
+func (e *errCouponRejected) Error() string { return e.message }
+
+func rejectCoupon(status int, message string) error {
+	return &errCouponRejected{status: status, message: message}
+}
+
+// CreateCoupon godoc
+// @Summary 新建优惠券
+// @Description 为商铺新建优惠券，可设置有效期、总量与每人限额，以及面向的语言或集章活动
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon body model.CouponReqCreate true "优惠券信息"
+// @Success 200 {object} model.Response[model.Coupon]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/coupons [post]
+func CreateCoupon(c *gin.Context) {
+	var req model.CouponReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if err := db.First(&model.Store{}, req.StoreID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	createCoupon(c, db, req.StoreID, req)
+}
+
+// UpdateCoupon godoc
+// @Summary 更新优惠券
+// @Description 整体更新优惠券信息，所属商铺不可变更
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon body model.CouponReqEdit true "优惠券信息"
+// @Success 200 {object} model.Response[model.Coupon]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/coupons [put]
+func UpdateCoupon(c *gin.Context) {
+	var req model.CouponReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var item model.Coupon
+	if err := db.First(&item, req.CouponID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "优惠券不存在"})
+		return
+	}
+	updateCoupon(c, db, item, req.CouponReqCreate)
+}
+
+// DeleteCoupon godoc
+// @Summary 删除优惠券
+// @Description 删除尚未被领取的优惠券；已有用户领取时请改为停用
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon_id path int true "优惠券ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/coupons/{coupon_id} [delete]
+func DeleteCoupon(c *gin.Context) {
+	couponID, _ := strconv.Atoi(c.Param("coupon_id"))
+	db := database.GetDB()
+	var item model.Coupon
+	if err := db.First(&item, couponID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "优惠券不存在"})
+		return
+	}
+	deleteCoupon(c, db, item)
+}
+
+// GetCoupon godoc
+// @Summary 获取优惠券
+// @Description 获取优惠券详情
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon_id path int true "优惠券ID"
+// @Success 200 {object} model.Response[model.Coupon]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/coupons/{coupon_id} [get]
+func GetCoupon(c *gin.Context) {
+	couponID, _ := strconv.Atoi(c.Param("coupon_id"))
+	db := database.GetDB()
+	var item model.Coupon
+	if err := db.First(&item, couponID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "优惠券不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Coupon]{Success: true, Data: item})
+}
+
+// ListCoupons godoc
+// @Summary 获取可领取的优惠券列表
+// @Description 获取当前有效期内、未领完的优惠券；指定了面向语言的优惠券仅对该语言的请求返回
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param req body model.CouponReqList true "分页与搜索"
+// @Param lang query string false "语言代码，优先于 Accept-Language"
+// @Success 200 {object} model.ListResponse[model.Coupon]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/coupons/list [post]
+func ListCoupons(c *gin.Context) {
+	var req model.CouponReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var coupons []model.Coupon
+	var total int64
+
+	now := time.Now()
+	query := db.Model(&model.Coupon{}).
+		Where("is_active = ? AND valid_from <= ? AND valid_until > ?", true, now, now).
+		Where("total_limit = 0 OR claimed_count < total_limit").
+		Where("target_language IS NULL OR target_language = ?", languageChain(c)[0])
+	if req.StoreID != 0 {
+		query = query.Where("store_id = ?", req.StoreID)
+	}
+	if req.Keyword != "" {
+		query = query.Where("title LIKE ?", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("valid_until").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&coupons)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.Coupon]{
+		Success: true,
+		Total:   total,
+		List:    coupons,
+	})
+}
+
+// ClaimCoupon godoc
+// @Summary 领取优惠券
+// @Description 将优惠券领取到当前用户的券包，生成一次性核销码；受总量、每人限额及面向语言、集章活动的限制
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon_id path int true "优惠券ID"
+// @Param lang query string false "语言代码，优先于 Accept-Language"
+// @Success 200 {object} model.Response[model.UserCoupon]
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/coupons/{coupon_id}/claim [post]
+func ClaimCoupon(c *gin.Context) {
+	couponID, _ := strconv.Atoi(c.Param("coupon_id"))
+	userID := c.GetInt("user_id")
+	lang := languageChain(c)[0]
+	db := database.GetDB()
+
+	var wallet model.UserCoupon
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 锁定优惠券行，保证并发领取时计数不超过上限
+		var coupon model.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, couponID).Error; err != nil {
+			return rejectCoupon(http.StatusNotFound, "优惠券不存在")
+		}
+		now := time.Now()
+		if !coupon.IsActive || now.Before(coupon.ValidFrom) || !now.Before(coupon.ValidUntil) {
+			return rejectCoupon(http.StatusConflict, "优惠券不在有效期内")
+		}
+		if coupon.TargetLanguage != nil && *coupon.TargetLanguage != lang {
+			return rejectCoupon(http.StatusForbidden, "该优惠券不面向当前语言的用户")
+		}
+		if coupon.CampaignID != nil {
+			var count int64
+			tx.Model(&model.CampaignReward{}).Where("campaign_id = ? AND user_id = ?", *coupon.CampaignID, userID).Count(&count)
+			if count == 0 {
+				return rejectCoupon(http.StatusForbidden, "完成指定集章活动后才能领取")
+			}
+		}
+		if coupon.TotalLimit > 0 && coupon.ClaimedCount >= coupon.TotalLimit {
+			return rejectCoupon(http.StatusConflict, "优惠券已领完")
+		}
+		if coupon.PerUserLimit > 0 {
+			var count int64
+			tx.Model(&model.UserCoupon{}).Where("coupon_id = ? AND user_id = ?", couponID, userID).Count(&count)
+			if count >= int64(coupon.PerUserLimit) {
+				return rejectCoupon(http.StatusConflict, "已达到每人领取上限")
+			}
+		}
+
+		wallet = model.UserCoupon{
+			CouponID:   couponID,
+			UserID:     userID,
+			RedeemCode: randcode.New(12),
+			ClaimedAt:  now,
+		}
+		if err := tx.Create(&wallet).Error; err != nil {
+			return err
+		}
+		coupon.ClaimedCount++
+		wallet.Coupon = &coupon
+		return tx.Model(&coupon).UpdateColumn("claimed_count", gorm.Expr("claimed_count + 1")).Error
+	})
+	if err != nil {
+		writeCouponError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.UserCoupon]{Success: true, Data: wallet})
+}
+
+// ListMyCoupons godoc
+// @Summary 获取我的券包
+// @Description 获取当前用户领取的优惠券及核销码，未核销的排在前面
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ListResponse[model.UserCoupon]
+// @Security ApiKeyAuth
+// @Router /api/coupons/wallet [get]
+func ListMyCoupons(c *gin.Context) {
+	db := database.GetDB()
+	var wallet []model.UserCoupon
+	db.Where("user_id = ?", c.GetInt("user_id")).
+		Order("redeemed_at IS NOT NULL").Order("claimed_at DESC").Find(&wallet)
+	fillUserCoupons(db, wallet)
+	c.JSON(http.StatusOK, model.ListResponse[model.UserCoupon]{
+		Success: true,
+		Total:   int64(len(wallet)),
+		List:    wallet,
+	})
+}
+
+// ListMyStoreCoupons godoc
+// @Summary 店主获取优惠券列表
+// @Description 店主或店员获取自己商铺的全部优惠券，含已停用和已过期的
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Success 200 {object} model.ListResponse[model.Coupon]
+// @Failure 403 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons [get]
+func ListMyStoreCoupons(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	var coupons []model.Coupon
+	db.Where("store_id = ?", storeID).Order("valid_until DESC").Find(&coupons)
+	c.JSON(http.StatusOK, model.ListResponse[model.Coupon]{
+		Success: true,
+		Total:   int64(len(coupons)),
+		List:    coupons,
+	})
+}
+
+// CreateMyStoreCoupon godoc
+// @Summary 店主新建优惠券
+// @Description 店主为自己的商铺新建优惠券
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param coupon body model.CouponReqCreate true "优惠券信息"
+// @Success 200 {object} model.Response[model.Coupon]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons [post]
+func CreateMyStoreCoupon(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.CouponReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner) {
+		return
+	}
+	createCoupon(c, db, storeID, req)
+}
+
+// UpdateMyStoreCoupon godoc
+// @Summary 店主更新优惠券
+// @Description 店主整体更新自己商铺的优惠券
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param coupon_id path int true "优惠券ID"
+// @Param coupon body model.CouponReqCreate true "优惠券信息"
+// @Success 200 {object} model.Response[model.Coupon]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons/{coupon_id} [put]
+func UpdateMyStoreCoupon(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	couponID, _ := strconv.Atoi(c.Param("coupon_id"))
+	var req model.CouponReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner) {
+		return
+	}
+	var item model.Coupon
+	if err := db.Where("store_id = ?", storeID).First(&item, couponID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "优惠券不存在"})
+		return
+	}
+	updateCoupon(c, db, item, req)
+}
+
+// DeleteMyStoreCoupon godoc
+// @Summary 店主删除优惠券
+// @Description 店主删除自己商铺尚未被领取的优惠券
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param coupon_id path int true "优惠券ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons/{coupon_id} [delete]
+func DeleteMyStoreCoupon(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	couponID, _ := strconv.Atoi(c.Param("coupon_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner) {
+		return
+	}
+	var item model.Coupon
+	if err := db.Where("store_id = ?", storeID).First(&item, couponID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "优惠券不存在"})
+		return
+	}
+	deleteCoupon(c, db, item)
+}
+
+// RedeemMyStoreCoupon godoc
+// @Summary 店员核销优惠券
+// @Description 店主或店员扫描用户出示的一次性核销码完成核销；在事务中锁定优惠券，保证并发核销不超过总量与每人限额
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.CouponReqRedeem true "核销码"
+// @Success 200 {object} model.Response[model.UserCoupon]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons/redeem [post]
+func RedeemMyStoreCoupon(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.CouponReqRedeem
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+
+	var wallet model.UserCoupon
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("redeem_code = ?", req.RedeemCode).First(&wallet).Error; err != nil {
+			return rejectCoupon(http.StatusNotFound, "核销码无效")
+		}
+		var coupon model.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, wallet.CouponID).Error; err != nil {
+			return err
+		}
+		// 不属于本店的券按无效码处理，避免泄露其他商铺的信息
+		if coupon.StoreID != storeID {
+			return rejectCoupon(http.StatusNotFound, "核销码无效")
+		}
+		if wallet.RedeemedAt != nil {
+			return rejectCoupon(http.StatusConflict, "该券已核销")
+		}
+		now := time.Now()
+		if !coupon.IsActive || now.Before(coupon.ValidFrom) || !now.Before(coupon.ValidUntil) {
+			return rejectCoupon(http.StatusConflict, "优惠券不在有效期内")
+		}
+		if coupon.TotalLimit > 0 && coupon.RedeemedCount >= coupon.TotalLimit {
+			return rejectCoupon(http.StatusConflict, "优惠券核销数已达上限")
+		}
+		if coupon.PerUserLimit > 0 {
+			var count int64
+			tx.Model(&model.UserCoupon{}).
+				Where("coupon_id = ? AND user_id = ? AND redeemed_at IS NOT NULL", coupon.CouponID, wallet.UserID).Count(&count)
+			if count >= int64(coupon.PerUserLimit) {
+				return rejectCoupon(http.StatusConflict, "该用户已达到核销上限")
+			}
+		}
+
+		operator := c.GetInt("user_id")
+		wallet.RedeemedAt = &now
+		wallet.RedeemedBy = &operator
+		if err := tx.Model(&wallet).Updates(map[string]interface{}{"redeemed_at": now, "redeemed_by": operator}).Error; err != nil {
+			return err
+		}
+		coupon.RedeemedCount++
+		wallet.Coupon = &coupon
+		return tx.Model(&coupon).UpdateColumn("redeemed_count", gorm.Expr("redeemed_count + 1")).Error
+	})
+	if err != nil {
+		writeCouponError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.UserCoupon]{Success: true, Data: wallet})
+}
+
+// GetMyStoreCouponReport godoc
+// @Summary 店主获取优惠券核销报表
+// @Description 按优惠券统计指定期间内的领取数与核销数，日期按日本时间计算，结束日期包含当天
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param from query string false "开始日期 YYYY-MM-DD"
+// @Param to query string false "结束日期 YYYY-MM-DD"
+// @Success 200 {object} model.Response[model.CouponRedemptionReport]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/coupons/report [get]
+func GetMyStoreCouponReport(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	report := model.CouponRedemptionReport{StoreID: storeID, Coupons: []model.CouponRedemptionStat{}}
+	for _, p := range []struct {
+		key string
+		dst **time.Time
+	}{{"from", &report.From}, {"to", &report.To}} {
+		v := c.Query(p.key)
+		if v == "" {
+			continue
+		}
+		t, err := time.ParseInLocation(hours.DateLayout, v, hours.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "日期格式错误，应为 YYYY-MM-DD"})
+			return
+		}
+		*p.dst = &t
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+
+	// 领取与核销分别按各自的时间落入统计期间
+	between := func(col string) (string, []interface{}) {
+		cond, args := "TRUE", []interface{}{}
+		if report.From != nil {
+			cond += " AND uc." + col + " >= ?"
+			args = append(args, *report.From)
+		}
+		if report.To != nil {
+			cond += " AND uc." + col + " < ?"
+			args = append(args, report.To.AddDate(0, 0, 1))
+		}
+		return cond, args
+	}
+	claimedCond, claimedArgs := between("claimed_at")
+	redeemedCond, redeemedArgs := between("redeemed_at")
+	args := append(append(claimedArgs, redeemedArgs...), storeID)
+	err := db.Raw(`SELECT c.coupon_id, c.title,
+			COUNT(uc.user_coupon_id) FILTER (WHERE `+claimedCond+`) AS claimed_count,
+			COUNT(uc.redeemed_at) FILTER (WHERE `+redeemedCond+`) AS redeemed_count
+		FROM coupons c
+		LEFT JOIN user_coupons uc ON uc.coupon_id = c.coupon_id
+		WHERE c.store_id = ?
+		GROUP BY c.coupon_id, c.title
+		ORDER BY c.coupon_id`, args...).Scan(&report.Coupons).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	for _, s := range report.Coupons {
+		report.ClaimedCount += s.ClaimedCount
+		report.RedeemedCount += s.RedeemedCount
+	}
+	c.JSON(http.StatusOK, model.Response[model.CouponRedemptionReport]{Success: true, Data: report})
+}
+
+// validateCoupon 校验面向语言与集章活动，失败时写入响应并返回 false
+func validateCoupon(c *gin.Context, db *gorm.DB, req *model.CouponReqCreate) bool {
+	if req.TargetLanguage != nil {
+		lang := i18n.Normalize(*req.TargetLanguage)
+		if lang == "" {
+			req.TargetLanguage = nil
+		} else {
+			req.TargetLanguage = &lang
+		}
+	}
+	if req.CampaignID != nil {
+		if err := db.First(&model.Campaign{}, *req.CampaignID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "集章活动不存在"})
+			return false
+		}
+	}
+	return true
+}
+
+func applyCoupon(item *model.Coupon, req model.CouponReqCreate) {
+	item.Title = req.Title
+	item.DiscountDescription = req.DiscountDescription
+	item.Terms = req.Terms
+	item.ValidFrom = req.ValidFrom
+	item.ValidUntil = req.ValidUntil
+	item.TotalLimit = req.TotalLimit
+	item.PerUserLimit = 1
+	if req.PerUserLimit != nil {
+		item.PerUserLimit = *req.PerUserLimit
+	}
+	item.TargetLanguage = req.TargetLanguage
+	item.CampaignID = req.CampaignID
+	item.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+func createCoupon(c *gin.Context, db *gorm.DB, storeID int, req model.CouponReqCreate) {
+	if !validateCoupon(c, db, &req) {
+		return
+	}
+	item := model.Coupon{StoreID: storeID}
+	applyCoupon(&item, req)
+	if err := db.Create(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Coupon]{Success: true, Data: item})
+}
+
+func updateCoupon(c *gin.Context, db *gorm.DB, item model.Coupon, req model.CouponReqCreate) {
+	if !validateCoupon(c, db, &req) {
+		return
+	}
+	if req.TotalLimit > 0 && req.TotalLimit < item.ClaimedCount {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "总量不能小于已领取数量"})
+		return
+	}
+	applyCoupon(&item, req)
+	// 计数由领取、核销接口维护，这里不覆盖
+	err := db.Model(&item).Select("title", "discount_description", "terms", "valid_from", "valid_until",
+		"total_limit", "per_user_limit", "target_language", "campaign_id", "is_active", "updated_at").Updates(&item).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Coupon]{Success: true, Data: item})
+}
+
+func deleteCoupon(c *gin.Context, db *gorm.DB, item model.Coupon) {
+	result := db.Where("claimed_count = 0").Delete(&item)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, model.BaseResponse{Success: false, ErrMessage: "已有用户领取，请改为停用"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// fillUserCoupons 填充券包中的优惠券信息
+func fillUserCoupons(db *gorm.DB, wallet []model.UserCoupon) {
+	if len(wallet) == 0 {
+		return
+	}
+	ids := make([]int, len(wallet))
+	for i, w := range wallet {
+		ids[i] = w.CouponID
+	}
+	var coupons []model.Coupon
+	db.Where("coupon_id IN ?", ids).Find(&coupons)
+	index := make(map[int]*model.Coupon, len(coupons))
+	for i := range coupons {
+		index[coupons[i].CouponID] = &coupons[i]
+	}
+	for i := range wallet {
+		wallet[i].Coupon = index[wallet[i].CouponID]
+	}
+}
+
+func writeCouponError(c *gin.Context, err error) {
+	var rejected *errCouponRejected
+	if errors.As(err, &rejected) {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+}
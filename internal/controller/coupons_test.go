@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateCouponKeepsZeroValues(t *testing.T) {
+	db := setupDB(t)
+	store := createStore(t, db)
+	unlimited, active := 0, false
+	from := time.Now().Truncate(time.Second)
+
+	w := serve(t, CreateCoupon, request{
+		method: http.MethodPost,
+		target: "/api/coupons",
+		body: model.CouponReqCreate{
+			StoreID:             store.StoreID,
+			Title:               "饮品九折",
+			DiscountDescription: "饮品九折",
+			ValidFrom:           from,
+			ValidUntil:          from.AddDate(0, 1, 0),
+			PerUserLimit:        &unlimited,
+			IsActive:            &active,
+		},
+		role: model.RoleAdmin,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	created := decode[model.Response[model.Coupon]](t, w).Data
+	t.Cleanup(func() { db.Delete(&model.Coupon{}, created.CouponID) })
+
+	id := strconv.Itoa(created.CouponID)
+	w = serve(t, GetCoupon, request{
+		method: http.MethodGet,
+		target: "/api/coupons/" + id,
+		params: gin.Params{{Key: "coupon_id", Value: id}},
+	})
+	got := decode[model.Response[model.Coupon]](t, w).Data
+	if got.PerUserLimit != 0 || got.IsActive {
+		t.Fatalf("per_user_limit = %d, is_active = %v, want 0 and false", got.PerUserLimit, got.IsActive)
+	}
+}
@@ -2,10 +2,13 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 	"travel-ar-backend/internal/model"
 	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // CreateFacility godoc
@@ -18,6 +21,7 @@ import (
 // @Success 200 {object} model.Response[model.Facility]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/facilities [post]
 func CreateFacility(c *gin.Context) {
 	var req model.FacilityCreateRequest
@@ -28,12 +32,14 @@ func CreateFacility(c *gin.Context) {
 
 	db := database.GetDB()
 	facility := model.Facility{
-		FacilityName:    req.FacilityName,
-		Location:        req.Location,
-		DescriptionText: req.DescriptionText,
-		Latitude:        req.Latitude,
-		Longitude:       req.Longitude,
-		PersonID:        req.PersonID,
+		FacilityName:     req.FacilityName,
+		FacilityNameKana: req.FacilityNameKana,
+		FacilityCategory: req.FacilityCategory,
+		Location:         req.Location,
+		DescriptionText:  req.DescriptionText,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
+		PersonID:         req.PersonID,
 	}
 
 	if err := db.Create(&facility).Error; err != nil {
@@ -55,6 +61,7 @@ func CreateFacility(c *gin.Context) {
 // @Success 200 {object} model.Response[model.Facility]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/facilities/{id} [put]
 func UpdateFacility(c *gin.Context) {
 	id := c.Param("id")
@@ -72,6 +79,8 @@ func UpdateFacility(c *gin.Context) {
 	}
 
 	facility.FacilityName = req.FacilityName
+	facility.FacilityNameKana = req.FacilityNameKana
+	facility.FacilityCategory = req.FacilityCategory
 	facility.Location = req.Location
 	facility.DescriptionText = req.DescriptionText
 	facility.Latitude = req.Latitude
@@ -91,11 +100,21 @@ func UpdateFacility(c *gin.Context) {
 // @Param id path int true "设施ID"
 // @Success 200 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/facilities/{id} [delete]
 func DeleteFacility(c *gin.Context) {
-	id := c.Param("id")
+	facilityID, _ := strconv.Atoi(c.Param("id"))
 	db := database.GetDB()
-	if err := db.Delete(&model.Facility{}, id).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteFavorites(tx, model.EntityFacility, facilityID); err != nil {
+			return err
+		}
+		if err := deleteTaggings(tx, model.EntityFacility, facilityID); err != nil {
+			return err
+		}
+		return tx.Delete(&model.Facility{}, facilityID).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
@@ -109,6 +128,7 @@ func DeleteFacility(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "设施ID"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.Response[model.Facility]
 // @Failure 404 {object} model.BaseResponse
 // @Router /api/facilities/{id} [get]
@@ -120,6 +140,8 @@ func GetFacility(c *gin.Context) {
 		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "Not found"})
 		return
 	}
+	localizeFacilities(c, db, []*model.Facility{&facility})
+	fillFacilityFavorites(c, db, []*model.Facility{&facility})
 	c.JSON(http.StatusOK, model.Response[model.Facility]{Success: true, Data: facility})
 }
 
@@ -130,6 +152,7 @@ func GetFacility(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param query body model.FacilityQueryRequest true "分页与搜索"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.ListResponse[model.Facility]
 // @Failure 400 {object} model.BaseResponse
 // @Router /api/facilities/list [post]
@@ -143,16 +166,75 @@ func ListFacilities(c *gin.Context) {
 	var facilities []model.Facility
 	var total int64
 
-	db.Model(&model.Facility{}).
+	filterByTags(db.Model(&model.Facility{}), model.EntityFacility, "facilities.facility_id", query.TagIDs, query.TagMatch).
 		Where("facility_name LIKE ?", "%"+query.Keyword+"%").
 		Count(&total).
 		Limit(query.PageSize).
 		Offset((query.Page - 1) * query.PageSize).
 		Find(&facilities)
 
+	list := make([]*model.Facility, 0, len(facilities))
+	for i := range facilities {
+		list = append(list, &facilities[i])
+	}
+	localizeFacilities(c, db, list)
+	fillFacilityFavorites(c, db, list)
+
 	c.JSON(http.StatusOK, model.ListResponse[model.Facility]{
 		Total:   total,
 		List:    facilities,
 		Success: true,
 	})
 }
+
+// NearbyFacilities godoc
+// @Summary 附近设施
+// @Description 按中心点与半径查询设施，按距离由近到远排序
+// @Tags Facilities
+// @Accept json
+// @Produce json
+// @Param req body model.FacilityReqNearby true "中心点、半径与分类"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.ListResponse[model.FacilityNearby]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/facilities/nearby [post]
+func NearbyFacilities(c *gin.Context) {
+	var req model.FacilityReqNearby
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	lat, lng := *req.Latitude, *req.Longitude
+	db := database.GetDB()
+	minLat, minLng, maxLat, maxLng := geo.BoundingBox(lat, lng, req.RadiusM)
+	inner := db.Model(&model.Facility{}).
+		Select("facilities.*, "+geo.SQLDistance("latitude", "longitude")+" AS distance_m", lat, lat, lng).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng)
+	if req.Category != "" {
+		inner = inner.Where("facility_category = ?", req.Category)
+	}
+
+	var facilities []model.FacilityNearby
+	if err := db.Table("(?) AS f", inner).
+		Where("distance_m <= ?", req.RadiusM).
+		Order("distance_m").
+		Limit(nearbyLimit(req.Limit)).
+		Find(&facilities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	list := make([]*model.Facility, 0, len(facilities))
+	for i := range facilities {
+		list = append(list, &facilities[i].Facility)
+	}
+	localizeFacilities(c, db, list)
+	fillFacilityFavorites(c, db, list)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.FacilityNearby]{
+		Total:   int64(len(facilities)),
+		List:    facilities,
+		Success: true,
+	})
+}
@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddFavorite godoc
+// @Summary 添加收藏
+// @Description 收藏商铺、设施或文章；重复收藏不报错
+// @Tags Favorites
+// @Accept json
+// @Produce json
+// @Param req body model.FavoriteReqCreate true "收藏对象"
+// @Success 200 {object} model.Response[model.Favorite]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/favorites [post]
+func AddFavorite(c *gin.Context) {
+	var req model.FavoriteReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !entityExists(db, req.FavoritableType, req.FavoritableID) {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "收藏对象不存在"})
+		return
+	}
+
+	favorite := model.Favorite{
+		UserID:          c.GetInt("user_id"),
+		FavoritableType: req.FavoritableType,
+		FavoritableID:   req.FavoritableID,
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "favoritable_type"}, {Name: "favoritable_id"}},
+		DoNothing: true,
+	}).Create(&favorite).Error
+	if err == nil {
+		err = db.Where("user_id = ? AND favoritable_type = ? AND favoritable_id = ?",
+			favorite.UserID, favorite.FavoritableType, favorite.FavoritableID).First(&favorite).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Favorite]{Success: true, Data: favorite})
+}
+
+// RemoveFavorite godoc
+// @Summary 取消收藏
+// @Description 取消当前用户对指定对象的收藏；未收藏时同样返回成功
+// @Tags Favorites
+// @Accept json
+// @Produce json
+// @Param favoritable_type path string true "实体类型（Store / Facility / Article）"
+// @Param favoritable_id path int true "实体ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/favorites/{favoritable_type}/{favoritable_id} [delete]
+func RemoveFavorite(c *gin.Context) {
+	favoritableID, _ := strconv.Atoi(c.Param("favoritable_id"))
+	db := database.GetDB()
+	err := db.Where("user_id = ? AND favoritable_type = ? AND favoritable_id = ?",
+		c.GetInt("user_id"), c.Param("favoritable_type"), favoritableID).Delete(&model.Favorite{}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// ListMyFavorites godoc
+// @Summary 获取我的收藏
+// @Description 按收藏时间倒序获取当前用户的收藏，附带收藏对象详情
+// @Tags Favorites
+// @Accept json
+// @Produce json
+// @Param req body model.FavoriteReqList true "分页与类型"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.ListResponse[model.Favorite]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/favorites/mine [post]
+func ListMyFavorites(c *gin.Context) {
+	var req model.FavoriteReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var favorites []model.Favorite
+	var total int64
+
+	query := db.Model(&model.Favorite{}).Where("user_id = ?", c.GetInt("user_id"))
+	if req.FavoritableType != "" {
+		query = query.Where("favoritable_type = ?", req.FavoritableType)
+	}
+	query.Count(&total)
+	query.Order("created_at DESC").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&favorites)
+	fillFavoriteTargets(c, db, favorites)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.Favorite]{
+		Success: true,
+		Total:   total,
+		List:    favorites,
+	})
+}
+
+// favoriteTarget 待填充收藏状态的实体
+type favoriteTarget struct {
+	id        int
+	favorited *bool
+	count     *int64
+}
+
+// fillFavorites 填充实体的收藏人数，以及当前登录用户是否已收藏
+func fillFavorites(c *gin.Context, db *gorm.DB, entityType string, targets []favoriteTarget) {
+	if len(targets) == 0 {
+		return
+	}
+	ids := make([]int, len(targets))
+	for i, t := range targets {
+		ids[i] = t.id
+	}
+	var rows []struct {
+		FavoritableID int
+		Total         int64
+		Mine          bool
+	}
+	db.Model(&model.Favorite{}).
+		Select("favoritable_id, COUNT(*) AS total, BOOL_OR(user_id = ?) AS mine", c.GetInt("user_id")).
+		Where("favoritable_type = ? AND favoritable_id IN ?", entityType, ids).
+		Group("favoritable_id").Scan(&rows)
+	index := make(map[int]int, len(rows))
+	for i, r := range rows {
+		index[r.FavoritableID] = i
+	}
+	for _, t := range targets {
+		if i, ok := index[t.id]; ok {
+			*t.count = rows[i].Total
+			*t.favorited = rows[i].Mine
+		}
+	}
+}
+
+func fillStoreFavorites(c *gin.Context, db *gorm.DB, stores []*model.Store) {
+	targets := make([]favoriteTarget, len(stores))
+	for i, s := range stores {
+		targets[i] = favoriteTarget{s.StoreID, &s.IsFavorited, &s.FavoriteCount}
+	}
+	fillFavorites(c, db, model.EntityStore, targets)
+}
+
+func fillFacilityFavorites(c *gin.Context, db *gorm.DB, facilities []*model.Facility) {
+	targets := make([]favoriteTarget, len(facilities))
+	for i, f := range facilities {
+		targets[i] = favoriteTarget{f.FacilityID, &f.IsFavorited, &f.FavoriteCount}
+	}
+	fillFavorites(c, db, model.EntityFacility, targets)
+}
+
+func fillArticleFavorites(c *gin.Context, db *gorm.DB, articles []model.Article) {
+	targets := make([]favoriteTarget, len(articles))
+	for i := range articles {
+		a := &articles[i]
+		targets[i] = favoriteTarget{a.ArticleID, &a.IsFavorited, &a.FavoriteCount}
+	}
+	fillFavorites(c, db, model.EntityArticle, targets)
+}
+
+// fillFavoriteTargets 按类型批量加载收藏对象详情
+func fillFavoriteTargets(c *gin.Context, db *gorm.DB, favorites []model.Favorite) {
+	ids := map[string][]int{}
+	for _, f := range favorites {
+		ids[f.FavoritableType] = append(ids[f.FavoritableType], f.FavoritableID)
+	}
+	targets := loadTargets(c, db, ids)
+	for i := range favorites {
+		favorites[i].Target = targets[favorites[i].FavoritableType][favorites[i].FavoritableID]
+	}
+}
+
+// loadTargets 按实体类型批量读取商铺、设施、文章详情（已本地化并填充收藏状态）
+// 返回 实体类型 → 实体ID → 实体指针
+func loadTargets(c *gin.Context, db *gorm.DB, ids map[string][]int) map[string]map[int]interface{} {
+	targets := map[string]map[int]interface{}{}
+	if len(ids[model.EntityStore]) > 0 {
+		var stores []model.Store
+		db.Where("store_id IN ?", ids[model.EntityStore]).Find(&stores)
+		list := make([]*model.Store, len(stores))
+		targets[model.EntityStore] = map[int]interface{}{}
+		for i := range stores {
+			list[i] = &stores[i]
+			targets[model.EntityStore][stores[i].StoreID] = list[i]
+		}
+		localizeStores(c, db, list)
+		fillStoreFavorites(c, db, list)
+	}
+	if len(ids[model.EntityFacility]) > 0 {
+		var facilities []model.Facility
+		db.Where("facility_id IN ?", ids[model.EntityFacility]).Find(&facilities)
+		list := make([]*model.Facility, len(facilities))
+		targets[model.EntityFacility] = map[int]interface{}{}
+		for i := range facilities {
+			list[i] = &facilities[i]
+			targets[model.EntityFacility][facilities[i].FacilityID] = list[i]
+		}
+		localizeFacilities(c, db, list)
+		fillFacilityFavorites(c, db, list)
+	}
+	if len(ids[model.EntityArticle]) > 0 {
+		var articles []model.Article
+		db.Where("article_id IN ?", ids[model.EntityArticle]).Find(&articles)
+		localizeArticles(c, db, articles)
+		fillArticleFavorites(c, db, articles)
+		targets[model.EntityArticle] = map[int]interface{}{}
+		for i := range articles {
+			targets[model.EntityArticle][articles[i].ArticleID] = &articles[i]
+		}
+	}
+	return targets
+}
+
+// entityExists 判断多态关联的目标实体是否存在
+func entityExists(db *gorm.DB, entityType string, id int) bool {
+	entity, ok := model.EntityTables[entityType]
+	if !ok {
+		return false
+	}
+	var count int64
+	db.Table(entity.Table).Where(entity.PrimaryKey+" = ?", id).Count(&count)
+	return count > 0
+}
+
+// deleteFavorites 删除指向某实体的全部收藏，在删除实体时调用
+func deleteFavorites(tx *gorm.DB, entityType string, id int) error {
+	return tx.Where("favoritable_type = ? AND favoritable_id = ?", entityType, id).Delete(&model.Favorite{}).Error
+}
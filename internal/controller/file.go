@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -20,6 +21,7 @@ import (
 // @Success 200 {object} model.Response[model.File]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/files [post]
 func CreateFile(c *gin.Context) {
 	var req model.FileReqCreate
@@ -29,12 +31,13 @@ func CreateFile(c *gin.Context) {
 	}
 
 	file := model.File{
-		FileName:  req.FileName,
-		FileType:  req.FileType,
-		FileSize:  req.FileSize,
-		FileData:  req.FileData,
-		Location:  req.Location,
-		RelatedID: req.RelatedID,
+		FileName:    req.FileName,
+		FileType:    req.FileType,
+		FileSize:    req.FileSize,
+		FileData:    req.FileData,
+		Location:    req.Location,
+		RelatedID:   req.RelatedID,
+		RelatedType: req.RelatedType,
 	}
 	db := database.GetDB()
 	if err := db.Create(&file).Error; err != nil {
@@ -54,6 +57,7 @@ func CreateFile(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/files [put]
 func UpdateFile(c *gin.Context) {
 	var req model.FileReqEdit
@@ -81,6 +85,7 @@ func UpdateFile(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/files/{file_id} [delete]
 func DeleteFile(c *gin.Context) {
 	id := c.Param("file_id")
@@ -116,6 +121,46 @@ func GetFile(c *gin.Context) {
 	c.JSON(http.StatusOK, model.Response[model.File]{Success: true, Data: file})
 }
 
+// GetFileContent godoc
+// @Summary 下载文件内容
+// @Description 以原始二进制返回文件内容。JPEG、PNG、WebP、GIF 图片按原类型内联返回，其余类型（含 SVG、HTML 及 3D 模型）一律作为附件以 application/octet-stream 返回，避免上传内容在 API 域名下执行脚本
+// @Tags Files
+// @Produce octet-stream
+// @Param file_id path int true "文件ID"
+// @Success 200 {file} file
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/files/{file_id}/content [get]
+func GetFileContent(c *gin.Context) {
+	fileID, _ := strconv.Atoi(c.Param("file_id"))
+	db := database.GetDB()
+	var file model.File
+	if err := db.First(&file, fileID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "文件不存在"})
+		return
+	}
+	// 文件类型由上传者提供，不可信：只有位图按原类型内联返回，并禁止浏览器嗅探与执行脚本
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+	contentType := file.FileType
+	if !rasterImageTypes[contentType] {
+		contentType = "application/octet-stream"
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName})
+		if disposition == "" {
+			disposition = "attachment"
+		}
+		c.Header("Content-Disposition", disposition)
+	}
+	c.Data(http.StatusOK, contentType, file.FileData)
+}
+
+// rasterImageTypes 允许内联返回的图片类型，不含可携带脚本的 SVG
+var rasterImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
 // ListFiles godoc
 // @Summary 获取文件列表
 // @Description 获取文件分页列表
@@ -0,0 +1,864 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
+	"travel-ar-backend/pkg/hours"
+	"travel-ar-backend/pkg/randcode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// shareTokenLength 分享令牌长度，约 118 位熵
+const shareTokenLength = 24
+
+// CreateItinerary godoc
+// @Summary 新建行程
+// @Description 新建行程并生成指定天数的空白行程日
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param req body model.ItineraryReqCreate true "行程信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries [post]
+func CreateItinerary(c *gin.Context) {
+	var req model.ItineraryReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	startDate, err := parseItineraryDate(req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if req.DayCount == 0 {
+		req.DayCount = 1
+	}
+
+	db := database.GetDB()
+	item := model.Itinerary{
+		UserID:      c.GetInt("user_id"),
+		Title:       req.Title,
+		Description: req.Description,
+		StartDate:   startDate,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		days := make([]model.ItineraryDay, req.DayCount)
+		for i := range days {
+			days[i] = model.ItineraryDay{ItineraryID: item.ItineraryID, DayIndex: i + 1}
+		}
+		return tx.Create(&days).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	loadItineraryDetail(c, db, &item)
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// UpdateItinerary godoc
+// @Summary 更新行程
+// @Description 更新行程标题、说明与开始日期
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param req body model.ItineraryReqEdit true "行程信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries [put]
+func UpdateItinerary(c *gin.Context) {
+	var req model.ItineraryReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var item model.Itinerary
+	if err := db.Where("user_id = ?", c.GetInt("user_id")).First(&item, req.ItineraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "行程不存在"})
+		return
+	}
+	startDate, err := parseItineraryDate(req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	item.Title = req.Title
+	item.Description = req.Description
+	item.StartDate = startDate
+	if err := db.Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	loadItineraryDetail(c, db, &item)
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// DeleteItinerary godoc
+// @Summary 删除行程
+// @Description 删除行程及其全部行程日与停留点
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id} [delete]
+func DeleteItinerary(c *gin.Context) {
+	db := database.GetDB()
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		dayIDs := tx.Model(&model.ItineraryDay{}).Select("day_id").Where("itinerary_id = ?", item.ItineraryID)
+		if err := tx.Where("day_id IN (?)", dayIDs).Delete(&model.ItineraryStop{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("itinerary_id = ?", item.ItineraryID).Delete(&model.ItineraryDay{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&item).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetItinerary godoc
+// @Summary 获取行程详情
+// @Description 获取自己的行程，含各天停留点及直线距离
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id} [get]
+func GetItinerary(c *gin.Context) {
+	db := database.GetDB()
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return
+	}
+	loadItineraryDetail(c, db, &item)
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// ListMyItineraries godoc
+// @Summary 获取我的行程列表
+// @Description 按更新时间倒序获取当前用户的行程（不含行程日明细）
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param req body model.ItineraryReqList true "分页与搜索"
+// @Success 200 {object} model.ListResponse[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/mine [post]
+func ListMyItineraries(c *gin.Context) {
+	var req model.ItineraryReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var items []model.Itinerary
+	var total int64
+
+	query := db.Model(&model.Itinerary{}).Where("user_id = ?", c.GetInt("user_id"))
+	if req.Keyword != "" {
+		query = query.Where("title LIKE ?", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("updated_at DESC").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&items)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.Itinerary]{
+		Success: true,
+		Total:   total,
+		List:    items,
+	})
+}
+
+// AddItineraryDay godoc
+// @Summary 新增行程日
+// @Description 在行程末尾追加一天
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param req body model.ItineraryDayReqEdit true "行程日信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days [post]
+func AddItineraryDay(c *gin.Context) {
+	var req model.ItineraryDayReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return
+	}
+	var last int
+	db.Model(&model.ItineraryDay{}).Where("itinerary_id = ?", item.ItineraryID).
+		Select("COALESCE(MAX(day_index), 0)").Scan(&last)
+	day := model.ItineraryDay{ItineraryID: item.ItineraryID, DayIndex: last + 1, Title: req.Title}
+	if err := db.Create(&day).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// UpdateItineraryDay godoc
+// @Summary 更新行程日
+// @Description 更新行程日标题
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param req body model.ItineraryDayReqEdit true "行程日信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id} [put]
+func UpdateItineraryDay(c *gin.Context) {
+	var req model.ItineraryDayReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	if err := db.Model(&day).Update("title", req.Title).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// DeleteItineraryDay godoc
+// @Summary 删除行程日
+// @Description 删除行程日及其停留点，之后的行程日依次前移
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id} [delete]
+func DeleteItineraryDay(c *gin.Context) {
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day_id = ?", day.DayID).Delete(&model.ItineraryStop{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&day).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ItineraryDay{}).
+			Where("itinerary_id = ? AND day_index > ?", item.ItineraryID, day.DayIndex).
+			UpdateColumn("day_index", gorm.Expr("day_index - 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// DuplicateItineraryDay godoc
+// @Summary 复制行程日
+// @Description 复制行程日及其停留点，插入到原行程日之后
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/duplicate [post]
+func DuplicateItineraryDay(c *gin.Context) {
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ItineraryDay{}).
+			Where("itinerary_id = ? AND day_index > ?", item.ItineraryID, day.DayIndex).
+			UpdateColumn("day_index", gorm.Expr("day_index + 1")).Error; err != nil {
+			return err
+		}
+		return copyItineraryDay(tx, day, item.ItineraryID, day.DayIndex+1)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// AddItineraryStop godoc
+// @Summary 新增停留点
+// @Description 在行程日中新增商铺或设施停留点，可指定插入位置
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param req body model.ItineraryStopReqCreate true "停留点信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/stops [post]
+func AddItineraryStop(c *gin.Context) {
+	var req model.ItineraryStopReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	stop := model.ItineraryStop{
+		DayID:        day.DayID,
+		StopType:     req.StopType,
+		TargetID:     req.TargetID,
+		PlannedStart: req.PlannedStart,
+		PlannedEnd:   req.PlannedEnd,
+		Note:         req.Note,
+	}
+	if err := validateItineraryStop(db, &stop); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		tx.Model(&model.ItineraryStop{}).Where("day_id = ?", day.DayID).Count(&count)
+		stop.StopOrder = int(count) + 1
+		if req.Position > 0 && req.Position < stop.StopOrder {
+			stop.StopOrder = req.Position
+			if err := tx.Model(&model.ItineraryStop{}).
+				Where("day_id = ? AND stop_order >= ?", day.DayID, stop.StopOrder).
+				UpdateColumn("stop_order", gorm.Expr("stop_order + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&stop).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// UpdateItineraryStop godoc
+// @Summary 更新停留点
+// @Description 更新停留点的计划时间与备注，未传的字段保持不变
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param stop_id path int true "停留点ID"
+// @Param req body model.ItineraryStopReqEdit true "停留点信息"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/stops/{stop_id} [put]
+func UpdateItineraryStop(c *gin.Context) {
+	var req model.ItineraryStopReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, stop, ok := ownItineraryStop(c, db)
+	if !ok {
+		return
+	}
+	if req.PlannedStart != nil {
+		stop.PlannedStart = req.PlannedStart
+	}
+	if req.PlannedEnd != nil {
+		stop.PlannedEnd = req.PlannedEnd
+	}
+	if req.Note != nil {
+		stop.Note = *req.Note
+	}
+	if err := validateItineraryStop(db, &stop); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if err := db.Save(&stop).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// DeleteItineraryStop godoc
+// @Summary 删除停留点
+// @Description 删除停留点，之后的停留点依次前移
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param stop_id path int true "停留点ID"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/stops/{stop_id} [delete]
+func DeleteItineraryStop(c *gin.Context) {
+	db := database.GetDB()
+	item, stop, ok := ownItineraryStop(c, db)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&stop).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ItineraryStop{}).
+			Where("day_id = ? AND stop_order > ?", stop.DayID, stop.StopOrder).
+			UpdateColumn("stop_order", gorm.Expr("stop_order - 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// ReorderItineraryStops godoc
+// @Summary 调整停留点顺序
+// @Description 按传入的停留点ID顺序重排当天的全部停留点
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param req body model.ItineraryStopReqReorder true "停留点ID顺序"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/stops/order [put]
+func ReorderItineraryStops(c *gin.Context) {
+	var req model.ItineraryStopReqReorder
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	var current []int
+	db.Model(&model.ItineraryStop{}).Where("day_id = ?", day.DayID).Pluck("stop_id", &current)
+	if !sameIDSet(current, req.StopIDs) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "须包含当天全部停留点且不能重复"})
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.StopIDs {
+			if err := tx.Model(&model.ItineraryStop{}).Where("stop_id = ?", id).
+				UpdateColumn("stop_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+// ShareItinerary godoc
+// @Summary 分享行程
+// @Description 生成只读分享令牌；已分享时返回原令牌
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/share [post]
+func ShareItinerary(c *gin.Context) {
+	db := database.GetDB()
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return
+	}
+	if item.ShareToken == nil {
+		token := randcode.New(shareTokenLength)
+		if err := db.Model(&item).Update("share_token", token).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		item.ShareToken = &token
+	}
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// UnshareItinerary godoc
+// @Summary 取消分享行程
+// @Description 作废分享令牌，原分享链接随即失效
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/share [delete]
+func UnshareItinerary(c *gin.Context) {
+	db := database.GetDB()
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return
+	}
+	if err := db.Model(&item).Update("share_token", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetSharedItinerary godoc
+// @Summary 查看分享的行程
+// @Description 通过分享令牌只读查看行程，无需登录
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param share_token path string true "分享令牌"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/itineraries/shared/{share_token} [get]
+func GetSharedItinerary(c *gin.Context) {
+	db := database.GetDB()
+	var item model.Itinerary
+	if err := db.Where("share_token = ?", c.Param("share_token")).First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "分享链接无效"})
+		return
+	}
+	loadItineraryDetail(c, db, &item)
+	// 只读视图不暴露分享令牌以外的归属信息
+	item.UserID = 0
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// CloneItinerary godoc
+// @Summary 复制分享的行程
+// @Description 将他人分享的行程连同行程日、停留点复制为自己的行程
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param share_token path string true "分享令牌"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/shared/{share_token}/clone [post]
+func CloneItinerary(c *gin.Context) {
+	db := database.GetDB()
+	var src model.Itinerary
+	if err := db.Where("share_token = ?", c.Param("share_token")).First(&src).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "分享链接无效"})
+		return
+	}
+	item := model.Itinerary{
+		UserID:      c.GetInt("user_id"),
+		Title:       src.Title,
+		Description: src.Description,
+		StartDate:   src.StartDate,
+		ClonedFrom:  &src.ItineraryID,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		var days []model.ItineraryDay
+		if err := tx.Where("itinerary_id = ?", src.ItineraryID).Order("day_index").Find(&days).Error; err != nil {
+			return err
+		}
+		for _, day := range days {
+			if err := copyItineraryDay(tx, day, item.ItineraryID, day.DayIndex); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	loadItineraryDetail(c, db, &item)
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// ownItinerary 读取路径中属于当前用户的行程，不存在时写入 404
+func ownItinerary(c *gin.Context, db *gorm.DB) (model.Itinerary, bool) {
+	itineraryID, _ := strconv.Atoi(c.Param("itinerary_id"))
+	var item model.Itinerary
+	if err := db.Where("user_id = ?", c.GetInt("user_id")).First(&item, itineraryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "行程不存在"})
+		return item, false
+	}
+	return item, true
+}
+
+// ownItineraryDay 读取路径中属于当前用户行程的行程日
+func ownItineraryDay(c *gin.Context, db *gorm.DB) (model.Itinerary, model.ItineraryDay, bool) {
+	var day model.ItineraryDay
+	item, ok := ownItinerary(c, db)
+	if !ok {
+		return item, day, false
+	}
+	dayID, _ := strconv.Atoi(c.Param("day_id"))
+	if err := db.Where("itinerary_id = ?", item.ItineraryID).First(&day, dayID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "行程日不存在"})
+		return item, day, false
+	}
+	return item, day, true
+}
+
+// ownItineraryStop 读取路径中属于当前用户行程日的停留点
+func ownItineraryStop(c *gin.Context, db *gorm.DB) (model.Itinerary, model.ItineraryStop, bool) {
+	var stop model.ItineraryStop
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return item, stop, false
+	}
+	stopID, _ := strconv.Atoi(c.Param("stop_id"))
+	if err := db.Where("day_id = ?", day.DayID).First(&stop, stopID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "停留点不存在"})
+		return item, stop, false
+	}
+	return item, stop, true
+}
+
+func respondItinerary(c *gin.Context, db *gorm.DB, item model.Itinerary) {
+	loadItineraryDetail(c, db, &item)
+	c.JSON(http.StatusOK, model.Response[model.Itinerary]{Success: true, Data: item})
+}
+
+// parseItineraryDate 解析 YYYY-MM-DD，nil 或空字符串返回 nil
+func parseItineraryDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(hours.DateLayout, *s)
+	if err != nil {
+		return nil, errors.New("日期格式应为 YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+// validateItineraryStop 校验停留点对象存在以及计划时刻格式，空字符串的时刻视为未设置
+func validateItineraryStop(db *gorm.DB, stop *model.ItineraryStop) error {
+	if !entityExists(db, stop.StopType, stop.TargetID) {
+		return errors.New("停留点对象不存在")
+	}
+	var start, end = -1, -1
+	for _, p := range []struct {
+		v   **string
+		dst *int
+	}{{&stop.PlannedStart, &start}, {&stop.PlannedEnd, &end}} {
+		if *p.v == nil || **p.v == "" {
+			*p.v = nil
+			continue
+		}
+		m, err := hours.ParseClock(**p.v)
+		if err != nil {
+			return err
+		}
+		*p.dst = m
+	}
+	if start >= 0 && end >= 0 && end < start {
+		return errors.New("离开时间不能早于到达时间")
+	}
+	return nil
+}
+
+// copyItineraryDay 将行程日及其停留点复制到指定行程的指定位置
+func copyItineraryDay(tx *gorm.DB, src model.ItineraryDay, itineraryID, dayIndex int) error {
+	day := model.ItineraryDay{ItineraryID: itineraryID, DayIndex: dayIndex, Title: src.Title}
+	if err := tx.Create(&day).Error; err != nil {
+		return err
+	}
+	var stops []model.ItineraryStop
+	if err := tx.Where("day_id = ?", src.DayID).Order("stop_order").Find(&stops).Error; err != nil {
+		return err
+	}
+	if len(stops) == 0 {
+		return nil
+	}
+	for i := range stops {
+		stops[i].StopID = 0
+		stops[i].DayID = day.DayID
+	}
+	return tx.Create(&stops).Error
+}
+
+// loadItineraryDetail 填充行程日、停留点的名称坐标，以及日期和直线距离
+func loadItineraryDetail(c *gin.Context, db *gorm.DB, item *model.Itinerary) {
+	db.Where("itinerary_id = ?", item.ItineraryID).Order("day_index").Find(&item.Days)
+	if len(item.Days) == 0 {
+		return
+	}
+	dayIDs := make([]int, len(item.Days))
+	for i := range item.Days {
+		dayIDs[i] = item.Days[i].DayID
+	}
+	var stops []model.ItineraryStop
+	db.Where("day_id IN ?", dayIDs).Order("day_id").Order("stop_order").Find(&stops)
+
+	ids := map[string][]int{}
+	for _, s := range stops {
+		ids[s.StopType] = append(ids[s.StopType], s.TargetID)
+	}
+	type place struct {
+		name     string
+		lat, lng float64
+	}
+	places := map[string]map[int]place{model.EntityStore: {}, model.EntityFacility: {}}
+	if len(ids[model.EntityStore]) > 0 {
+		var stores []model.Store
+		db.Where("store_id IN ?", ids[model.EntityStore]).Find(&stores)
+		list := make([]*model.Store, len(stores))
+		for i := range stores {
+			list[i] = &stores[i]
+		}
+		localizeStores(c, db, list)
+		for _, s := range stores {
+			places[model.EntityStore][s.StoreID] = place{s.StoreName, s.Latitude, s.Longitude}
+		}
+	}
+	if len(ids[model.EntityFacility]) > 0 {
+		var facilities []model.Facility
+		db.Where("facility_id IN ?", ids[model.EntityFacility]).Find(&facilities)
+		list := make([]*model.Facility, len(facilities))
+		for i := range facilities {
+			list[i] = &facilities[i]
+		}
+		localizeFacilities(c, db, list)
+		for _, f := range facilities {
+			places[model.EntityFacility][f.FacilityID] = place{f.FacilityName, f.Latitude, f.Longitude}
+		}
+	}
+
+	byDay := make(map[int][]model.ItineraryStop, len(item.Days))
+	located := make(map[int]bool, len(stops))
+	for _, s := range stops {
+		if p, ok := places[s.StopType][s.TargetID]; ok {
+			s.Name, s.Latitude, s.Longitude = p.name, p.lat, p.lng
+			located[s.StopID] = true
+		}
+		byDay[s.DayID] = append(byDay[s.DayID], s)
+	}
+	item.TotalDistanceM = 0
+	for i := range item.Days {
+		day := &item.Days[i]
+		day.Stops = byDay[day.DayID]
+		if day.Stops == nil {
+			day.Stops = []model.ItineraryStop{}
+		}
+		for j := 1; j < len(day.Stops); j++ {
+			prev, cur := day.Stops[j-1], &day.Stops[j]
+			// 对象已被删除的停留点没有坐标，不计入距离
+			if !located[prev.StopID] || !located[cur.StopID] {
+				continue
+			}
+			cur.DistanceFromPrevM = geo.Distance(prev.Latitude, prev.Longitude, cur.Latitude, cur.Longitude)
+			day.DistanceM += cur.DistanceFromPrevM
+		}
+		item.TotalDistanceM += day.DistanceM
+		if item.StartDate != nil {
+			date := item.StartDate.AddDate(0, 0, day.DayIndex-1).Format(hours.DateLayout)
+			day.Date = &date
+		}
+	}
+}
+
+// sameIDSet 判断两组ID是否为同一集合（b 中不允许重复）
+func sameIDSet(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
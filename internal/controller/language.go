@@ -6,6 +6,7 @@ import (
 
 	"travel-ar-backend/internal/model"
 	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/i18n"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +21,7 @@ import (
 // @Success 200 {object} model.Response[model.Language]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/languages [post]
 func CreateLanguage(c *gin.Context) {
 	var req model.LanguageReqCreate
@@ -30,6 +32,7 @@ func CreateLanguage(c *gin.Context) {
 
 	language := model.Language{
 		LanguageName: req.LanguageName,
+		LanguageCode: i18n.Normalize(req.LanguageCode),
 		DisplayOrder: req.DisplayOrder,
 		IsActive:     req.IsActive,
 	}
@@ -52,6 +55,7 @@ func CreateLanguage(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/languages [put]
 func UpdateLanguage(c *gin.Context) {
 	var req model.LanguageReqEdit
@@ -69,6 +73,7 @@ func UpdateLanguage(c *gin.Context) {
 
 	db.Model(&language).Updates(model.Language{
 		LanguageName: req.LanguageName,
+		LanguageCode: i18n.Normalize(req.LanguageCode),
 		DisplayOrder: req.DisplayOrder,
 		IsActive:     req.IsActive,
 	})
@@ -86,6 +91,7 @@ func UpdateLanguage(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/languages/{language_id} [delete]
 func DeleteLanguage(c *gin.Context) {
 	id := c.Param("language_id")
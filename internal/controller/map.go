@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// mapMaxFeatures 每种实体类型单次视口查询的最大条数
+	mapMaxFeatures = 5000
+	// mapClusterThreshold 视口内点数超过该值时才进行聚合
+	mapClusterThreshold = 100
+	// mapClusterMaxZoom 达到该缩放级别后不再聚合
+	mapClusterMaxZoom = 16
+)
+
+// ViewportFeatures godoc
+// @Summary 地图视口要素
+// @Description 查询视口范围内的商铺与设施，返回 GeoJSON FeatureCollection；低缩放级别且点数过多时返回聚合点
+// @Tags Map
+// @Accept json
+// @Produce json
+// @Param req body model.MapReqViewport true "视口范围、实体类型与缩放级别"
+// @Success 200 {object} model.FeatureCollection
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/map/features [post]
+func ViewportFeatures(c *gin.Context) {
+	var req model.MapReqViewport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if req.MinLat >= req.MaxLat || req.MinLng >= req.MaxLng {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "视口范围不正确"})
+		return
+	}
+	types := map[string]bool{}
+	for _, t := range req.Types {
+		if t != model.EntityStore && t != model.EntityFacility {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的实体类型: " + t})
+			return
+		}
+		types[t] = true
+	}
+	if len(types) == 0 {
+		types[model.EntityStore] = true
+		types[model.EntityFacility] = true
+	}
+
+	db := database.GetDB()
+	var features []model.Feature
+	var points []geo.Point
+	add := func(kind string, lat, lng float64, v interface{}) error {
+		props, err := toProperties(v)
+		if err != nil {
+			return err
+		}
+		props["entity_type"] = kind
+		points = append(points, geo.Point{Lat: lat, Lng: lng, Kind: kind, Index: len(features)})
+		features = append(features, model.NewPointFeature(lat, lng, props))
+		return nil
+	}
+
+	if types[model.EntityStore] {
+		var stores []model.Store
+		if err := db.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", req.MinLat, req.MaxLat, req.MinLng, req.MaxLng).
+			Limit(mapMaxFeatures).Find(&stores).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		for _, s := range stores {
+			if err := add(model.EntityStore, s.Latitude, s.Longitude, s); err != nil {
+				c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+				return
+			}
+		}
+	}
+	if types[model.EntityFacility] {
+		var facilities []model.Facility
+		if err := db.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", req.MinLat, req.MaxLat, req.MinLng, req.MaxLng).
+			Limit(mapMaxFeatures).Find(&facilities).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		for _, f := range facilities {
+			if err := add(model.EntityFacility, f.Latitude, f.Longitude, f); err != nil {
+				c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+				return
+			}
+		}
+	}
+
+	if req.Zoom < mapClusterMaxZoom && len(features) > mapClusterThreshold {
+		features = clusterFeatures(features, points, req.Zoom)
+	}
+	if features == nil {
+		features = []model.Feature{}
+	}
+	c.JSON(http.StatusOK, model.FeatureCollection{Type: "FeatureCollection", Features: features})
+}
+
+// clusterFeatures 把同一网格内的多个要素替换为一个聚合点要素
+func clusterFeatures(features []model.Feature, points []geo.Point, zoom int) []model.Feature {
+	var result []model.Feature
+	for _, cl := range geo.GridCluster(points, zoom) {
+		if cl.Count == 1 {
+			result = append(result, features[cl.Members[0]])
+			continue
+		}
+		result = append(result, model.NewPointFeature(cl.Lat, cl.Lng, map[string]interface{}{
+			"cluster":        true,
+			"point_count":    cl.Count,
+			"store_count":    cl.Kinds[model.EntityStore],
+			"facility_count": cl.Kinds[model.EntityFacility],
+		}))
+	}
+	return result
+}
+
+// toProperties 把实体结构体按其 JSON 字段转换为 GeoJSON properties
+func toProperties(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	props := map[string]interface{}{}
+	if err := json.Unmarshal(b, &props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
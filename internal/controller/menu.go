@@ -20,6 +20,7 @@ import (
 // @Success 200 {object} model.Response[model.Menu]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/menus [post]
 func CreateMenu(c *gin.Context) {
 	var req model.MenuReqCreate
@@ -52,6 +53,7 @@ func CreateMenu(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/menus [put]
 func UpdateMenu(c *gin.Context) {
 	var req model.MenuReqEdit
@@ -79,6 +81,7 @@ func UpdateMenu(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/menus/{menu_id} [delete]
 func DeleteMenu(c *gin.Context) {
 	id := c.Param("menu_id")
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListMyStores godoc
+// @Summary 我的商铺列表
+// @Description 获取当前用户作为店主或店员管理的商铺
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param req body model.MyStoreReqList true "分页"
+// @Success 200 {object} model.ListResponse[model.MyStore]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/list [post]
+func ListMyStores(c *gin.Context) {
+	var req model.MyStoreReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var stores []model.MyStore
+	var total int64
+
+	query := db.Table("stores").
+		Joins("JOIN store_members ON store_members.store_id = stores.store_id").
+		Where("store_members.user_id = ?", c.GetInt("user_id"))
+	query.Count(&total)
+	query.Select("stores.*, store_members.member_role").
+		Order("stores.store_id").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).
+		Find(&stores)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.MyStore]{
+		Success: true,
+		Total:   total,
+		List:    stores,
+	})
+}
+
+// GetMyStore godoc
+// @Summary 获取我的商铺
+// @Description 获取当前用户管理的单个商铺
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Success 200 {object} model.Response[model.Store]
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id} [get]
+func GetMyStore(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	var store model.Store
+	if err := db.First(&store, storeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Store]{Success: true, Data: store})
+}
+
+// UpdateMyStore godoc
+// @Summary 编辑我的商铺
+// @Description 店主编辑商铺基本信息，空值字段不更新
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param store body model.MyStoreReqEdit true "商铺信息"
+// @Success 200 {object} model.Response[model.Store]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id} [put]
+func UpdateMyStore(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.MyStoreReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner) {
+		return
+	}
+	var store model.Store
+	if err := db.First(&store, storeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	// 结构体更新会忽略零值字段
+	if err := db.Model(&store).Updates(model.Store{
+		StoreName:       req.StoreName,
+		StoreNameKana:   req.StoreNameKana,
+		StoreCategory:   req.StoreCategory,
+		Location:        req.Location,
+		DescriptionText: req.Description,
+		Address:         req.Address,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		PhoneNumber:     req.PhoneNumber,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Store]{Success: true, Data: store})
+}
+
+// UpdateMyStoreBusinessHours godoc
+// @Summary 更新营业时间
+// @Description 店主或店员更新商铺营业时间
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.MyStoreReqBusinessHours true "营业时间"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/business_hours [put]
+func UpdateMyStoreBusinessHours(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.MyStoreReqBusinessHours
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	if err := db.Model(&model.Store{}).Where("store_id = ?", storeID).
+		Update("business_hours", req.BusinessHours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// UploadMyStorePhoto godoc
+// @Summary 上传商铺照片
+// @Description 店主或店员为商铺上传照片，照片保存在 files 表中（related_type=Store）
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.MyStoreReqPhoto true "照片"
+// @Success 200 {object} model.Response[model.File]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/photos [post]
+func UploadMyStorePhoto(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.MyStoreReqPhoto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !strings.HasPrefix(req.FileType, "image/") {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "仅支持上传图片"})
+		return
+	}
+
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	var store model.Store
+	if err := db.First(&store, storeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+
+	file := model.File{
+		FileName:    req.FileName,
+		FileType:    req.FileType,
+		FileSize:    len(req.FileData),
+		FileData:    req.FileData,
+		Location:    store.Location,
+		RelatedID:   storeID,
+		RelatedType: model.EntityStore,
+	}
+	if err := db.Create(&file).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.File]{Success: true, Data: file})
+}
+
+// ListMyStorePhotos godoc
+// @Summary 商铺照片列表
+// @Description 获取商铺已上传的照片
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Success 200 {object} model.ListResponse[model.File]
+// @Failure 403 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/photos [get]
+func ListMyStorePhotos(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	var files []model.File
+	db.Where("related_type = ? AND related_id = ?", model.EntityStore, storeID).Order("file_id").Find(&files)
+	c.JSON(http.StatusOK, model.ListResponse[model.File]{
+		Success: true,
+		Total:   int64(len(files)),
+		List:    files,
+	})
+}
+
+// DeleteMyStorePhoto godoc
+// @Summary 删除商铺照片
+// @Description 删除商铺的一张照片
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param file_id path int true "文件ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/photos/{file_id} [delete]
+func DeleteMyStorePhoto(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	fileID, _ := strconv.Atoi(c.Param("file_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	result := db.Where("file_id = ? AND related_type = ? AND related_id = ?", fileID, model.EntityStore, storeID).Delete(&model.File{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "照片不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
@@ -20,6 +20,7 @@ import (
 // @Success 200 {object} model.Response[model.Notice]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/notices [post]
 func CreateNotice(c *gin.Context) {
 	var req model.NoticeReqCreate
@@ -42,7 +43,9 @@ func CreateNotice(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, model.Response[model.Notice]{Success: true, Data: notice})
+	notices := []model.Notice{notice}
+	localizeNotices(c, db, notices)
+	c.JSON(http.StatusOK, model.Response[model.Notice]{Success: true, Data: notices[0]})
 }
 
 // UpdateNotice godoc
@@ -55,6 +58,7 @@ func CreateNotice(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/notices [put]
 func UpdateNotice(c *gin.Context) {
 	var req model.NoticeReqEdit
@@ -82,6 +86,7 @@ func UpdateNotice(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/notices/{notice_id} [delete]
 func DeleteNotice(c *gin.Context) {
 	id := c.Param("notice_id")
@@ -101,6 +106,7 @@ func DeleteNotice(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param notice_id path int true "通知ID"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.Response[model.Notice]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
@@ -124,6 +130,7 @@ func GetNotice(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param req body model.NoticeReqList true "分页与搜索"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.ListResponse[model.Notice]
 // @Failure 400 {object} model.BaseResponse
 // @Router /api/notices/list [post]
@@ -139,6 +146,7 @@ func ListNotices(c *gin.Context) {
 
 	db.Model(&model.Notice{}).Count(&total)
 	db.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&notices)
+	localizeNotices(c, db, notices)
 
 	c.JSON(http.StatusOK, model.ListResponse[model.Notice]{
 		Success: true,
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"travel-ar-backend/internal/idp"
+	"travel-ar-backend/internal/token"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/markbates/goth/gothic"
+)
+
+// BeginOAuth godoc
+// @Summary 第三方登录（Web）
+// @Description 跳转到第三方登录页面，登录完成后回调 /api/auth/{provider}/callback。目前支持 google
+// @Tags Auth
+// @Param provider path string true "登录方式（google）"
+// @Success 302
+// @Router /api/auth/{provider} [get]
+func BeginOAuth(c *gin.Context) {
+	gothic.BeginAuthHandler(c.Writer, gothic.GetContextWithProvider(c.Request, c.Param("provider")))
+}
+
+// OAuthCallback godoc
+// @Summary 第三方登录回调（Web）
+// @Description 完成第三方登录，签发与移动端相同的 access token / refresh token 并写入 Cookie，然后跳转到前端（OAUTH_REDIRECT_URL，默认 http://localhost:5173）。失败时跳转地址带 error 参数；邮箱已被其他账号使用时 error=link_required，绑定确认凭证写入 link_token Cookie（仅发送到 /api/auth/link），不会自动合并
+// @Tags Auth
+// @Param provider path string true "登录方式（google）"
+// @Success 302
+// @Router /api/auth/{provider}/callback [get]
+func OAuthCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	gothUser, err := gothic.CompleteUserAuth(c.Writer, gothic.GetContextWithProvider(c.Request, provider))
+	if err != nil {
+		log.Printf("oauth %s callback: %v", provider, err)
+		redirectOAuth(c, url.Values{"error": {"auth_failed"}})
+		return
+	}
+	if provider != idp.Google {
+		redirectOAuth(c, url.Values{"error": {"unsupported_provider"}})
+		return
+	}
+	verified, _ := gothUser.RawData["verified_email"].(bool)
+	db := database.GetDB()
+	user, link, err := socialUser(c, db, &idp.Identity{
+		Provider:      idp.Google,
+		Subject:       gothUser.UserID,
+		Email:         gothUser.Email,
+		EmailVerified: verified,
+		Name:          gothUser.Name,
+		Picture:       gothUser.AvatarURL,
+	})
+	switch {
+	case link != nil:
+		// 邮箱已被其他账号使用：由前端引导用户通过 /api/auth/link/confirm 确认绑定
+		// 凭证写入 HttpOnly Cookie，不放进跳转地址，避免经浏览器历史或 Referer 泄露
+		token.Default().SetLinkCookie(c.Writer, link.LinkToken, time.Now().Add(linkTokenTTL))
+		redirectOAuth(c, url.Values{"error": {"link_required"}, "email": {link.Email}, "provider": {link.Provider}})
+		return
+	case errors.Is(err, errAccountDisabled):
+		redirectOAuth(c, url.Values{"error": {"account_disabled"}})
+		return
+	case errors.Is(err, errEmailRequired):
+		redirectOAuth(c, url.Values{"error": {"email_required"}})
+		return
+	case errors.Is(err, errEmailUnverified):
+		redirectOAuth(c, url.Values{"error": {"email_unverified"}, "email": {user.Email}})
+		return
+	case err != nil:
+		log.Printf("oauth %s user: %v", provider, err)
+		redirectOAuth(c, url.Values{"error": {"auth_failed"}})
+		return
+	}
+	if _, _, err := issueTokens(c, db, user, newSessionID(), deviceName(c)); err != nil {
+		log.Printf("oauth %s issue tokens: %v", provider, err)
+		redirectOAuth(c, url.Values{"error": {"auth_failed"}})
+		return
+	}
+	redirectOAuth(c, nil)
+}
+
+// redirectOAuth 跳转回前端，params 附加到查询参数（失败时含 error）
+func redirectOAuth(c *gin.Context, params url.Values) {
+	target := os.Getenv("OAUTH_REDIRECT_URL")
+	if target == "" {
+		target = "http://localhost:5173"
+	}
+	if len(params) > 0 {
+		if u, err := url.Parse(target); err == nil {
+			q := u.Query()
+			for k, v := range params {
+				q[k] = v
+			}
+			u.RawQuery = q.Encode()
+			target = u.String()
+		}
+	}
+	c.Redirect(http.StatusFound, target)
+}
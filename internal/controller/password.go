@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"travel-ar-backend/internal/mailer"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
+	"travel-ar-backend/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	minPasswordLength     = 6                // 密码最短长度
+	resetTokenLength      = 40               // 重置令牌长度
+	resetTokenTTL         = 30 * time.Minute // 重置令牌有效期
+	maxResetPerEmailHour  = 3                // 同一邮箱每小时最多发送的重置邮件数
+	resetRequestPerIPHour = 10               // 同一 IP 每小时最多申请次数
+)
+
+// resetRequestLimiter 按客户端 IP 限制重置申请频率
+var resetRequestLimiter = ratelimit.New(resetRequestPerIPHour, time.Hour)
+
+// resetRequestAccepted 申请重置的统一响应，不论邮箱是否存在都相同，避免泄露账号信息
+const resetRequestAccepted = "如果该邮箱已注册，我们已发送密码重置邮件，请查收"
+
+// RequestPasswordReset godoc
+// @Summary 申请重置密码
+// @Description 向邮箱发送一次性的密码重置令牌，30 分钟内有效。无论邮箱是否注册都返回相同结果；同一 IP 每小时最多 10 次，同一邮箱每小时最多发送 3 封
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.PasswordResetRequest true "邮箱"
+// @Param lang query string false "邮件语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 429 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/password/reset [post]
+func RequestPasswordReset(c *gin.Context) {
+	var req model.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !resetRequestLimiter.Allow(c.ClientIP()) {
+		c.JSON(http.StatusTooManyRequests, model.BaseResponse{Success: false, ErrMessage: "请求过于频繁，请稍后再试"})
+		return
+	}
+
+	db := database.GetDB()
+	var user model.User
+	if err := db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: resetRequestAccepted})
+		return
+	}
+	if user.Status != model.UserStatusDisabled {
+		token, err := issuePasswordReset(db, user, c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		// 邮件在后台发送，已注册与未注册邮箱的响应内容和耗时一致；发送失败只记录日志
+		if token != "" {
+			langs := languageChain(c)
+			go func() {
+				if err := sendPasswordReset(user, token, langs); err != nil {
+					log.Printf("send password reset to user %d: %v", user.UserID, err)
+				}
+			}()
+		}
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: resetRequestAccepted})
+}
+
+// ConfirmPasswordReset godoc
+// @Summary 重置密码
+// @Description 使用邮件中的令牌设置新密码。令牌只能使用一次，成功后该用户的其他重置令牌与全部登录会话（refresh token）失效
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.PasswordResetConfirm true "重置令牌与新密码"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/password/reset/confirm [post]
+func ConfirmPasswordReset(c *gin.Context) {
+	var req model.PasswordResetConfirm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "密码长度不能少于6位"})
+		return
+	}
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "密码加密失败"})
+		return
+	}
+
+	db := database.GetDB()
+	var rejected *errVerifyRejected
+	err = db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var reset model.PasswordResetToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(req.Token), now).
+			First(&reset).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				rejected = &errVerifyRejected{http.StatusBadRequest, "重置链接无效或已过期，请重新申请"}
+				return nil
+			}
+			return err
+		}
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, reset.UserID).Error; err != nil {
+			return err
+		}
+		if user.Status == model.UserStatusDisabled {
+			rejected = &errVerifyRejected{http.StatusBadRequest, "账号已停用"}
+			return nil
+		}
+		updates := map[string]interface{}{
+			"password":   string(hashedPwd),
+			"updated_at": now,
+		}
+		// 能收到重置邮件即证明邮箱有效，待验证账号一并激活
+		if user.Status == model.UserStatusPending {
+			updates["status"] = model.UserStatusActive
+			updates["verify_code"] = ""
+			updates["verify_code_expire"] = nil
+			updates["verify_attempts"] = 0
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		// 本令牌与该用户其他未使用的令牌全部作废
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return revokeSessions(tx, user.UserID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: "密码已重置，请使用新密码登录"})
+}
+
+// ChangePassword godoc
+// @Summary 修改密码
+// @Description 校验当前密码后设置新密码。修改后除发起请求的会话外，其余登录会话全部失效
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.ChangePasswordRequest true "当前密码与新密码"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 401 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/password [put]
+func ChangePassword(c *gin.Context) {
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "密码长度不能少于6位"})
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "新密码不能与当前密码相同"})
+		return
+	}
+
+	db := database.GetDB()
+	var user model.User
+	if err := db.First(&user, c.GetInt("user_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "用户不存在"})
+		return
+	}
+	// 第三方登录创建的账号没有密码，需通过重置密码设置
+	if user.Password == "" {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "账号未设置密码，请使用重置密码功能设置"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		c.JSON(http.StatusUnauthorized, model.BaseResponse{Success: false, ErrMessage: "当前密码错误"})
+		return
+	}
+	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "密码加密失败"})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password":   string(hashedPwd),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		// 保留发起请求的会话，其余会话全部失效
+		return tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND family_id <> ? AND revoked = false", user.UserID, c.GetString("session_id")).
+			Update("revoked", true).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: "密码已修改"})
+}
+
+// issuePasswordReset 在同一邮箱每小时的发送上限内生成并保存重置令牌，达到上限时返回空令牌
+// 计数与写入在同一事务中进行，并先锁定用户行，使并发申请依次计数，不会超过上限
+func issuePasswordReset(db *gorm.DB, user model.User, requestIP string) (string, error) {
+	var token string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("user_id").First(&model.User{}, user.UserID).Error; err != nil {
+			return err
+		}
+		var sent int64
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND created_at > ?", user.UserID, time.Now().Add(-time.Hour)).
+			Count(&sent).Error; err != nil {
+			return err
+		}
+		if sent >= maxResetPerEmailHour {
+			return nil
+		}
+		token = randcode.New(resetTokenLength)
+		return tx.Create(&model.PasswordResetToken{
+			UserID:    user.UserID,
+			TokenHash: hashToken(token),
+			ExpiresAt: time.Now().Add(resetTokenTTL),
+			RequestIP: requestIP,
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// sendPasswordReset 按请求语言渲染密码重置邮件并发送
+// 配置 PASSWORD_RESET_URL 时邮件中给出带 token 参数的链接，否则直接给出令牌
+func sendPasswordReset(user model.User, token string, langs []string) error {
+	link := ""
+	if base := os.Getenv("PASSWORD_RESET_URL"); base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("token", token)
+		u.RawQuery = q.Encode()
+		link = u.String()
+	}
+	msg, err := mailer.Render(mailer.TemplatePasswordReset, langs, map[string]interface{}{
+		"Token":         token,
+		"URL":           link,
+		"ExpireMinutes": int(resetTokenTTL / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	ctx, cancel := context.WithTimeout(context.Background(), verifySendTimeout)
+	defer cancel()
+	return mailer.Default().Send(ctx, msg)
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+
+	"travel-ar-backend/internal/model"
+)
+
+func TestIssuePasswordResetConcurrentLimit(t *testing.T) {
+	db := setupDB(t)
+	user := createUser(t, db, testEmail(t), "secret123", model.UserStatusActive)
+	t.Cleanup(func() { db.Where("user_id = ?", user.UserID).Delete(&model.PasswordResetToken{}) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := issuePasswordReset(db, user, "192.0.2.1"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var issued int64
+	db.Model(&model.PasswordResetToken{}).Where("user_id = ?", user.UserID).Count(&issued)
+	if issued != maxResetPerEmailHour {
+		t.Fatalf("issued %d reset tokens, want %d", issued, maxResetPerEmailHour)
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/recommend"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecommendRadiusM 推荐搜索半径上限（米）
+const maxRecommendRadiusM = 20000
+
+// GetRecommendations godoc
+// @Summary 个性化推荐
+// @Description 推荐当前位置附近、用户尚未访问或收藏的商铺与设施。综合与去过地点的标签相似度、共同访问（去过相同地点的用户也去过）、热度与距离打分；无访问记录时按热度与距离推荐
+// @Tags Recommendations
+// @Accept json
+// @Produce json
+// @Param req body model.RecommendationReq true "当前位置、半径与类型"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.ListResponse[model.Recommendation]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/recommendations [post]
+func GetRecommendations(c *gin.Context) {
+	var req model.RecommendationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if req.RadiusM == 0 {
+		req.RadiusM = 3000
+	}
+	if req.RadiusM > maxRecommendRadiusM {
+		req.RadiusM = maxRecommendRadiusM
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	db := database.GetDB()
+	scored, err := recommend.Recommend(db, recommend.Query{
+		UserID:    c.GetInt("user_id"),
+		Latitude:  *req.Latitude,
+		Longitude: *req.Longitude,
+		RadiusM:   req.RadiusM,
+		Types:     req.Types,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	ids := map[string][]int{}
+	for _, s := range scored {
+		ids[s.EntityType] = append(ids[s.EntityType], s.EntityID)
+	}
+	targets := loadTargets(c, db, ids)
+	list := make([]model.Recommendation, len(scored))
+	for i, s := range scored {
+		list[i] = model.Recommendation{
+			EntityType: s.EntityType,
+			EntityID:   s.EntityID,
+			DistanceM:  s.DistanceM,
+			Score:      s.Score,
+			Reasons:    s.Reasons,
+			Target:     targets[s.EntityType][s.EntityID],
+		}
+	}
+	c.JSON(http.StatusOK, model.ListResponse[model.Recommendation]{
+		Success: true,
+		Total:   int64(len(list)),
+		List:    list,
+	})
+}
+
+// RebuildCoVisitations godoc
+// @Summary 重建共同访问矩阵
+// @Description 立即根据访问记录与收藏重新计算推荐用的共同访问矩阵（后台任务也会定期重建），返回写入的行数
+// @Tags Recommendations
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Response[int64]
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/recommendations/rebuild [post]
+func RebuildCoVisitations(c *gin.Context) {
+	n, err := recommend.RebuildCoVisitations(database.GetDB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[int64]{Success: true, Data: n})
+}
@@ -20,6 +20,7 @@ import (
 // @Success 200 {object} model.Response[model.RefreshToken]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/refresh_tokens [post]
 func CreateRefreshToken(c *gin.Context) {
 	var req model.RefreshTokenReqCreate
@@ -28,11 +29,17 @@ func CreateRefreshToken(c *gin.Context) {
 		return
 	}
 
+	familyID := req.FamilyID
+	if familyID == "" {
+		familyID = newSessionID()
+	}
 	token := model.RefreshToken{
-		UserID:       req.UserID,
-		RefreshToken: req.RefreshToken,
-		ExpiresAt:    req.ExpiresAt,
-		Revoked:      req.Revoked,
+		UserID:     req.UserID,
+		FamilyID:   familyID,
+		TokenHash:  hashToken(req.RefreshToken),
+		DeviceName: req.DeviceName,
+		ExpiresAt:  req.ExpiresAt,
+		Revoked:    req.Revoked,
 	}
 	db := database.GetDB()
 	if err := db.Create(&token).Error; err != nil {
@@ -52,6 +59,7 @@ func CreateRefreshToken(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/refresh_tokens [put]
 func UpdateRefreshToken(c *gin.Context) {
 	var req model.RefreshTokenReqEdit
@@ -79,6 +87,7 @@ func UpdateRefreshToken(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/refresh_tokens/{token_id} [delete]
 func DeleteRefreshToken(c *gin.Context) {
 	id := c.Param("token_id")
@@ -101,6 +110,7 @@ func DeleteRefreshToken(c *gin.Context) {
 // @Success 200 {object} model.Response[model.RefreshToken]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/refresh_tokens/{token_id} [get]
 func GetRefreshToken(c *gin.Context) {
 	id := c.Param("token_id")
@@ -123,6 +133,7 @@ func GetRefreshToken(c *gin.Context) {
 // @Param req body model.RefreshTokenReqList true "分页与搜索"
 // @Success 200 {object} model.ListResponse[model.RefreshToken]
 // @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/refresh_tokens/list [post]
 func ListRefreshTokens(c *gin.Context) {
 	var req model.RefreshTokenReqList
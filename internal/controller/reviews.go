@@ -0,0 +1,446 @@
+package controller
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateReview godoc
+// @Summary 发表商铺评价
+// @Description 当前用户对商铺发表 1~5 星评价，可附带图片；每个用户对同一商铺只能评价一次
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review body model.ReviewReqCreate true "评价信息"
+// @Success 200 {object} model.Response[model.StoreReview]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/reviews [post]
+func CreateReview(c *gin.Context) {
+	var req model.ReviewReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !validReviewPhotos(req.Photos) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "仅支持上传 JPEG、PNG、WebP、GIF 图片"})
+		return
+	}
+
+	db := database.GetDB()
+	var store model.Store
+	if err := db.First(&store, req.StoreID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	userID := c.GetInt("user_id")
+	var count int64
+	db.Model(&model.StoreReview{}).Where("store_id = ? AND user_id = ?", req.StoreID, userID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, model.BaseResponse{Success: false, ErrMessage: "已评价过该商铺，请修改原评价"})
+		return
+	}
+
+	review := model.StoreReview{
+		StoreID:     req.StoreID,
+		UserID:      userID,
+		Rating:      req.Rating,
+		ReviewText:  req.ReviewText,
+		IsPublished: true,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		ids, err := saveReviewPhotos(tx, review.ReviewID, store.Location, req.Photos)
+		if err != nil {
+			return err
+		}
+		review.PhotoFileIDs = ids
+		return refreshStoreRating(tx, req.StoreID)
+	})
+	if isDuplicateKey(db, err) {
+		// 并发请求越过了上面的检查，由唯一约束拦截
+		c.JSON(http.StatusConflict, model.BaseResponse{Success: false, ErrMessage: "已评价过该商铺，请修改原评价"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreReview]{Success: true, Data: review})
+}
+
+// UpdateReview godoc
+// @Summary 修改商铺评价
+// @Description 修改本人的评价；photos 不为空时替换全部图片
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review body model.ReviewReqEdit true "评价信息"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/reviews [put]
+func UpdateReview(c *gin.Context) {
+	var req model.ReviewReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !validReviewPhotos(req.Photos) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "仅支持上传 JPEG、PNG、WebP、GIF 图片"})
+		return
+	}
+
+	db := database.GetDB()
+	var review model.StoreReview
+	if err := db.First(&review, req.ReviewID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "评价不存在"})
+		return
+	}
+	if review.UserID != c.GetInt("user_id") {
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "只能修改自己的评价"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Rating != 0 {
+		updates["rating"] = req.Rating
+	}
+	if req.ReviewText != nil {
+		updates["review_text"] = *req.ReviewText
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&review).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if len(req.Photos) > 0 {
+			if err := tx.Where("related_type = ? AND related_id = ?", model.EntityReview, review.ReviewID).
+				Delete(&model.File{}).Error; err != nil {
+				return err
+			}
+			var store model.Store
+			tx.Select("location").First(&store, review.StoreID)
+			if _, err := saveReviewPhotos(tx, review.ReviewID, store.Location, req.Photos); err != nil {
+				return err
+			}
+		}
+		return refreshStoreRating(tx, review.StoreID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// DeleteReview godoc
+// @Summary 删除商铺评价
+// @Description 删除评价及其图片；本人或管理员、内容编辑可操作
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review_id path int true "评价ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/reviews/{review_id} [delete]
+func DeleteReview(c *gin.Context) {
+	reviewID, _ := strconv.Atoi(c.Param("review_id"))
+	db := database.GetDB()
+	var review model.StoreReview
+	if err := db.First(&review, reviewID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "评价不存在"})
+		return
+	}
+	role := c.GetString("role")
+	if review.UserID != c.GetInt("user_id") && role != model.RoleAdmin && role != model.RoleEditor {
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "只能删除自己的评价"})
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("related_type = ? AND related_id = ?", model.EntityReview, review.ReviewID).
+			Delete(&model.File{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return refreshStoreRating(tx, review.StoreID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// PublishReview godoc
+// @Summary 审核商铺评价
+// @Description 设置评价的公开状态，仅公开的评价计入商铺评分
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review_id path int true "评价ID"
+// @Param req body model.ReviewReqPublish true "公开状态"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/reviews/{review_id}/publish [put]
+func PublishReview(c *gin.Context) {
+	reviewID, _ := strconv.Atoi(c.Param("review_id"))
+	var req model.ReviewReqPublish
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var review model.StoreReview
+	if err := db.First(&review, reviewID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "评价不存在"})
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&review).Update("is_published", req.IsPublished).Error; err != nil {
+			return err
+		}
+		return refreshStoreRating(tx, review.StoreID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetReview godoc
+// @Summary 获取单个评价
+// @Description 获取一条已公开的评价
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param review_id path int true "评价ID"
+// @Success 200 {object} model.Response[model.StoreReview]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/reviews/{review_id} [get]
+func GetReview(c *gin.Context) {
+	reviewID, _ := strconv.Atoi(c.Param("review_id"))
+	db := database.GetDB()
+	var review model.StoreReview
+	if err := db.Where("is_published = ?", true).First(&review, reviewID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "评价不存在"})
+		return
+	}
+	reviews := []model.StoreReview{review}
+	fillReviewPhotos(db, reviews)
+	c.JSON(http.StatusOK, model.Response[model.StoreReview]{Success: true, Data: reviews[0]})
+}
+
+// ListReviews godoc
+// @Summary 获取评价列表
+// @Description 获取已公开评价的分页列表，可按商铺、用户、评分筛选，按时间倒序
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param req body model.ReviewReqList true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.StoreReview]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/reviews/list [post]
+func ListReviews(c *gin.Context) {
+	var req model.ReviewReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	published := true
+	req.IsPublished = &published
+	listReviews(c, req)
+}
+
+// ListMyReviews godoc
+// @Summary 获取我的评价
+// @Description 获取当前用户发表的全部评价（含未公开）
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ListResponse[model.StoreReview]
+// @Security ApiKeyAuth
+// @Router /api/reviews/mine [get]
+func ListMyReviews(c *gin.Context) {
+	db := database.GetDB()
+	var reviews []model.StoreReview
+	db.Where("user_id = ?", c.GetInt("user_id")).Order("created_at DESC").Find(&reviews)
+	fillReviewPhotos(db, reviews)
+	c.JSON(http.StatusOK, model.ListResponse[model.StoreReview]{
+		Success: true,
+		Total:   int64(len(reviews)),
+		List:    reviews,
+	})
+}
+
+// ListModerationReviews godoc
+// @Summary 获取待审核评价列表
+// @Description 获取全部评价的分页列表，可按公开状态筛选，用于审核
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param req body model.ReviewReqList true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.StoreReview]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/reviews/moderation/list [post]
+func ListModerationReviews(c *gin.Context) {
+	var req model.ReviewReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	listReviews(c, req)
+}
+
+func listReviews(c *gin.Context, req model.ReviewReqList) {
+	db := database.GetDB()
+	var reviews []model.StoreReview
+	var total int64
+
+	query := db.Model(&model.StoreReview{})
+	if req.IsPublished != nil {
+		query = query.Where("is_published = ?", *req.IsPublished)
+	}
+	if req.StoreID != 0 {
+		query = query.Where("store_id = ?", req.StoreID)
+	}
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if req.Rating != 0 {
+		query = query.Where("rating = ?", req.Rating)
+	}
+	if req.Keyword != "" {
+		query = query.Where("review_text LIKE ?", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("created_at DESC").Order("review_id DESC").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&reviews)
+	fillReviewPhotos(db, reviews)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.StoreReview]{
+		Success: true,
+		Total:   total,
+		List:    reviews,
+	})
+}
+
+// refreshStoreRating 根据已公开评价重新计算商铺评分与评价数
+// 先锁定商铺行，保证并发写评价时按顺序重算
+func refreshStoreRating(tx *gorm.DB, storeID int) error {
+	var store model.Store
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("store_id").First(&store, storeID).Error; err != nil {
+		return err
+	}
+	var stat struct {
+		Avg   float64
+		Count int
+	}
+	if err := tx.Model(&model.StoreReview{}).
+		Select("COALESCE(AVG(rating), 0) AS avg, COUNT(*) AS count").
+		Where("store_id = ? AND is_published = ?", storeID, true).
+		Scan(&stat).Error; err != nil {
+		return err
+	}
+	return tx.Model(&store).Updates(map[string]interface{}{
+		"rating_score": math.Round(stat.Avg*100) / 100,
+		"review_count": stat.Count,
+	}).Error
+}
+
+// validReviewPhotos 按文件内容识别图片类型，只接受 JPEG、PNG、WebP、GIF，并以识别结果覆盖客户端提供的类型
+func validReviewPhotos(photos []model.ReviewReqPhoto) bool {
+	for i := range photos {
+		detected := http.DetectContentType(photos[i].FileData)
+		if !rasterImageTypes[detected] {
+			return false
+		}
+		photos[i].FileType = detected
+	}
+	return true
+}
+
+// saveReviewPhotos 将评价图片保存到 files 表，返回文件ID
+func saveReviewPhotos(tx *gorm.DB, reviewID int, location string, photos []model.ReviewReqPhoto) ([]int, error) {
+	ids := []int{}
+	for _, p := range photos {
+		file := model.File{
+			FileName:    p.FileName,
+			FileType:    p.FileType,
+			FileSize:    len(p.FileData),
+			FileData:    p.FileData,
+			Location:    location,
+			RelatedID:   reviewID,
+			RelatedType: model.EntityReview,
+		}
+		if err := tx.Create(&file).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, file.FileID)
+	}
+	return ids, nil
+}
+
+// fillReviewPhotos 填充评价的图片文件ID
+func fillReviewPhotos(db *gorm.DB, reviews []model.StoreReview) {
+	if len(reviews) == 0 {
+		return
+	}
+	ids := make([]int, len(reviews))
+	index := make(map[int]int, len(reviews))
+	for i := range reviews {
+		ids[i] = reviews[i].ReviewID
+		index[reviews[i].ReviewID] = i
+		reviews[i].PhotoFileIDs = []int{}
+	}
+	var files []model.File
+	db.Select("file_id", "related_id").
+		Where("related_type = ? AND related_id IN ?", model.EntityReview, ids).
+		Order("file_id").Find(&files)
+	for _, f := range files {
+		if i, ok := index[f.RelatedID]; ok {
+			reviews[i].PhotoFileIDs = append(reviews[i].PhotoFileIDs, f.FileID)
+		}
+	}
+}
+
+// isDuplicateKey 判断是否为唯一约束冲突，借助方言的错误转换而不依赖驱动的错误类型
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
+	"travel-ar-backend/pkg/route"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OptimizeRoute godoc
+// @Summary 优化访问顺序
+// @Description 按直线距离计算商铺、设施的建议访问顺序（最近邻 + 2-opt），可固定起点与终点；指定出发时间时尽量在营业时间内到达
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Param req body model.RouteReqOptimize true "停留点与参数"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.RouteResult]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/routes/optimize [post]
+func OptimizeRoute(c *gin.Context) {
+	var req model.RouteReqOptimize
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	points := append([]model.RoutePoint{}, req.Stops...)
+	if req.Start != nil {
+		points = append(points, *req.Start)
+	}
+	if req.End != nil {
+		points = append(points, *req.End)
+	}
+	db := database.GetDB()
+	stops, infos, err := resolveRoutePoints(c, db, points, req.DepartAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	opt := routeOptions(req.DepartAt, req.DwellMinutes, req.SpeedKMH)
+	n := len(req.Stops)
+	if req.Start != nil {
+		opt.Start = &stops[n]
+		n++
+	}
+	if req.End != nil {
+		opt.End = &stops[n]
+	}
+	result := route.Optimize(stops[:len(req.Stops)], opt)
+	c.JSON(http.StatusOK, model.Response[model.RouteResult]{Success: true, Data: routeResult(result, infos, opt.Start)})
+}
+
+// OptimizeItineraryStops godoc
+// @Summary 优化行程日停留点顺序
+// @Description 按直线距离重新排列当天的停留点并保存，可保持首尾停留点不动；指定出发时间时尽量在营业时间内到达
+// @Tags Itineraries
+// @Accept json
+// @Produce json
+// @Param itinerary_id path int true "行程ID"
+// @Param day_id path int true "行程日ID"
+// @Param req body model.ItineraryReqOptimize true "优化参数"
+// @Success 200 {object} model.Response[model.Itinerary]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/itineraries/{itinerary_id}/days/{day_id}/stops/optimize [post]
+func OptimizeItineraryStops(c *gin.Context) {
+	var req model.ItineraryReqOptimize
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	item, day, ok := ownItineraryDay(c, db)
+	if !ok {
+		return
+	}
+	var current []model.ItineraryStop
+	db.Where("day_id = ?", day.DayID).Order("stop_order").Find(&current)
+	if len(current) < 2 {
+		respondItinerary(c, db, item)
+		return
+	}
+	points := make([]model.RoutePoint, len(current))
+	for i, s := range current {
+		points[i] = model.RoutePoint{StopType: s.StopType, TargetID: s.TargetID}
+	}
+	stops, _, err := resolveRoutePoints(c, db, points, req.DepartAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	opt := routeOptions(req.DepartAt, req.DwellMinutes, req.SpeedKMH)
+	from, to := 0, len(stops)
+	if req.KeepFirst {
+		opt.Start = &stops[0]
+		from++
+	}
+	if req.KeepLast && to-from > 0 {
+		opt.End = &stops[to-1]
+		to--
+	}
+	result := route.Optimize(stops[from:to], opt)
+	order := make([]int, 0, len(current))
+	if req.KeepFirst {
+		order = append(order, current[0].StopID)
+	}
+	for _, v := range result.Visits {
+		order = append(order, current[v.ID].StopID)
+	}
+	if opt.End != nil {
+		order = append(order, current[len(current)-1].StopID)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range order {
+			if err := tx.Model(&model.ItineraryStop{}).Where("stop_id = ?", id).
+				UpdateColumn("stop_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	respondItinerary(c, db, item)
+}
+
+func routeOptions(departAt *time.Time, dwellMinutes int, speedKMH float64) route.Options {
+	opt := route.Options{
+		Dwell:    time.Duration(dwellMinutes) * time.Minute,
+		SpeedMPS: speedKMH * 1000 / 3600,
+	}
+	if departAt != nil {
+		opt.Depart = *departAt
+	}
+	return opt
+}
+
+// resolveRoutePoints 读取地点坐标与名称，返回的 route.Stop.ID 为地点在 points 中的下标
+// 指定出发时间时为有营业时间设置的商铺附带营业时间表
+func resolveRoutePoints(c *gin.Context, db *gorm.DB, points []model.RoutePoint, departAt *time.Time) ([]route.Stop, []model.RouteStop, error) {
+	ids := map[string][]int{}
+	for _, p := range points {
+		if p.StopType != "" {
+			ids[p.StopType] = append(ids[p.StopType], p.TargetID)
+		}
+	}
+
+	stores := map[int]*model.Store{}
+	if len(ids[model.EntityStore]) > 0 {
+		var rows []model.Store
+		db.Where("store_id IN ?", ids[model.EntityStore]).Find(&rows)
+		list := make([]*model.Store, len(rows))
+		for i := range rows {
+			list[i] = &rows[i]
+			stores[rows[i].StoreID] = list[i]
+		}
+		localizeStores(c, db, list)
+	}
+	facilities := map[int]*model.Facility{}
+	if len(ids[model.EntityFacility]) > 0 {
+		var rows []model.Facility
+		db.Where("facility_id IN ?", ids[model.EntityFacility]).Find(&rows)
+		list := make([]*model.Facility, len(rows))
+		for i := range rows {
+			list[i] = &rows[i]
+			facilities[rows[i].FacilityID] = list[i]
+		}
+		localizeFacilities(c, db, list)
+	}
+	schedules := map[int]route.Schedule{}
+	if departAt != nil && len(stores) > 0 {
+		loaded, err := loadStoreSchedules(db, ids[model.EntityStore], *departAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		for id, s := range loaded {
+			if !s.Empty() {
+				schedules[id] = s
+			}
+		}
+	}
+
+	stops := make([]route.Stop, len(points))
+	infos := make([]model.RouteStop, len(points))
+	for i, p := range points {
+		info := model.RouteStop{StopType: p.StopType, TargetID: p.TargetID}
+		switch p.StopType {
+		case model.EntityStore:
+			s, ok := stores[p.TargetID]
+			if !ok {
+				return nil, nil, errors.New("商铺不存在")
+			}
+			info.Name, info.Latitude, info.Longitude = s.StoreName, s.Latitude, s.Longitude
+			stops[i].Hours = schedules[p.TargetID]
+		case model.EntityFacility:
+			f, ok := facilities[p.TargetID]
+			if !ok {
+				return nil, nil, errors.New("设施不存在")
+			}
+			info.Name, info.Latitude, info.Longitude = f.FacilityName, f.Latitude, f.Longitude
+		default:
+			if p.Latitude == nil || p.Longitude == nil {
+				return nil, nil, errors.New("未指定类型的地点须提供经纬度")
+			}
+			info.Latitude, info.Longitude = *p.Latitude, *p.Longitude
+		}
+		stops[i].ID, stops[i].Lat, stops[i].Lng = i, info.Latitude, info.Longitude
+		infos[i] = info
+	}
+	return stops, infos, nil
+}
+
+func routeResult(result route.Result, infos []model.RouteStop, start *route.Stop) model.RouteResult {
+	out := model.RouteResult{
+		Stops:          make([]model.RouteStop, len(result.Visits)),
+		TotalDistanceM: result.DistanceM,
+		Violations:     result.Violations,
+	}
+	prev := start
+	for i, v := range result.Visits {
+		s := infos[v.ID]
+		if prev != nil {
+			s.DistanceFromPrevM = geo.Distance(prev.Lat, prev.Lng, v.Lat, v.Lng)
+		}
+		if !v.Arrive.IsZero() {
+			at := v.Arrive
+			s.ArriveAt = &at
+		}
+		s.WaitMinutes = int(v.Wait / time.Minute)
+		s.Closed = v.Closed
+		out.Stops[i] = s
+		prev = &result.Visits[i].Stop
+	}
+	return out
+}
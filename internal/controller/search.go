@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/search"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search godoc
+// @Summary 全文检索
+// @Description 统一检索文章（标题、正文）、商铺（名称、简介、地址）、设施（名称、简介）及其标签名，按相关度排序并返回高亮摘要与分面统计；日文等不分词文本按包含匹配兜底
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "关键词，多个关键词以空格分隔"
+// @Param types query string false "实体类型，逗号分隔（Article,Store,Facility）"
+// @Param category query string false "分类"
+// @Param page query int false "页码，默认 1"
+// @Param page_size query int false "每页条数，默认 20"
+// @Success 200 {object} model.Response[model.SearchResult]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/search [get]
+func Search(c *gin.Context) {
+	var req model.SearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	q := search.Query{
+		Text:     req.Q,
+		Category: req.Category,
+		Limit:    req.PageSize,
+		Offset:   (req.Page - 1) * req.PageSize,
+	}
+	for _, t := range strings.Split(req.Types, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "":
+		case model.EntityArticle, model.EntityStore, model.EntityFacility:
+			q.Types = append(q.Types, t)
+		default:
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的实体类型: " + t})
+			return
+		}
+	}
+
+	result, err := search.Search(database.GetDB(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.SearchResult]{Success: true, Data: result})
+}
+
+// SuggestSearch godoc
+// @Summary 输入联想
+// @Description 按前缀匹配商铺名、设施名、标签名、文章标题及其读音，忽略全角/半角、平假名/片假名与大小写差异；完全一致的排在最前，其余按热度（访问、收藏、评价等）排序。候选由后台任务定期重建
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "输入中的文本"
+// @Param types query string false "实体类型，逗号分隔（Store,Facility,Tag,Article）"
+// @Param limit query int false "最大条数，默认 8，最大 20"
+// @Success 200 {object} model.Response[[]model.SearchSuggestion]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/search/suggest [get]
+func SuggestSearch(c *gin.Context) {
+	var req model.SearchSuggestReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	q := search.SuggestQuery{Text: req.Q, Limit: req.Limit}
+	for _, t := range strings.Split(req.Types, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "":
+		case model.EntityStore, model.EntityFacility, model.EntityTag, model.EntityArticle:
+			q.Types = append(q.Types, t)
+		default:
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的实体类型: " + t})
+			return
+		}
+	}
+
+	list, err := search.Suggest(database.GetDB(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	// 每次按键都会请求，允许客户端与 CDN 短时间缓存
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, model.Response[[]model.SearchSuggestion]{Success: true, Data: list})
+}
+
+// RebuildSearchSuggestions godoc
+// @Summary 重建输入联想候选
+// @Description 立即由商铺、设施、标签、文章重建联想候选（后台任务也会定期重建），返回候选数
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Response[int]
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/search/suggest/rebuild [post]
+func RebuildSearchSuggestions(c *gin.Context) {
+	n, err := search.RebuildSuggestions(database.GetDB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[int]{Success: true, Data: n})
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/token"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListSessions godoc
+// @Summary 获取登录会话
+// @Description 获取当前用户未过期的登录会话（设备），current 表示发起请求的会话
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ListResponse[model.Session]
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions [get]
+func ListSessions(c *gin.Context) {
+	db := database.GetDB()
+	var sessions []model.Session
+	// 每个会话只有最新签发的令牌有效，其创建时间即最近活动时间
+	if err := db.Table("refresh_tokens t").
+		Select(`t.family_id AS session_id, t.device_name, t.ip_address, t.user_agent,
+			t.created_at AS last_active_at, t.expires_at,
+			(SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id) AS created_at`).
+		Where("t.user_id = ? AND t.revoked = false AND t.expires_at > ?", c.GetInt("user_id"), time.Now()).
+		Order("t.created_at DESC").
+		Scan(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	current := c.GetString("session_id")
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == current
+	}
+	c.JSON(http.StatusOK, model.ListResponse[model.Session]{
+		Success: true,
+		Total:   int64(len(sessions)),
+		List:    sessions,
+	})
+}
+
+// RevokeSession godoc
+// @Summary 注销登录会话
+// @Description 使当前用户的某个登录会话失效，该设备需重新登录
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param session_id path string true "会话ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions/{session_id} [delete]
+func RevokeSession(c *gin.Context) {
+	db := database.GetDB()
+	result := db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked = false", c.GetInt("user_id"), c.Param("session_id")).
+		Update("revoked", true)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "会话不存在或已失效"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// RevokeSessions godoc
+// @Summary 注销其他登录会话
+// @Description 使当前用户除发起请求的会话外的全部登录会话失效；all=true 时包括当前会话
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param all query bool false "是否包括当前会话"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions [delete]
+func RevokeSessions(c *gin.Context) {
+	db := database.GetDB()
+	query := db.Model(&model.RefreshToken{}).Where("user_id = ? AND revoked = false", c.GetInt("user_id"))
+	if current := c.GetString("session_id"); current != "" && c.Query("all") != "true" {
+		query = query.Where("family_id <> ?", current)
+	}
+	if err := query.Update("revoked", true).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// issueTokens 在会话 familyID 中签发 access token 与 refresh token，并写入 Cookie 供 Web 端使用
+func issueTokens(c *gin.Context, db *gorm.DB, user model.User, familyID, device string) (string, string, error) {
+	refreshToken, record, err := issueRefreshToken(db, c, user.UserID, familyID, device)
+	if err != nil {
+		return "", "", err
+	}
+	accessToken, err := token.Default().IssueAccess(user.UserID, user.Role, familyID)
+	if err != nil {
+		return "", "", err
+	}
+	token.Default().SetCookies(c.Writer, accessToken, refreshToken, record.ExpiresAt)
+	return accessToken, refreshToken, nil
+}
+
+// issueRefreshToken 在会话 familyID 中签发新的 refresh token，保存哈希与请求的设备信息
+func issueRefreshToken(db *gorm.DB, c *gin.Context, userID int, familyID, device string) (string, model.RefreshToken, error) {
+	expiresAt := time.Now().Add(token.RefreshTTL)
+	refreshToken, err := token.Default().IssueRefresh(userID, randcode.New(16), expiresAt)
+	if err != nil {
+		return "", model.RefreshToken{}, err
+	}
+	record := model.RefreshToken{
+		UserID:     userID,
+		FamilyID:   familyID,
+		TokenHash:  hashToken(refreshToken),
+		DeviceName: device,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  truncateRunes(c.Request.UserAgent(), 255),
+		ExpiresAt:  expiresAt,
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return "", model.RefreshToken{}, err
+	}
+	return refreshToken, record, nil
+}
+
+// bindOptionalJSON 请求体非空时按 JSON 绑定，失败时写入 400 响应
+func bindOptionalJSON(c *gin.Context, req interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return false
+	}
+	return true
+}
+
+// refreshTokenOrCookie 请求体未提供 refresh token 时取 refresh_token Cookie（Web 端）
+func refreshTokenOrCookie(c *gin.Context, fromBody string) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	return token.RefreshFromRequest(c.Request)
+}
+
+// revokeSessions 作废用户的指定会话，未指定时作废全部会话
+func revokeSessions(tx *gorm.DB, userID int, familyIDs ...string) error {
+	query := tx.Model(&model.RefreshToken{}).Where("user_id = ? AND revoked = false", userID)
+	if len(familyIDs) > 0 {
+		query = query.Where("family_id IN ?", familyIDs)
+	}
+	return query.Update("revoked", true).Error
+}
+
+// newSessionID 生成会话ID（refresh token family）
+func newSessionID() string {
+	return randcode.New(24)
+}
+
+// deviceName 客户端通过 X-Device-Name 请求头提供设备名称（如 "iPhone 15"）
+func deviceName(c *gin.Context) string {
+	return truncateRunes(c.GetHeader("X-Device-Name"), 100)
+}
+
+// hashToken 数据库中只保存令牌的 SHA-256，泄露后无法直接使用
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncateRunes 按字符截断，避免超出列长度
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
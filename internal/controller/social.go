@@ -0,0 +1,492 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"travel-ar-backend/internal/idp"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/token"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	linkTokenTTL        = 10 * time.Minute   // 绑定确认凭证有效期
+	verifyLinkTTL       = 7 * 24 * time.Hour // 待验证账号的第三方身份保留期限，期间完成邮箱验证才会绑定
+	maxLinkAttempts     = 5                  // 确认绑定时允许的密码错误次数
+	socialVerifyTimeout = 10 * time.Second   // 获取提供方公钥超时
+)
+
+var (
+	errAccountDisabled = errors.New("account disabled")
+	errEmailRequired   = errors.New("provider did not share an email address")
+	errEmailUnverified = errors.New("email not verified")
+)
+
+// SocialLogin godoc
+// @Summary 第三方登录/注册（App）
+// @Description 提交 Google、Apple 或 LINE 签发的 ID token 登录，首次登录时注册。提供方未确认邮箱归属时（如 LINE）注册为待验证账号并发送验证码，返回 403（errCode=email_unverified），通过 /api/verify 验证后才绑定该第三方账号并可登录。邮箱已被其他账号使用时不会自动合并，返回 409 与绑定确认凭证（errCode=link_required），需通过 /api/auth/link/confirm 确认
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "登录方式（google, apple, line）"
+// @Param payload body model.SocialLoginRequest true "ID token"
+// @Param X-Device-Name header string false "设备名称，显示在登录会话列表中"
+// @Success 200 {object} model.Response[model.AuthResponse]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 401 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 409 {object} model.Response[model.AccountLinkRequired]
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/auth/{provider} [post]
+func SocialLogin(c *gin.Context) {
+	var req model.SocialLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误: " + err.Error()})
+		return
+	}
+	identity, ok := verifyIdentity(c, c.Param("provider"), req.IdToken, req.Nonce)
+	if !ok {
+		return
+	}
+	if identity.Name == "" {
+		identity.Name = req.Name
+	}
+	db := database.GetDB()
+	user, link, err := socialUser(c, db, identity)
+	if link != nil {
+		c.JSON(http.StatusConflict, model.Response[model.AccountLinkRequired]{
+			Success:    false,
+			ErrCode:    "link_required",
+			ErrMessage: "该邮箱已注册，请确认是否绑定到已有账号",
+			Data:       *link,
+		})
+		return
+	}
+	if !respondSocialError(c, err) {
+		return
+	}
+	respondAuth(c, db, user)
+}
+
+// ConfirmAccountLink godoc
+// @Summary 确认绑定第三方账号
+// @Description 将第三方登录返回的待绑定身份绑定到同邮箱的已有账号。需提供已有账号的密码，或以已有账号登录后提交；通过密码确认时返回登录结果，已登录时返回用户信息。密码最多错误 5 次。Web 端回调时凭证保存在 link_token Cookie 中，请求体可省略 link_token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.AccountLinkConfirm true "绑定确认凭证与密码"
+// @Param X-Device-Name header string false "设备名称，显示在登录会话列表中"
+// @Success 200 {object} model.Response[model.AuthResponse]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 401 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/auth/link/confirm [post]
+func ConfirmAccountLink(c *gin.Context) {
+	var req model.AccountLinkConfirm
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+	fromCookie := req.LinkToken == ""
+	if fromCookie {
+		req.LinkToken = token.LinkFromRequest(c.Request)
+	}
+	if req.LinkToken == "" {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "缺少绑定凭证"})
+		return
+	}
+	currentUserID := c.GetInt("user_id")
+	db := database.GetDB()
+	var user model.User
+	var rejected *errVerifyRejected
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var link model.AccountLink
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+				hashToken(req.LinkToken), model.LinkPurposeConfirm, time.Now()).
+			First(&link).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				rejected = &errVerifyRejected{http.StatusBadRequest, "绑定凭证无效或已过期，请重新登录"}
+				return nil
+			}
+			return err
+		}
+		if link.Attempts >= maxLinkAttempts {
+			rejected = &errVerifyRejected{http.StatusBadRequest, "密码错误次数过多，请重新登录"}
+			return nil
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, link.UserID).Error; err != nil {
+			return err
+		}
+		if user.Status == model.UserStatusDisabled {
+			rejected = &errVerifyRejected{http.StatusForbidden, "账号已停用"}
+			return nil
+		}
+		// 确认已有账号的归属：已登录该账号，或提供该账号的密码
+		if currentUserID != user.UserID {
+			if req.Password == "" || user.Password == "" ||
+				bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+				rejected = &errVerifyRejected{http.StatusUnauthorized, "密码错误，或请先登录已有账号后再确认绑定"}
+				if req.Password == "" {
+					return nil
+				}
+				// 错误次数需要保存，不回滚事务
+				return tx.Model(&link).Update("attempts", gorm.Expr("attempts + 1")).Error
+			}
+		}
+		updates, reject, err := linkIdentity(tx, user, link.Provider, link.Subject)
+		if err != nil || reject != nil {
+			rejected = reject
+			return err
+		}
+		// 第三方已确认邮箱归属时，待验证账号一并激活
+		if user.Status == model.UserStatusPending && link.EmailVerified {
+			updates["status"] = model.UserStatusActive
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&link).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.First(&user, user.UserID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	// 凭证已使用或失效时删除 Cookie；密码错误可重试，保留
+	if fromCookie && (rejected == nil || rejected.status != http.StatusUnauthorized) {
+		token.Default().ClearLinkCookie(c.Writer)
+	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	if currentUserID == user.UserID {
+		c.JSON(http.StatusOK, model.Response[model.User]{Success: true, Data: user})
+		return
+	}
+	respondAuth(c, db, user)
+}
+
+// LinkProvider godoc
+// @Summary 绑定第三方账号
+// @Description 为当前登录账号绑定 Google、Apple 或 LINE 账号，绑定后可用该方式登录
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "登录方式（google, apple, line）"
+// @Param payload body model.SocialLoginRequest true "ID token"
+// @Success 200 {object} model.Response[model.User]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 401 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/link/{provider} [post]
+func LinkProvider(c *gin.Context) {
+	var req model.SocialLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误: " + err.Error()})
+		return
+	}
+	identity, ok := verifyIdentity(c, c.Param("provider"), req.IdToken, req.Nonce)
+	if !ok {
+		return
+	}
+	db := database.GetDB()
+	var user model.User
+	var rejected *errVerifyRejected
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, c.GetInt("user_id")).Error; err != nil {
+			return err
+		}
+		updates, reject, err := linkIdentity(tx, user, identity.Provider, identity.Subject)
+		if err != nil || reject != nil {
+			rejected = reject
+			return err
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&user, user.UserID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.User]{Success: true, Data: user})
+}
+
+// UnlinkProvider godoc
+// @Summary 解除绑定第三方账号
+// @Description 解除当前登录账号与 Google、Apple 或 LINE 账号的绑定，需至少保留一种登录方式（密码或其他第三方）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "登录方式（google, apple, line）"
+// @Success 200 {object} model.Response[model.User]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/auth/link/{provider} [delete]
+func UnlinkProvider(c *gin.Context) {
+	provider := c.Param("provider")
+	column, ok := model.IdentityColumns[provider]
+	if !ok {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的登录方式: " + provider})
+		return
+	}
+	db := database.GetDB()
+	var user model.User
+	if err := db.First(&user, c.GetInt("user_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "用户不存在"})
+		return
+	}
+	if user.Identity(provider) == "" {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "未绑定该登录方式"})
+		return
+	}
+	if len(user.LoginMethods()) < 2 {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "至少需要保留一种登录方式，请先设置密码或绑定其他账号"})
+		return
+	}
+	if err := db.Model(&user).Updates(map[string]interface{}{column: "", "updated_at": time.Now()}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db.First(&user, user.UserID)
+	c.JSON(http.StatusOK, model.Response[model.User]{Success: true, Data: user})
+}
+
+// verifyIdentity 按提供方校验 ID token，失败时写入响应
+func verifyIdentity(c *gin.Context, provider, idToken, nonce string) (*idp.Identity, bool) {
+	verifier, err := idp.Lookup(provider)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的登录方式: " + provider})
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), socialVerifyTimeout)
+	defer cancel()
+	identity, err := verifier.Verify(ctx, idToken, nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.BaseResponse{Success: false, ErrMessage: "ID token无效"})
+		return nil, false
+	}
+	return identity, true
+}
+
+// socialUser 按第三方身份查找或创建用户，移动端 ID token 登录与 Web 登录共用
+// 邮箱已被其他账号使用时不合并，生成绑定确认凭证并返回；
+// 提供方未确认邮箱归属时注册为待验证账号并发送验证码，第三方身份只登记为待绑定，邮箱验证通过后才写入账号，
+// 避免他人以该邮箱预先注册并在邮箱所有者验证后仍能用自己的第三方账号登录
+func socialUser(c *gin.Context, db *gorm.DB, identity *idp.Identity) (model.User, *model.AccountLinkRequired, error) {
+	column := model.IdentityColumns[identity.Provider]
+	var user model.User
+	err := db.Where(column+" = ?", identity.Subject).First(&user).Error
+	if err == nil {
+		switch user.Status {
+		case model.UserStatusDisabled:
+			return user, nil, errAccountDisabled
+		case model.UserStatusPending:
+			return user, nil, errEmailUnverified
+		}
+		return user, nil, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, nil, err
+	}
+	if identity.Email == "" {
+		return user, nil, errEmailRequired
+	}
+
+	err = db.Where("email = ?", identity.Email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 不存在，注册
+		user = model.User{
+			Email:    identity.Email,
+			Name:     identity.Name,
+			Avatar:   identity.Picture,
+			Provider: identity.Provider,
+			Status:   model.UserStatusActive,
+			Role:     model.RoleTourist,
+		}
+		if identity.EmailVerified {
+			switch identity.Provider {
+			case idp.Google:
+				user.GoogleID = identity.Subject
+			case idp.Apple:
+				user.AppleID = identity.Subject
+			case idp.LINE:
+				user.LineID = identity.Subject
+			}
+			return user, nil, db.Create(&user).Error
+		}
+		if err := createPendingSocialUser(db, &user, identity); err != nil {
+			return user, nil, err
+		}
+		// 发送失败时用户已创建，可通过 /api/verify/resend 重新发送
+		if err := sendVerifyCode(c, user); err != nil {
+			log.Printf("send verify code to user %d: %v", user.UserID, err)
+		}
+		return user, nil, errEmailUnverified
+	}
+	if err != nil {
+		return user, nil, err
+	}
+	if user.Status == model.UserStatusDisabled {
+		return user, nil, errAccountDisabled
+	}
+	// 同一第三方身份注册的账号仍在等待邮箱验证
+	if user.Status == model.UserStatusPending {
+		var waiting int64
+		if err := db.Model(&model.AccountLink{}).
+			Where("user_id = ? AND purpose = ? AND provider = ? AND subject = ? AND used_at IS NULL AND expires_at > ?",
+				user.UserID, model.LinkPurposeVerify, identity.Provider, identity.Subject, time.Now()).
+			Count(&waiting).Error; err != nil {
+			return user, nil, err
+		}
+		if waiting > 0 {
+			return user, nil, errEmailUnverified
+		}
+	}
+
+	linkToken := randcode.New(40)
+	if err := db.Create(&model.AccountLink{
+		UserID:        user.UserID,
+		Purpose:       model.LinkPurposeConfirm,
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		TokenHash:     hashToken(linkToken),
+		ExpiresAt:     time.Now().Add(linkTokenTTL),
+	}).Error; err != nil {
+		return user, nil, err
+	}
+	return user, &model.AccountLinkRequired{
+		LinkToken: linkToken,
+		Email:     user.Email,
+		Provider:  identity.Provider,
+		Methods:   user.LoginMethods(),
+	}, nil
+}
+
+// createPendingSocialUser 以未确认邮箱的第三方身份注册待验证账号，身份登记为待绑定，不写入账号
+func createPendingSocialUser(db *gorm.DB, user *model.User, identity *idp.Identity) error {
+	now := time.Now()
+	verifyExpire := now.Add(verifyCodeTTL)
+	user.Status = model.UserStatusPending
+	user.VerifyCode = randcode.Digits(verifyCodeLength)
+	user.VerifyCodeExpire = &verifyExpire
+	user.VerifySentAt = &now
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		// 凭证不下发给客户端，仅用于满足唯一约束；绑定由邮箱验证触发
+		return tx.Create(&model.AccountLink{
+			UserID:        user.UserID,
+			Purpose:       model.LinkPurposeVerify,
+			Provider:      identity.Provider,
+			Subject:       identity.Subject,
+			Email:         identity.Email,
+			EmailVerified: false,
+			TokenHash:     hashToken(randcode.New(40)),
+			ExpiresAt:     now.Add(verifyLinkTTL),
+		}).Error
+	})
+}
+
+// applyVerifiedLinks 邮箱验证通过后，将注册时登记的第三方身份写入账号并重新读取 user；身份已被其他账号绑定时跳过
+func applyVerifiedLinks(tx *gorm.DB, user *model.User) error {
+	var links []model.AccountLink
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", user.UserID, model.LinkPurposeVerify, time.Now()).
+		Order("link_id").Find(&links).Error; err != nil {
+		return err
+	}
+	for _, link := range links {
+		updates, reject, err := linkIdentity(tx, *user, link.Provider, link.Subject)
+		if err != nil {
+			return err
+		}
+		if reject == nil {
+			if err := tx.Model(user).Updates(updates).Error; err != nil {
+				return err
+			}
+			if err := tx.First(user, user.UserID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&link).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkIdentity 检查第三方身份能否绑定到 user，返回需要更新的字段
+func linkIdentity(tx *gorm.DB, user model.User, provider, subject string) (map[string]interface{}, *errVerifyRejected, error) {
+	column := model.IdentityColumns[provider]
+	if current := user.Identity(provider); current != "" && current != subject {
+		return nil, &errVerifyRejected{http.StatusConflict, "账号已绑定其他 " + providerLabel(provider) + " 账号"}, nil
+	}
+	var count int64
+	if err := tx.Model(&model.User{}).
+		Where(column+" = ? AND user_id <> ?", subject, user.UserID).
+		Count(&count).Error; err != nil {
+		return nil, nil, err
+	}
+	if count > 0 {
+		return nil, &errVerifyRejected{http.StatusConflict, "该 " + providerLabel(provider) + " 账号已绑定其他用户"}, nil
+	}
+	return map[string]interface{}{column: subject, "updated_at": time.Now()}, nil, nil
+}
+
+// respondSocialError 写入第三方登录的错误响应，err 为 nil 时返回 true
+func respondSocialError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errAccountDisabled):
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "账号已停用"})
+	case errors.Is(err, errEmailRequired):
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "未获取到邮箱，请在授权时允许提供邮箱地址"})
+	case errors.Is(err, errEmailUnverified):
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrCode: "email_unverified", ErrMessage: "邮箱尚未验证，请使用邮件中的验证码完成验证后再登录"})
+	default:
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: fmt.Sprintf("用户注册失败: %v", err)})
+	}
+	return false
+}
+
+// providerLabel 登录方式的显示名称
+func providerLabel(provider string) string {
+	switch provider {
+	case idp.Google:
+		return "Google"
+	case idp.Apple:
+		return "Apple"
+	case idp.LINE:
+		return "LINE"
+	}
+	return provider
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/idp"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// stubVerifier 不校验 ID token，直接返回固定身份
+type stubVerifier struct{ identity idp.Identity }
+
+func (v stubVerifier) Verify(context.Context, string, string) (*idp.Identity, error) {
+	identity := v.identity
+	return &identity, nil
+}
+
+// socialLogin 以桩校验器登记的身份调用第三方登录
+func socialLogin(t *testing.T, provider string) *httptest.ResponseRecorder {
+	t.Helper()
+	return serve(t, SocialLogin, request{
+		method: http.MethodPost,
+		target: "/api/auth/" + provider,
+		body:   model.SocialLoginRequest{IdToken: "stub"},
+		params: gin.Params{{Key: "provider", Value: provider}},
+	})
+}
+
+// deleteUserByEmail 删除测试中经由 handler 创建的用户及其关联记录
+func deleteUserByEmail(db *gorm.DB, email string) {
+	var user model.User
+	if db.Where("email = ?", email).First(&user).Error != nil {
+		return
+	}
+	db.Where("user_id = ?", user.UserID).Delete(&model.AccountLink{})
+	db.Where("user_id = ?", user.UserID).Delete(&model.RefreshToken{})
+	db.Delete(&user)
+}
+
+func TestUnverifiedSocialSignupCannotHijackAccount(t *testing.T) {
+	db := setupDB(t)
+	email := testEmail(t)
+	idp.Register(idp.LINE, stubVerifier{idp.Identity{Provider: idp.LINE, Subject: "attacker-" + email, Email: email}})
+	t.Cleanup(func() { deleteUserByEmail(db, email) })
+	lineLogin := func() *httptest.ResponseRecorder { return socialLogin(t, idp.LINE) }
+
+	// 他人以未确认邮箱的 LINE 账号抢先注册
+	if w := lineLogin(); w.Code != http.StatusForbidden {
+		t.Fatalf("attacker signup: status = %d, body %s", w.Code, w.Body.String())
+	}
+	var user model.User
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.LineID != "" {
+		t.Fatalf("line_id = %q before email verification", user.LineID)
+	}
+	if w := lineLogin(); w.Code != http.StatusForbidden {
+		t.Fatalf("attacker retry: status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	// 邮箱所有者注册（越过重新发送的间隔）并完成验证
+	db.Model(&user).Update("verify_sent_at", time.Now().Add(-2*verifyResendCooling))
+	w := serve(t, Register, request{
+		method: http.MethodPost,
+		target: "/api/auth/register",
+		body:   model.RegisterRequest{Email: email, Password: "owner-secret"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("owner register: status = %d, body %s", w.Code, w.Body.String())
+	}
+	db.First(&user, user.UserID)
+	w = serve(t, VerifyEmail, request{
+		method: http.MethodPost,
+		target: "/api/verify",
+		body:   model.VerifyRequest{Email: email, Code: user.VerifyCode},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("owner verify: status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	// 他人的 LINE 账号不能登录所有者的账号
+	if w := lineLogin(); w.Code == http.StatusOK {
+		t.Fatalf("attacker logged in after owner verified: %s", w.Body.String())
+	}
+	db.First(&user, user.UserID)
+	if user.LineID != "" || user.Status != model.UserStatusActive {
+		t.Fatalf("line_id = %q, status = %q, want unlinked active account", user.LineID, user.Status)
+	}
+	w = serve(t, Login, request{
+		method: http.MethodPost,
+		target: "/api/auth/login",
+		body:   model.LoginRequest{Email: email, Password: "owner-secret"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("owner login: status = %d, body %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUnverifiedSocialSignupLinksAfterVerification(t *testing.T) {
+	db := setupDB(t)
+	email := testEmail(t)
+	idp.Register(idp.LINE, stubVerifier{idp.Identity{Provider: idp.LINE, Subject: "owner-" + email, Email: email}})
+	t.Cleanup(func() { deleteUserByEmail(db, email) })
+	lineLogin := func() *httptest.ResponseRecorder { return socialLogin(t, idp.LINE) }
+
+	if w := lineLogin(); w.Code != http.StatusForbidden {
+		t.Fatalf("signup: status = %d, body %s", w.Code, w.Body.String())
+	}
+	var user model.User
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	w := serve(t, VerifyEmail, request{
+		method: http.MethodPost,
+		target: "/api/verify",
+		body:   model.VerifyRequest{Email: email, Code: user.VerifyCode},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify: status = %d, body %s", w.Code, w.Body.String())
+	}
+	if w := lineLogin(); w.Code != http.StatusOK {
+		t.Fatalf("line login after verification: status = %d, body %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,266 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/hours"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetStoreHours godoc
+// @Summary 获取商铺营业时间
+// @Description 获取商铺的每周营业区间与例外日期（今天及以后）
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Success 200 {object} model.Response[model.StoreHours]
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/stores/{store_id}/hours [get]
+func GetStoreHours(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+
+	var weekly []model.StoreBusinessHour
+	if err := db.Where("store_id = ?", storeID).Order("weekday, open_time").Find(&weekly).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	var holidays []model.StoreHoliday
+	today := time.Now().In(hours.Location).Format(hours.DateLayout)
+	if err := db.Where("store_id = ? AND holiday_date >= ?", storeID, today).Order("holiday_date, open_time").Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	data := model.StoreHours{
+		Weekly:   make([]model.StoreHoursInterval, 0, len(weekly)),
+		Holidays: make([]model.StoreHolidayItem, 0, len(holidays)),
+	}
+	for _, w := range weekly {
+		data.Weekly = append(data.Weekly, model.StoreHoursInterval{Weekday: w.Weekday, OpenTime: w.OpenTime, CloseTime: w.CloseTime})
+	}
+	for _, h := range holidays {
+		item := model.StoreHolidayItem{Date: h.HolidayDate.Format(hours.DateLayout), IsClosed: h.IsClosed, Note: h.Note}
+		if h.OpenTime != nil && h.CloseTime != nil {
+			item.OpenTime, item.CloseTime = *h.OpenTime, *h.CloseTime
+		}
+		data.Holidays = append(data.Holidays, item)
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreHours]{Success: true, Data: data})
+}
+
+// UpdateStoreHours godoc
+// @Summary 设置商铺营业时间
+// @Description 整体替换商铺的每周营业区间与例外日期（时间均为日本时间）
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.StoreHours true "营业时间"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/stores/{store_id}/hours [put]
+func UpdateStoreHours(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	saveStoreHours(c, database.GetDB(), storeID)
+}
+
+// UpdateMyStoreHours godoc
+// @Summary 设置我的商铺营业时间
+// @Description 店主或店员整体替换商铺的每周营业区间与例外日期（时间均为日本时间）
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param req body model.StoreHours true "营业时间"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/hours [put]
+func UpdateMyStoreHours(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	saveStoreHours(c, db, storeID)
+}
+
+// saveStoreHours 校验请求并在事务中替换商铺的营业时间
+func saveStoreHours(c *gin.Context, db *gorm.DB, storeID int) {
+	var req model.StoreHours
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if err := db.First(&model.Store{}, storeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+
+	weekly := make([]model.StoreBusinessHour, 0, len(req.Weekly))
+	for _, w := range req.Weekly {
+		if _, err := hours.ParseRange(w.OpenTime, w.CloseTime); err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		weekly = append(weekly, model.StoreBusinessHour{StoreID: storeID, Weekday: w.Weekday, OpenTime: w.OpenTime, CloseTime: w.CloseTime})
+	}
+	holidays := make([]model.StoreHoliday, 0, len(req.Holidays))
+	for _, h := range req.Holidays {
+		date, err := time.ParseInLocation(hours.DateLayout, h.Date, hours.Location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: fmt.Sprintf("日期格式应为YYYY-MM-DD: %q", h.Date)})
+			return
+		}
+		holiday := model.StoreHoliday{StoreID: storeID, HolidayDate: date, IsClosed: h.IsClosed, Note: h.Note}
+		if !h.IsClosed {
+			if _, err := hours.ParseRange(h.OpenTime, h.CloseTime); err != nil {
+				c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+				return
+			}
+			open, close := h.OpenTime, h.CloseTime
+			holiday.OpenTime, holiday.CloseTime = &open, &close
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("store_id = ?", storeID).Delete(&model.StoreBusinessHour{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("store_id = ?", storeID).Delete(&model.StoreHoliday{}).Error; err != nil {
+			return err
+		}
+		if len(weekly) > 0 {
+			if err := tx.Create(&weekly).Error; err != nil {
+				return err
+			}
+		}
+		if len(holidays) > 0 {
+			if err := tx.Create(&holidays).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// loadStoreSchedules 批量读取商铺的营业时间表，只加载 now 前后可能用到的例外日期
+func loadStoreSchedules(db *gorm.DB, storeIDs []int, now time.Time) (map[int]hours.Schedule, error) {
+	schedules := make(map[int]hours.Schedule)
+	if len(storeIDs) == 0 {
+		return schedules, nil
+	}
+
+	var weekly []model.StoreBusinessHour
+	if err := db.Where("store_id IN ?", storeIDs).Find(&weekly).Error; err != nil {
+		return nil, err
+	}
+	now = now.In(hours.Location)
+	from := now.AddDate(0, 0, -1).Format(hours.DateLayout)
+	to := now.AddDate(0, 0, 15).Format(hours.DateLayout)
+	var holidays []model.StoreHoliday
+	if err := db.Where("store_id IN ? AND holiday_date BETWEEN ? AND ?", storeIDs, from, to).Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	for _, w := range weekly {
+		r, err := hours.ParseRange(w.OpenTime, w.CloseTime)
+		if err != nil {
+			continue
+		}
+		s := schedules[w.StoreID]
+		s.Weekly = append(s.Weekly, hours.Interval{Weekday: time.Weekday(w.Weekday), Range: r})
+		schedules[w.StoreID] = s
+	}
+	for _, h := range holidays {
+		s := schedules[h.StoreID]
+		if s.Exceptions == nil {
+			s.Exceptions = make(map[string]hours.Exception)
+		}
+		// date 列读出时为 UTC 零点，按日期部分取值
+		key := h.HolidayDate.Format(hours.DateLayout)
+		ex := s.Exceptions[key]
+		if h.IsClosed {
+			ex.Closed = true
+		} else if h.OpenTime != nil && h.CloseTime != nil {
+			if r, err := hours.ParseRange(*h.OpenTime, *h.CloseTime); err == nil {
+				ex.Ranges = append(ex.Ranges, r)
+			}
+		}
+		s.Exceptions[key] = ex
+		schedules[h.StoreID] = s
+	}
+	return schedules, nil
+}
+
+// fillStoreOpenStatus 计算并填充商铺的 is_open_now 与 next_open_at
+func fillStoreOpenStatus(db *gorm.DB, stores []*model.Store) {
+	ids := make([]int, 0, len(stores))
+	for _, s := range stores {
+		ids = append(ids, s.StoreID)
+	}
+	now := time.Now()
+	schedules, err := loadStoreSchedules(db, ids, now)
+	if err != nil {
+		return
+	}
+	for _, s := range stores {
+		schedule, ok := schedules[s.StoreID]
+		if !ok || schedule.Empty() {
+			continue
+		}
+		open := schedule.IsOpen(now)
+		s.IsOpenNow = &open
+		s.NextOpenAt = schedule.NextOpen(now)
+	}
+}
+
+// openNowCondition 返回筛选 now 时刻营业中商铺的 SQL 条件，逻辑与 hours.Schedule.IsOpen 保持一致
+func openNowCondition(now time.Time) (string, map[string]interface{}) {
+	now = now.In(hours.Location)
+	yesterday := now.AddDate(0, 0, -1)
+	args := map[string]interface{}{
+		"today":     now.Format(hours.DateLayout),
+		"yesterday": yesterday.Format(hours.DateLayout),
+		"dow":       int(now.Weekday()),
+		"ydow":      int(yesterday.Weekday()),
+		"clock":     now.Format("15:04"),
+	}
+	// 当天从 open_time 开始，或前一天跨午夜延续到今天
+	const todayRange = "r.open_time <= @clock AND (r.close_time > @clock OR r.close_time <= r.open_time)"
+	const yesterdayRange = "r.close_time <= r.open_time AND r.close_time > @clock"
+	noException := func(date string) string {
+		return "NOT EXISTS (SELECT 1 FROM store_holidays x WHERE x.store_id = stores.store_id AND x.holiday_date = " + date + ")"
+	}
+	notClosed := func(date string) string {
+		return "NOT EXISTS (SELECT 1 FROM store_holidays x WHERE x.store_id = stores.store_id AND x.holiday_date = " + date + " AND x.is_closed)"
+	}
+
+	cond := "(" +
+		"EXISTS (SELECT 1 FROM store_business_hours r WHERE r.store_id = stores.store_id AND r.weekday = @dow AND " + todayRange + " AND " + noException("@today") + ")" +
+		" OR EXISTS (SELECT 1 FROM store_business_hours r WHERE r.store_id = stores.store_id AND r.weekday = @ydow AND " + yesterdayRange + " AND " + noException("@yesterday") + ")" +
+		" OR EXISTS (SELECT 1 FROM store_holidays r WHERE r.store_id = stores.store_id AND r.holiday_date = @today AND NOT r.is_closed AND " + todayRange + " AND " + notClosed("@today") + ")" +
+		" OR EXISTS (SELECT 1 FROM store_holidays r WHERE r.store_id = stores.store_id AND r.holiday_date = @yesterday AND NOT r.is_closed AND " + yesterdayRange + " AND " + notClosed("@yesterday") + ")" +
+		")"
+	return cond, args
+}
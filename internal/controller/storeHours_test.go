@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/hours"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUpdateStoreHoursKeepsOpenHoliday(t *testing.T) {
+	db := setupDB(t)
+	store := createStore(t, db)
+	t.Cleanup(func() { db.Where("store_id = ?", store.StoreID).Delete(&model.StoreHoliday{}) })
+	params := gin.Params{{Key: "store_id", Value: strconv.Itoa(store.StoreID)}}
+	date := time.Now().In(hours.Location).AddDate(0, 0, 7).Format(hours.DateLayout)
+
+	w := serve(t, UpdateStoreHours, request{
+		method: http.MethodPut,
+		target: "/api/stores/" + params[0].Value + "/hours",
+		body: model.StoreHours{Holidays: []model.StoreHolidayItem{
+			{Date: date, IsClosed: false, OpenTime: "10:00", CloseTime: "15:00"},
+		}},
+		params: params,
+		role:   model.RoleAdmin,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	w = serve(t, GetStoreHours, request{method: http.MethodGet, target: "/api/stores/" + params[0].Value + "/hours", params: params})
+	got := decode[model.Response[model.StoreHours]](t, w).Data.Holidays
+	if len(got) != 1 || got[0].IsClosed || got[0].OpenTime != "10:00" || got[0].CloseTime != "15:00" {
+		t.Fatalf("holidays = %+v, want one open 10:00-15:00 holiday", got)
+	}
+}
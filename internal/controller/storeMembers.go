@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateStoreMember godoc
+// @Summary 新增商铺成员
+// @Description 将用户设为商铺的店主或店员；设为店主时游客账号会升级为 store_owner 角色
+// @Tags StoreMembers
+// @Accept json
+// @Produce json
+// @Param member body model.StoreMemberReqCreate true "商铺成员信息"
+// @Success 200 {object} model.Response[model.StoreMember]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/store_members [post]
+func CreateStoreMember(c *gin.Context) {
+	var req model.StoreMemberReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	if err := db.First(&model.Store{}, req.StoreID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	var user model.User
+	if err := db.First(&user, req.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "用户不存在"})
+		return
+	}
+
+	member := model.StoreMember{
+		StoreID:    req.StoreID,
+		UserID:     req.UserID,
+		MemberRole: req.MemberRole,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+		if req.MemberRole == model.StoreMemberOwner && user.Role == model.RoleTourist {
+			return tx.Model(&user).Update("role", model.RoleStoreOwner).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreMember]{Success: true, Data: member})
+}
+
+// DeleteStoreMember godoc
+// @Summary 删除商铺成员
+// @Description 解除用户与商铺的关联
+// @Tags StoreMembers
+// @Accept json
+// @Produce json
+// @Param store_member_id path int true "商铺成员ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/store_members/{store_member_id} [delete]
+func DeleteStoreMember(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("store_member_id"))
+	db := database.GetDB()
+	if err := db.Delete(&model.StoreMember{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// ListStoreMembers godoc
+// @Summary 获取商铺成员列表
+// @Description 获取商铺成员分页列表，可按商铺或用户筛选
+// @Tags StoreMembers
+// @Accept json
+// @Produce json
+// @Param req body model.StoreMemberReqList true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.StoreMember]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/store_members/list [post]
+func ListStoreMembers(c *gin.Context) {
+	var req model.StoreMemberReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var members []model.StoreMember
+	var total int64
+
+	query := db.Model(&model.StoreMember{})
+	if req.StoreID != 0 {
+		query = query.Where("store_id = ?", req.StoreID)
+	}
+	if req.UserID != 0 {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	query.Count(&total)
+	query.Order("store_member_id").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&members)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.StoreMember]{
+		Success: true,
+		Total:   total,
+		List:    members,
+	})
+}
+
+// storeMemberRole 查询用户在商铺中的角色，非成员时返回空字符串
+func storeMemberRole(db *gorm.DB, storeID, userID int) string {
+	var member model.StoreMember
+	if err := db.Where("store_id = ? AND user_id = ?", storeID, userID).First(&member).Error; err != nil {
+		return ""
+	}
+	return member.MemberRole
+}
+
+// authorizeStore 校验当前用户是否以 memberRoles 之一的身份管理该商铺，管理员不受限制
+// 校验失败时直接写入 403 响应并返回 false
+func authorizeStore(c *gin.Context, db *gorm.DB, storeID int, memberRoles ...string) bool {
+	if c.GetString("role") == model.RoleAdmin {
+		return true
+	}
+	role := storeMemberRole(db, storeID, c.GetInt("user_id"))
+	for _, r := range memberRoles {
+		if r == role {
+			return true
+		}
+	}
+	c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "无权管理该商铺"})
+	return false
+}
@@ -0,0 +1,395 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateStoreMenuItem godoc
+// @Summary 新建菜单项
+// @Description 为商铺新建一个菜单项
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param item body model.StoreMenuItemReqCreate true "菜单项信息"
+// @Success 200 {object} model.Response[model.StoreMenuItem]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/menu_items [post]
+func CreateStoreMenuItem(c *gin.Context) {
+	var req model.StoreMenuItemReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if err := db.First(&model.Store{}, req.StoreID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+	createStoreMenuItem(c, db, req.StoreID, req)
+}
+
+// UpdateStoreMenuItem godoc
+// @Summary 更新菜单项
+// @Description 更新菜单项信息，未传的字段保持不变
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param item body model.StoreMenuItemReqEdit true "菜单项信息"
+// @Success 200 {object} model.Response[model.StoreMenuItem]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/menu_items [put]
+func UpdateStoreMenuItem(c *gin.Context) {
+	var req model.StoreMenuItemReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var item model.StoreMenuItem
+	if err := db.First(&item, req.MenuItemID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "菜单项不存在"})
+		return
+	}
+	updateStoreMenuItem(c, db, item, req)
+}
+
+// DeleteStoreMenuItem godoc
+// @Summary 删除菜单项
+// @Description 删除一个菜单项
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param menu_item_id path int true "菜单项ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/menu_items/{menu_item_id} [delete]
+func DeleteStoreMenuItem(c *gin.Context) {
+	itemID, _ := strconv.Atoi(c.Param("menu_item_id"))
+	db := database.GetDB()
+	if err := db.Delete(&model.StoreMenuItem{}, itemID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetStoreMenuItem godoc
+// @Summary 获取单个菜单项
+// @Description 获取一个菜单项信息
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param menu_item_id path int true "菜单项ID"
+// @Success 200 {object} model.Response[model.StoreMenuItem]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/menu_items/{menu_item_id} [get]
+func GetStoreMenuItem(c *gin.Context) {
+	itemID, _ := strconv.Atoi(c.Param("menu_item_id"))
+	db := database.GetDB()
+	var item model.StoreMenuItem
+	if err := db.First(&item, itemID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "菜单项不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreMenuItem]{Success: true, Data: item})
+}
+
+// ListStoreMenuItems godoc
+// @Summary 获取菜单项列表
+// @Description 获取菜单项分页列表，可按商铺筛选、按名称搜索
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param req body model.StoreMenuItemReqList true "分页与搜索"
+// @Success 200 {object} model.ListResponse[model.StoreMenuItem]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/menu_items/list [post]
+func ListStoreMenuItems(c *gin.Context) {
+	var req model.StoreMenuItemReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var items []model.StoreMenuItem
+	var total int64
+
+	query := db.Model(&model.StoreMenuItem{})
+	if req.StoreID != 0 {
+		query = query.Where("store_id = ?", req.StoreID)
+	}
+	if req.Keyword != "" {
+		query = query.Where("item_name LIKE ?", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("store_id").Order("display_order").Order("menu_item_id").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&items)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.StoreMenuItem]{
+		Success: true,
+		Total:   total,
+		List:    items,
+	})
+}
+
+// GetStoreMenu godoc
+// @Summary 获取商铺菜单
+// @Description 获取商铺菜单，按分组聚合；分组顺序取组内最靠前菜单项的顺序
+// @Tags StoreMenuItems
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param available query bool false "仅返回可供应的菜单项"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.Response[model.StoreMenu]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/stores/{store_id}/menu [get]
+func GetStoreMenu(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	db := database.GetDB()
+	if err := db.First(&model.Store{}, storeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
+		return
+	}
+
+	query := db.Where("store_id = ?", storeID)
+	if c.Query("available") == "true" {
+		query = query.Where("is_available = ?", true)
+	}
+	var items []model.StoreMenuItem
+	query.Order("display_order").Order("menu_item_id").Find(&items)
+	localizeMenuItems(c, db, items)
+
+	c.JSON(http.StatusOK, model.Response[model.StoreMenu]{
+		Success: true,
+		Data:    model.StoreMenu{StoreID: storeID, Sections: groupMenuItems(items)},
+	})
+}
+
+// CreateMyStoreMenuItem godoc
+// @Summary 店主新建菜单项
+// @Description 店主或店员为自己的商铺新建菜单项
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param item body model.StoreMenuItemReqCreate true "菜单项信息"
+// @Success 200 {object} model.Response[model.StoreMenuItem]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/menu_items [post]
+func CreateMyStoreMenuItem(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	var req model.StoreMenuItemReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	createStoreMenuItem(c, db, storeID, req)
+}
+
+// UpdateMyStoreMenuItem godoc
+// @Summary 店主更新菜单项
+// @Description 店主或店员更新自己商铺的菜单项，未传的字段保持不变
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param menu_item_id path int true "菜单项ID"
+// @Param item body model.StoreMenuItemReqEdit true "菜单项信息"
+// @Success 200 {object} model.Response[model.StoreMenuItem]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/menu_items/{menu_item_id} [put]
+func UpdateMyStoreMenuItem(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	itemID, _ := strconv.Atoi(c.Param("menu_item_id"))
+	var req model.StoreMenuItemReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	var item model.StoreMenuItem
+	if err := db.Where("store_id = ?", storeID).First(&item, itemID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "菜单项不存在"})
+		return
+	}
+	updateStoreMenuItem(c, db, item, req)
+}
+
+// DeleteMyStoreMenuItem godoc
+// @Summary 店主删除菜单项
+// @Description 店主或店员删除自己商铺的菜单项
+// @Tags MyStores
+// @Accept json
+// @Produce json
+// @Param store_id path int true "商铺ID"
+// @Param menu_item_id path int true "菜单项ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 403 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/my/stores/{store_id}/menu_items/{menu_item_id} [delete]
+func DeleteMyStoreMenuItem(c *gin.Context) {
+	storeID, _ := strconv.Atoi(c.Param("store_id"))
+	itemID, _ := strconv.Atoi(c.Param("menu_item_id"))
+	db := database.GetDB()
+	if !authorizeStore(c, db, storeID, model.StoreMemberOwner, model.StoreMemberStaff) {
+		return
+	}
+	result := db.Where("store_id = ?", storeID).Delete(&model.StoreMenuItem{}, itemID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "菜单项不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+func createStoreMenuItem(c *gin.Context, db *gorm.DB, storeID int, req model.StoreMenuItemReqCreate) {
+	if req.PhotoFileID != nil && !validMenuPhoto(db, storeID, *req.PhotoFileID) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "图片不存在或不属于该商铺"})
+		return
+	}
+	item := model.StoreMenuItem{
+		StoreID:      storeID,
+		Section:      req.Section,
+		ItemName:     req.ItemName,
+		Description:  req.Description,
+		Price:        req.Price,
+		Currency:     normalizeCurrency(req.Currency),
+		PhotoFileID:  req.PhotoFileID,
+		Allergens:    req.Allergens,
+		IsVegan:      req.IsVegan,
+		IsHalal:      req.IsHalal,
+		IsGlutenFree: req.IsGlutenFree,
+		IsAvailable:  req.IsAvailable == nil || *req.IsAvailable,
+		DisplayOrder: req.DisplayOrder,
+	}
+	if item.Allergens == nil {
+		item.Allergens = []string{}
+	}
+	if err := db.Create(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreMenuItem]{Success: true, Data: item})
+}
+
+func updateStoreMenuItem(c *gin.Context, db *gorm.DB, item model.StoreMenuItem, req model.StoreMenuItemReqEdit) {
+	if req.PhotoFileID != nil && *req.PhotoFileID != 0 && !validMenuPhoto(db, item.StoreID, *req.PhotoFileID) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "图片不存在或不属于该商铺"})
+		return
+	}
+	if req.Section != nil {
+		item.Section = *req.Section
+	}
+	if req.ItemName != nil {
+		item.ItemName = *req.ItemName
+	}
+	if req.Description != nil {
+		item.Description = *req.Description
+	}
+	if req.Price != nil {
+		item.Price = *req.Price
+	}
+	if req.Currency != nil {
+		item.Currency = normalizeCurrency(*req.Currency)
+	}
+	if req.PhotoFileID != nil {
+		item.PhotoFileID = req.PhotoFileID
+		if *req.PhotoFileID == 0 {
+			item.PhotoFileID = nil
+		}
+	}
+	if req.Allergens != nil {
+		item.Allergens = *req.Allergens
+	}
+	if req.IsVegan != nil {
+		item.IsVegan = *req.IsVegan
+	}
+	if req.IsHalal != nil {
+		item.IsHalal = *req.IsHalal
+	}
+	if req.IsGlutenFree != nil {
+		item.IsGlutenFree = *req.IsGlutenFree
+	}
+	if req.IsAvailable != nil {
+		item.IsAvailable = *req.IsAvailable
+	}
+	if req.DisplayOrder != nil {
+		item.DisplayOrder = *req.DisplayOrder
+	}
+	// 整行保存，allergens 依赖 json 序列化器
+	if err := db.Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.StoreMenuItem]{Success: true, Data: item})
+}
+
+// validMenuPhoto 菜品图片须为关联到该商铺的图片文件
+func validMenuPhoto(db *gorm.DB, storeID, fileID int) bool {
+	var file model.File
+	if err := db.Select("file_id", "file_type").
+		Where("related_type = ? AND related_id = ?", model.EntityStore, storeID).
+		First(&file, fileID).Error; err != nil {
+		return false
+	}
+	return strings.HasPrefix(file.FileType, "image/")
+}
+
+func normalizeCurrency(currency string) string {
+	if currency == "" {
+		return model.DefaultCurrency
+	}
+	return strings.ToUpper(currency)
+}
+
+// groupMenuItems 按分组聚合已排序的菜单项，保持分组首次出现的顺序
+func groupMenuItems(items []model.StoreMenuItem) []model.StoreMenuSection {
+	sections := []model.StoreMenuSection{}
+	index := map[string]int{}
+	for _, item := range items {
+		i, ok := index[item.Section]
+		if !ok {
+			i = len(sections)
+			index[item.Section] = i
+			sections = append(sections, model.StoreMenuSection{Section: item.Section})
+		}
+		sections[i].Items = append(sections[i].Items, item)
+	}
+	return sections
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateStoreMenuItemKeepsUnavailable(t *testing.T) {
+	db := setupDB(t)
+	store := createStore(t, db)
+	available := false
+
+	w := serve(t, CreateStoreMenuItem, request{
+		method: http.MethodPost,
+		target: "/api/menu_items",
+		body:   model.StoreMenuItemReqCreate{StoreID: store.StoreID, ItemName: "抹茶パフェ", Price: 800, IsAvailable: &available},
+		role:   model.RoleAdmin,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body.String())
+	}
+	created := decode[model.Response[model.StoreMenuItem]](t, w).Data
+	t.Cleanup(func() { db.Delete(&model.StoreMenuItem{}, created.MenuItemID) })
+
+	id := strconv.Itoa(created.MenuItemID)
+	w = serve(t, GetStoreMenuItem, request{
+		method: http.MethodGet,
+		target: "/api/menu_items/" + id,
+		params: gin.Params{{Key: "menu_item_id", Value: id}},
+	})
+	if got := decode[model.Response[model.StoreMenuItem]](t, w).Data; got.IsAvailable {
+		t.Fatalf("is_available = true, want false")
+	}
+}
@@ -1,11 +1,12 @@
 package controller
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 	"travel-ar-backend/internal/model"
 	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/geo"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -21,6 +22,7 @@ import (
 // @Success 200 {object} model.Response[model.Store]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/stores [post]
 func CreateStore(c *gin.Context) {
 	var req model.StoreReqCreate
@@ -31,6 +33,7 @@ func CreateStore(c *gin.Context) {
 
 	store := model.Store{
 		StoreName:       req.StoreName,
+		StoreNameKana:   req.StoreNameKana,
 		StoreCategory:   req.StoreCategory,
 		Location:        req.Location,
 		DescriptionText: req.Description,
@@ -38,7 +41,6 @@ func CreateStore(c *gin.Context) {
 		Latitude:        req.Latitude,
 		Longitude:       req.Longitude,
 		BusinessHours:   req.BusinessHours,
-		RatingScore:     req.RatingScore,
 		PhoneNumber:     req.PhoneNumber,
 	}
 	db := database.GetDB()
@@ -60,6 +62,7 @@ func CreateStore(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/stores [put]
 func UpdateStore(c *gin.Context) {
 	var req model.StoreReqEdit
@@ -87,12 +90,22 @@ func UpdateStore(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/stores/{store_id} [delete]
 func DeleteStore(c *gin.Context) {
 	id := c.Param("store_id")
 	storeID, _ := strconv.Atoi(id)
 	db := database.GetDB()
-	if err := db.Delete(&model.Store{}, storeID).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteFavorites(tx, model.EntityStore, storeID); err != nil {
+			return err
+		}
+		if err := deleteTaggings(tx, model.EntityStore, storeID); err != nil {
+			return err
+		}
+		return tx.Delete(&model.Store{}, storeID).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
@@ -106,6 +119,7 @@ func DeleteStore(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param store_id path int true "商铺ID"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.Response[model.Store]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
@@ -119,6 +133,9 @@ func GetStore(c *gin.Context) {
 		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "商铺不存在"})
 		return
 	}
+	fillStoreOpenStatus(db, []*model.Store{&store})
+	localizeStores(c, db, []*model.Store{&store})
+	fillStoreFavorites(c, db, []*model.Store{&store})
 	c.JSON(http.StatusOK, model.Response[model.Store]{Success: true, Data: store})
 }
 
@@ -129,6 +146,7 @@ func GetStore(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param req body model.StoreReqList true "分页与搜索"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
 // @Success 200 {object} model.ListResponse[model.Store]
 // @Failure 400 {object} model.BaseResponse
 // @Router /api/stores/list [post]
@@ -142,134 +160,90 @@ func ListStores(c *gin.Context) {
 	var stores []model.Store
 	var total int64
 
-	db.Model(&model.Store{}).Count(&total)
-	db.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&stores)
-
-	c.JSON(http.StatusOK, model.ListResponse[model.Store]{
-		Success: true,
-		Total:   total,
-		List:    stores,
-	})
-}
-
-func getTagsQuery(db *gorm.DB, storeID string) *gorm.DB {
-	return db.Table("tags").
-		Select("tags.*").
-		Joins("JOIN taggings ON tags.tag_id = taggings.tag_id").
-		Where("taggings.taggable_type = ? AND taggings.taggable_id = ?", "Store", storeID)
-}
-
-// GetTagsByStore godoc
-// @Summary 获取商铺的标签
-// @Description 根据商铺ID获取关联的标签列表
-// @Tags Stores
-// @Accept json
-// @Produce json
-// @Param storeID path string true "商铺ID"
-// @Success 200 {object} model.ListResponse[model.Tag] "成功响应，包含商铺ID和标签列表"
-// @Failure 500 {object} model.BaseResponse "服务器内部错误"
-// @Router /api/stores/{storeID}/tags [get]
-func GetTagsByStore(c *gin.Context) {
-	storeID := c.Param("storeID")
-
-	var total int64
-	var tags []model.Tag
-	db := database.DB
-
-	// 计算总数
-	if err := getTagsQuery(db, storeID).Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	query := db.Model(&model.Store{})
+	if req.OpenNow {
+		cond, args := openNowCondition(time.Now())
+		query = query.Where(cond, args)
 	}
+	query = filterByTags(query, model.EntityStore, "stores.store_id", req.TagIDs, req.TagMatch)
+	query.Count(&total)
+	query.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&stores)
 
-	// 查询标签
-	if err := getTagsQuery(db, storeID).Find(&tags).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	list := make([]*model.Store, 0, len(stores))
+	for i := range stores {
+		list = append(list, &stores[i])
 	}
+	fillStoreOpenStatus(db, list)
+	localizeStores(c, db, list)
+	fillStoreFavorites(c, db, list)
 
-	c.JSON(http.StatusOK, model.ListResponse[model.Tag]{
+	c.JSON(http.StatusOK, model.ListResponse[model.Store]{
 		Success: true,
 		Total:   total,
-		List:    tags,
+		List:    stores,
 	})
 }
 
-// AddTagToStore godoc
-// @Summary 为商铺添加标签
-// @Description 为指定商铺添加一个标签
+// NearbyStores godoc
+// @Summary 附近商铺
+// @Description 按中心点与半径查询商铺，按距离由近到远排序
 // @Tags Stores
 // @Accept json
 // @Produce json
-// @Param storeID path string true "商铺ID"
-// @Param req body model.StoreTagReq true "标签ID"
-// @Success 200 {object} model.Response[model.Store]
+// @Param req body model.StoreReqNearby true "中心点、半径与分类"
+// @Param lang query string false "语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.ListResponse[model.StoreNearby]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
-// @Router /api/stores/{storeID}/tags [post]
-func AddTagToStore(c *gin.Context) {
-	storeID := c.Param("storeID")
-
-	var req struct {
-		TagID uint `json:"tag_id" binding:"required"`
-	}
-
+// @Router /api/stores/nearby [post]
+func NearbyStores(c *gin.Context) {
+	var req model.StoreReqNearby
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "Invalid input"})
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
-
-	tagging := model.Tagging{
-		TagID:        int(req.TagID),
-		TaggableType: "Store",
-		TaggableID:   parseUint(storeID),
-	}
-
-	if err := database.DB.Create(&tagging).Error; err != nil {
+	lat, lng := *req.Latitude, *req.Longitude
+	db := database.GetDB()
+	minLat, minLng, maxLat, maxLng := geo.BoundingBox(lat, lng, req.RadiusM)
+	inner := db.Model(&model.Store{}).
+		Select("stores.*, "+geo.SQLDistance("latitude", "longitude")+" AS distance_m", lat, lat, lng).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng)
+	if req.Category != "" {
+		inner = inner.Where("store_category = ?", req.Category)
+	}
+
+	var stores []model.StoreNearby
+	if err := db.Table("(?) AS s", inner).
+		Where("distance_m <= ?", req.RadiusM).
+		Order("distance_m").
+		Limit(nearbyLimit(req.Limit)).
+		Find(&stores).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
 		return
 	}
 
-	fmt.Printf("tagging: %+v\n", tagging)
-
-	// 查询添加标签后的商铺信息
-	var store model.Store
-	if err := database.DB.Where("store_id = ?", parseUint(storeID)).First(&store).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
-		return
+	list := make([]*model.Store, 0, len(stores))
+	for i := range stores {
+		list = append(list, &stores[i].Store)
 	}
+	fillStoreOpenStatus(db, list)
+	localizeStores(c, db, list)
+	fillStoreFavorites(c, db, list)
 
-	c.JSON(http.StatusOK, model.Response[model.Store]{
+	c.JSON(http.StatusOK, model.ListResponse[model.StoreNearby]{
 		Success: true,
-		Data:    store,
+		Total:   int64(len(stores)),
+		List:    stores,
 	})
 }
 
-// RemoveTagFromStore godoc
-// @Summary 删除商铺的标签
-// @Description 从指定商铺中移除一个标签
-// @Tags Stores
-// @Accept json
-// @Produce json
-// @Param storeID path string true "商铺ID"
-// @Param tagID path string true "标签ID"
-// @Success 200 {object} map[string]interface{} "成功响应，标签移除成功"
-// @Failure 500 {object} map[string]interface{} "服务器内部错误"
-// @Router /api/stores/{storeID}/tags/{tagID} [delete]
-func RemoveTagFromStore(c *gin.Context) {
-	storeID := c.Param("storeID")
-	tagID := c.Param("tagID")
-
-	if err := database.DB.Where("taggable_type = ? AND taggable_id = ? AND tag_id = ?", "Store", storeID, tagID).
-		Delete(&model.Tagging{}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// nearbyLimit 规范附近查询的返回条数
+func nearbyLimit(limit int) int {
+	if limit <= 0 {
+		return 50
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Tag removed from store"})
-}
-
-func parseUint(s string) int {
-	id, _ := strconv.Atoi(s)
-	return id
+	if limit > 500 {
+		return 500
+	}
+	return limit
 }
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"travel-ar-backend/internal/model"
 	"travel-ar-backend/pkg/database"
@@ -21,6 +25,7 @@ import (
 // @Success 200 {object} model.Response[model.Tag]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/tags [post]
 func CreateTag(c *gin.Context) {
 	var req model.TagReqCreate
@@ -50,6 +55,7 @@ func CreateTag(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/tags [put]
 func UpdateTag(c *gin.Context) {
 	var req model.TagReqEdit
@@ -77,6 +83,7 @@ func UpdateTag(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/tags/{tag_id} [delete]
 func DeleteTag(c *gin.Context) {
 	id := c.Param("tag_id")
@@ -122,11 +129,11 @@ func GetTag(c *gin.Context) {
 
 // ListTags godoc
 // @Summary 获取标签列表
-// @Description 获取标签分页列表
+// @Description 获取标签分页列表，附带各标签的关联实体数
 // @Tags Tags
 // @Accept json
 // @Produce json
-// @Param req body model.TagReqList true "分页与搜索"
+// @Param req body model.TagReqList true "分页、关键字与启用状态"
 // @Success 200 {object} model.ListResponse[model.Tag]
 // @Failure 400 {object} model.BaseResponse
 // @Router /api/tags/list [post]
@@ -139,8 +146,16 @@ func ListTags(c *gin.Context) {
 	db := database.GetDB()
 	var tags []model.Tag
 	var total int64
-	db.Model(&model.Tag{}).Count(&total)
-	db.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&tags)
+	query := db.Model(&model.Tag{})
+	if req.Keyword != "" {
+		query = query.Where("tag_name LIKE ?", "%"+req.Keyword+"%")
+	}
+	if req.IsActive != nil {
+		query = query.Where("is_active = ?", *req.IsActive)
+	}
+	query.Count(&total)
+	query.Order("tag_id").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&tags)
+	fillTagUsage(db, tags)
 
 	c.JSON(http.StatusOK, model.ListResponse[model.Tag]{
 		Success: true,
@@ -148,3 +163,169 @@ func ListTags(c *gin.Context) {
 		List:    tags,
 	})
 }
+
+// GetTagUsage godoc
+// @Summary 标签使用统计
+// @Description 按实体类型统计标签的关联数
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag_id path int true "标签ID"
+// @Success 200 {object} model.ListResponse[model.TagUsage]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/tags/{tag_id}/usage [get]
+func GetTagUsage(c *gin.Context) {
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误"})
+		return
+	}
+	db := database.GetDB()
+	var tag model.Tag
+	if err := db.First(&tag, tagID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "标签不存在"})
+		return
+	}
+	usage := []model.TagUsage{}
+	if err := db.Model(&model.Tagging{}).
+		Select("taggable_type, COUNT(*) AS count").
+		Where("tag_id = ?", tagID).
+		Group("taggable_type").Order("taggable_type").
+		Scan(&usage).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	var total int64
+	for _, u := range usage {
+		total += u.Count
+	}
+	c.JSON(http.StatusOK, model.ListResponse[model.TagUsage]{
+		Success: true,
+		Total:   total,
+		List:    usage,
+	})
+}
+
+// SetTagActive godoc
+// @Summary 启用/停用标签
+// @Description 停用的标签不能再添加到实体，也不在实体标签、检索与联想中展示，但已有关联保留，重新启用后恢复
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag_id path int true "标签ID"
+// @Param req body model.TagReqActive true "启用状态"
+// @Success 200 {object} model.Response[model.Tag]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/tags/{tag_id}/active [put]
+func SetTagActive(c *gin.Context) {
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误"})
+		return
+	}
+	var req model.TagReqActive
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var tag model.Tag
+	if err := db.First(&tag, tagID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "标签不存在"})
+		return
+	}
+	// 结构体更新会忽略 false，使用 map 更新
+	if err := db.Model(&tag).Updates(map[string]interface{}{
+		"is_active":  *req.IsActive,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Tag]{Success: true, Data: tag})
+}
+
+// MergeTags godoc
+// @Summary 合并标签
+// @Description 将源标签的关联全部转移到目标标签（已同时关联两者的实体只保留目标标签），然后删除源标签；返回转移的关联数
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param req body model.TagReqMerge true "源标签与目标标签"
+// @Success 200 {object} model.Response[int64]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/tags/merge [post]
+func MergeTags(c *gin.Context) {
+	var req model.TagReqMerge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var moved int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var tags []model.Tag
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tag_id IN ?", []int{req.SourceTagID, req.TargetTagID}).
+			Find(&tags).Error; err != nil {
+			return err
+		}
+		if len(tags) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+		// 已同时关联两个标签的实体，直接删除源标签的关联
+		if err := tx.Exec(`DELETE FROM taggings s USING taggings t
+			WHERE s.tag_id = ? AND t.tag_id = ? AND t.taggable_type = s.taggable_type AND t.taggable_id = s.taggable_id`,
+			req.SourceTagID, req.TargetTagID).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&model.Tagging{}).Where("tag_id = ?", req.SourceTagID).
+			Updates(map[string]interface{}{"tag_id": req.TargetTagID, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		return tx.Delete(&model.Tag{}, req.SourceTagID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "标签不存在"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[int64]{Success: true, Data: moved})
+}
+
+// fillTagUsage 填充标签的关联实体数
+func fillTagUsage(db *gorm.DB, tags []model.Tag) {
+	if len(tags) == 0 {
+		return
+	}
+	ids := make([]int, len(tags))
+	for i, t := range tags {
+		ids[i] = t.TagID
+	}
+	var rows []struct {
+		TagID int
+		Count int64
+	}
+	db.Model(&model.Tagging{}).Select("tag_id, COUNT(*) AS count").
+		Where("tag_id IN ?", ids).Group("tag_id").Scan(&rows)
+	counts := make(map[int]int64, len(rows))
+	for _, r := range rows {
+		counts[r.TagID] = r.Count
+	}
+	for i := range tags {
+		tags[i].UsageCount = counts[tags[i].TagID]
+	}
+}
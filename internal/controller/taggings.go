@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListEntityTags godoc
+// @Summary 获取实体的标签
+// @Description 获取文章、访问记录、商铺、评论或设施关联的已启用标签
+// @Tags Taggings
+// @Accept json
+// @Produce json
+// @Param taggable_type path string true "实体类型（Article, History, Store, Comment, Facility）"
+// @Param taggable_id path int true "实体ID"
+// @Success 200 {object} model.ListResponse[model.Tag]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/taggings/{taggable_type}/{taggable_id} [get]
+func ListEntityTags(c *gin.Context) {
+	taggableType, taggableID, ok := taggableParams(c)
+	if !ok {
+		return
+	}
+	db := database.GetDB()
+	var tags []model.Tag
+	if err := db.Model(&model.Tag{}).
+		Joins("JOIN taggings ON taggings.tag_id = tags.tag_id").
+		Where("taggings.taggable_type = ? AND taggings.taggable_id = ? AND tags.is_active", taggableType, taggableID).
+		Order("tags.tag_name").
+		Find(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.ListResponse[model.Tag]{
+		Success: true,
+		Total:   int64(len(tags)),
+		List:    tags,
+	})
+}
+
+// AttachTag godoc
+// @Summary 为实体添加标签
+// @Description 为文章、访问记录、商铺、评论或设施添加标签，已添加时不重复创建；停用的标签不能添加
+// @Tags Taggings
+// @Accept json
+// @Produce json
+// @Param taggable_type path string true "实体类型（Article, History, Store, Comment, Facility）"
+// @Param taggable_id path int true "实体ID"
+// @Param req body model.TaggingReqAttach true "标签ID"
+// @Success 200 {object} model.Response[model.Tagging]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 409 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/taggings/{taggable_type}/{taggable_id} [post]
+func AttachTag(c *gin.Context) {
+	taggableType, taggableID, ok := taggableParams(c)
+	if !ok {
+		return
+	}
+	var req model.TaggingReqAttach
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	if !entityExists(db, taggableType, taggableID) {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "实体不存在"})
+		return
+	}
+	var tag model.Tag
+	if err := db.First(&tag, req.TagID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "标签不存在"})
+		return
+	}
+	if !tag.IsActive {
+		c.JSON(http.StatusConflict, model.BaseResponse{Success: false, ErrMessage: "标签已停用"})
+		return
+	}
+
+	tagging := model.Tagging{TagID: tag.TagID, TaggableType: taggableType, TaggableID: taggableID}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tagging).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	// 已存在时返回原有关联
+	if tagging.TaggingID == 0 {
+		if err := db.Where("tag_id = ? AND taggable_type = ? AND taggable_id = ?", tag.TagID, taggableType, taggableID).
+			First(&tagging).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, model.Response[model.Tagging]{Success: true, Data: tagging})
+}
+
+// DetachTag godoc
+// @Summary 移除实体的标签
+// @Description 移除文章、访问记录、商铺、评论或设施上的一个标签
+// @Tags Taggings
+// @Accept json
+// @Produce json
+// @Param taggable_type path string true "实体类型（Article, History, Store, Comment, Facility）"
+// @Param taggable_id path int true "实体ID"
+// @Param tag_id path int true "标签ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/taggings/{taggable_type}/{taggable_id}/{tag_id} [delete]
+func DetachTag(c *gin.Context) {
+	taggableType, taggableID, ok := taggableParams(c)
+	if !ok {
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误"})
+		return
+	}
+	db := database.GetDB()
+	result := db.Where("tag_id = ? AND taggable_type = ? AND taggable_id = ?", tagID, taggableType, taggableID).
+		Delete(&model.Tagging{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "未添加该标签"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// taggableParams 解析路径中的实体类型与ID，失败时写入 400 响应
+func taggableParams(c *gin.Context) (string, int, bool) {
+	taggableType := c.Param("taggable_type")
+	if !model.TaggableTypes[taggableType] {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "不支持的实体类型: " + taggableType})
+		return "", 0, false
+	}
+	taggableID, err := strconv.Atoi(c.Param("taggable_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "参数错误"})
+		return "", 0, false
+	}
+	return taggableType, taggableID, true
+}
+
+// filterByTags 按标签筛选实体：any 为含任一标签，all 为含全部标签
+// idColumn 为实体主键列（如 stores.store_id）；tagIDs 为空时不筛选
+func filterByTags(query *gorm.DB, taggableType, idColumn string, tagIDs []int, match string) *gorm.DB {
+	if len(tagIDs) == 0 {
+		return query
+	}
+	seen := map[int]bool{}
+	ids := make([]int, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	need := 1
+	if match == "all" {
+		need = len(ids)
+	}
+	return query.Where(idColumn+` IN (SELECT taggable_id FROM taggings
+		WHERE taggable_type = ? AND tag_id IN ? GROUP BY taggable_id HAVING COUNT(DISTINCT tag_id) >= ?)`,
+		taggableType, ids, need)
+}
+
+// deleteTaggings 删除某实体的全部标签关联，在删除实体时调用
+func deleteTaggings(tx *gorm.DB, taggableType string, id int) error {
+	return tx.Where("taggable_type = ? AND taggable_id = ?", taggableType, id).Delete(&model.Tagging{}).Error
+}
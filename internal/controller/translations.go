@@ -0,0 +1,464 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/translation"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateTranslation godoc
+// @Summary 新建译文
+// @Description 为实体字段新建指定语言的译文，已存在时覆盖
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param translation body model.TranslationReqCreate true "译文信息"
+// @Success 200 {object} model.Response[model.Translation]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations [post]
+func CreateTranslation(c *gin.Context) {
+	var req model.TranslationReqCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	entity, ok := model.TranslatableEntities[req.TranslatableType]
+	if !ok || !containsString(entity.Fields, req.FieldName) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "该实体或字段不支持翻译"})
+		return
+	}
+	lang := i18n.Normalize(req.LanguageCode)
+	if lang == i18n.DefaultLanguage {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "原文语言无需翻译，请直接修改原文"})
+		return
+	}
+
+	db := database.GetDB()
+	var count int64
+	db.Table(entity.Table).Where(entity.PrimaryKey+" = ?", req.TranslatableID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "翻译对象不存在"})
+		return
+	}
+
+	translation := model.Translation{
+		TranslatableType: req.TranslatableType,
+		TranslatableID:   req.TranslatableID,
+		FieldName:        req.FieldName,
+		LanguageCode:     lang,
+		Content:          req.Content,
+		Source:           model.TranslationSourceHuman,
+		IsApproved:       true,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "translatable_type"}, {Name: "translatable_id"}, {Name: "field_name"}, {Name: "language_code"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"content":     req.Content,
+			"source":      model.TranslationSourceHuman,
+			"is_approved": true,
+			"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(&translation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Translation]{Success: true, Data: translation})
+}
+
+// UpdateTranslation godoc
+// @Summary 更新译文
+// @Description 更新译文内容，更新后视为人工译文
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param translation body model.TranslationReqEdit true "译文信息"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations [put]
+func UpdateTranslation(c *gin.Context) {
+	var req model.TranslationReqEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var translation model.Translation
+	if err := db.First(&translation, req.TranslationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "译文不存在"})
+		return
+	}
+	db.Model(&translation).Updates(map[string]interface{}{
+		"content":     req.Content,
+		"source":      model.TranslationSourceHuman,
+		"is_approved": true,
+	})
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// ApproveTranslation godoc
+// @Summary 审核机器译文
+// @Description 将机器译文标记为已审核，content 不为空时同时修正译文
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param translation_id path int true "译文ID"
+// @Param req body model.TranslationReqApprove true "修正后的译文（可选）"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations/{translation_id}/approve [put]
+func ApproveTranslation(c *gin.Context) {
+	translationID, _ := strconv.Atoi(c.Param("translation_id"))
+	var req model.TranslationReqApprove
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var t model.Translation
+	if err := db.First(&t, translationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "译文不存在"})
+		return
+	}
+	updates := map[string]interface{}{"is_approved": true}
+	if req.Content != "" {
+		updates["content"] = req.Content
+	}
+	if err := db.Model(&t).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// ListReviewTranslations godoc
+// @Summary 获取待审核译文列表
+// @Description 获取尚未审核的机器译文分页列表，附带原文
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param req body model.TranslationReqReview true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.TranslationReview]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations/review/list [post]
+func ListReviewTranslations(c *gin.Context) {
+	var req model.TranslationReqReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var translations []model.Translation
+	var total int64
+
+	query := db.Model(&model.Translation{}).
+		Where("source = ? AND is_approved = ?", model.TranslationSourceMachine, false)
+	if req.TranslatableType != "" {
+		query = query.Where("translatable_type = ?", req.TranslatableType)
+	}
+	if req.LanguageCode != "" {
+		query = query.Where("language_code = ?", i18n.Normalize(req.LanguageCode))
+	}
+	query.Count(&total)
+	query.Order("translation_id").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&translations)
+
+	// 按实体字段批量读取原文
+	type key struct{ entityType, field string }
+	ids := map[key][]int{}
+	for _, t := range translations {
+		k := key{t.TranslatableType, t.FieldName}
+		ids[k] = append(ids[k], t.TranslatableID)
+	}
+	texts := map[key]map[int]string{}
+	for k, v := range ids {
+		m, err := translation.SourceTexts(db, k.entityType, k.field, v)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+			return
+		}
+		texts[k] = m
+	}
+
+	list := make([]model.TranslationReview, len(translations))
+	for i, t := range translations {
+		list[i] = model.TranslationReview{
+			Translation: t,
+			SourceText:  texts[key{t.TranslatableType, t.FieldName}][t.TranslatableID],
+		}
+	}
+	c.JSON(http.StatusOK, model.ListResponse[model.TranslationReview]{
+		Success: true,
+		Total:   total,
+		List:    list,
+	})
+}
+
+// DeleteTranslation godoc
+// @Summary 删除译文
+// @Description 删除一条译文
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param translation_id path int true "译文ID"
+// @Success 200 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations/{translation_id} [delete]
+func DeleteTranslation(c *gin.Context) {
+	translationID, _ := strconv.Atoi(c.Param("translation_id"))
+	db := database.GetDB()
+	if err := db.Delete(&model.Translation{}, translationID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
+// GetTranslation godoc
+// @Summary 获取单个译文
+// @Description 获取一条译文
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param translation_id path int true "译文ID"
+// @Success 200 {object} model.Response[model.Translation]
+// @Failure 404 {object} model.BaseResponse
+// @Router /api/translations/{translation_id} [get]
+func GetTranslation(c *gin.Context) {
+	translationID, _ := strconv.Atoi(c.Param("translation_id"))
+	db := database.GetDB()
+	var translation model.Translation
+	if err := db.First(&translation, translationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "译文不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.Response[model.Translation]{Success: true, Data: translation})
+}
+
+// ListTranslations godoc
+// @Summary 获取译文列表
+// @Description 获取译文分页列表，可按实体与语言筛选
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param req body model.TranslationReqList true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.Translation]
+// @Failure 400 {object} model.BaseResponse
+// @Router /api/translations/list [post]
+func ListTranslations(c *gin.Context) {
+	var req model.TranslationReqList
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var translations []model.Translation
+	var total int64
+
+	query := db.Model(&model.Translation{})
+	if req.TranslatableType != "" {
+		query = query.Where("translatable_type = ?", req.TranslatableType)
+	}
+	if req.TranslatableID != 0 {
+		query = query.Where("translatable_id = ?", req.TranslatableID)
+	}
+	if req.LanguageCode != "" {
+		query = query.Where("language_code = ?", i18n.Normalize(req.LanguageCode))
+	}
+	if req.Keyword != "" {
+		query = query.Where("content LIKE ?", "%"+req.Keyword+"%")
+	}
+	query.Count(&total)
+	query.Order("translation_id").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&translations)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.Translation]{
+		Success: true,
+		Total:   total,
+		List:    translations,
+	})
+}
+
+// ListMissingTranslations godoc
+// @Summary 获取缺失译文列表
+// @Description 列出原文非空、但在启用语言中尚无译文的实体字段
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param req body model.TranslationReqMissing true "分页与筛选"
+// @Success 200 {object} model.ListResponse[model.MissingTranslation]
+// @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/translations/missing [post]
+func ListMissingTranslations(c *gin.Context) {
+	var req model.TranslationReqMissing
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if req.TranslatableType != "" {
+		if _, ok := model.TranslatableEntities[req.TranslatableType]; !ok {
+			c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "该实体不支持翻译"})
+			return
+		}
+	}
+
+	db := database.GetDB()
+	var missing []model.MissingTranslation
+	var total int64
+
+	var types []string
+	if req.TranslatableType != "" {
+		types = []string{req.TranslatableType}
+	}
+	query := translation.MissingQuery(db, types, i18n.Normalize(req.LanguageCode))
+	query.Count(&total)
+	query.Order("translatable_type").Order("translatable_id").Order("field_name").Order("language_code").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Scan(&missing)
+
+	c.JSON(http.StatusOK, model.ListResponse[model.MissingTranslation]{
+		Success: true,
+		Total:   total,
+		List:    missing,
+	})
+}
+
+// languageChain 返回本次请求的语言回退链，?lang= 优先于 Accept-Language 请求头
+func languageChain(c *gin.Context) []string {
+	if lang := c.Query("lang"); lang != "" {
+		return i18n.Chain(strings.Split(lang, ",")...)
+	}
+	return i18n.Chain(i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+}
+
+// translationTarget 待本地化的实体：ID 与可翻译字段（列名 → 结构体字段）
+type translationTarget struct {
+	id     int
+	fields map[string]*string
+}
+
+// localize 按请求的语言回退链，用译文替换实体字段
+// 每个字段取回退链中最靠前的译文；原文语言排在更前面时保留原文
+// 未审核的机器译文同样返回，比直接显示日文原文更适合外国游客
+func localize(c *gin.Context, db *gorm.DB, entityType string, targets []translationTarget) {
+	chain := languageChain(c)
+	if len(targets) == 0 || chain[0] == i18n.DefaultLanguage {
+		return
+	}
+	rank := make(map[string]int, len(chain))
+	for i, lang := range chain {
+		rank[lang] = i
+	}
+	ids := make([]int, len(targets))
+	for i, t := range targets {
+		ids[i] = t.id
+	}
+
+	var translations []model.Translation
+	db.Where("translatable_type = ? AND translatable_id IN ? AND language_code IN ?", entityType, ids, chain).
+		Find(&translations)
+
+	type key struct {
+		id    int
+		field string
+	}
+	best := map[key]model.Translation{}
+	for _, t := range translations {
+		k := key{t.TranslatableID, t.FieldName}
+		if cur, ok := best[k]; !ok || rank[t.LanguageCode] < rank[cur.LanguageCode] {
+			best[k] = t
+		}
+	}
+	for _, target := range targets {
+		for field, ptr := range target.fields {
+			if t, ok := best[key{target.id, field}]; ok && rank[t.LanguageCode] < rank[i18n.DefaultLanguage] {
+				*ptr = t.Content
+			}
+		}
+	}
+}
+
+func localizeFacilities(c *gin.Context, db *gorm.DB, facilities []*model.Facility) {
+	targets := make([]translationTarget, len(facilities))
+	for i, f := range facilities {
+		targets[i] = translationTarget{f.FacilityID, map[string]*string{
+			"facility_name":    &f.FacilityName,
+			"description_text": &f.DescriptionText,
+		}}
+	}
+	localize(c, db, model.EntityFacility, targets)
+}
+
+func localizeStores(c *gin.Context, db *gorm.DB, stores []*model.Store) {
+	targets := make([]translationTarget, len(stores))
+	for i, s := range stores {
+		targets[i] = translationTarget{s.StoreID, map[string]*string{
+			"store_name":       &s.StoreName,
+			"description_text": &s.DescriptionText,
+		}}
+	}
+	localize(c, db, model.EntityStore, targets)
+}
+
+func localizeArticles(c *gin.Context, db *gorm.DB, articles []model.Article) {
+	targets := make([]translationTarget, len(articles))
+	for i := range articles {
+		a := &articles[i]
+		targets[i] = translationTarget{a.ArticleID, map[string]*string{
+			"title":     &a.Title,
+			"body_text": &a.BodyText,
+		}}
+	}
+	localize(c, db, model.EntityArticle, targets)
+}
+
+func localizeNotices(c *gin.Context, db *gorm.DB, notices []model.Notice) {
+	targets := make([]translationTarget, len(notices))
+	for i := range notices {
+		n := &notices[i]
+		targets[i] = translationTarget{n.NoticeID, map[string]*string{
+			"title":   &n.Title,
+			"content": &n.Content,
+		}}
+	}
+	localize(c, db, model.EntityNotice, targets)
+}
+
+func localizeMenuItems(c *gin.Context, db *gorm.DB, items []model.StoreMenuItem) {
+	targets := make([]translationTarget, len(items))
+	for i := range items {
+		m := &items[i]
+		targets[i] = translationTarget{m.MenuItemID, map[string]*string{
+			"section":     &m.Section,
+			"item_name":   &m.ItemName,
+			"description": &m.Description,
+		}}
+	}
+	localize(c, db, model.EntityMenuItem, targets)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
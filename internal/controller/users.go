@@ -18,6 +18,7 @@ import (
 // @Success 200 {object} model.Response[model.User]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/users [post]
 func CreateUser(c *gin.Context) {
 	var req model.UserReqCreate
@@ -26,6 +27,14 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	if req.Role == "" {
+		req.Role = model.RoleTourist
+	}
+	if !model.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "角色不合法"})
+		return
+	}
+
 	user := model.User{
 		Name:        req.Name,
 		NameKana:    req.NameKana,
@@ -36,8 +45,10 @@ func CreateUser(c *gin.Context) {
 		Password:    req.Password,
 		GoogleID:    req.GoogleID,
 		AppleID:     req.AppleID,
+		LineID:      req.LineID,
 		Provider:    req.Provider,
 		Status:      req.Status,
+		Role:        req.Role,
 	}
 
 	db := database.GetDB()
@@ -59,6 +70,7 @@ func CreateUser(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/users [put]
 func UpdateUser(c *gin.Context) {
 	var req model.UserReqEdit
@@ -78,6 +90,43 @@ func UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
 }
 
+// UpdateUserRole godoc
+// @Summary 修改用户角色
+// @Description 管理员修改指定用户的角色（admin, editor, store_owner, tourist），新角色在用户下次刷新token后生效
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param req body model.UserReqRole true "用户ID与角色"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
+// @Router /api/users/role [put]
+func UpdateUserRole(c *gin.Context) {
+	var req model.UserReqRole
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !model.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: "角色不合法"})
+		return
+	}
+
+	db := database.GetDB()
+	result := db.Model(&model.User{}).Where("user_id = ?", req.UserID).Update("role", req.Role)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, model.BaseResponse{Success: false, ErrMessage: "用户不存在"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true})
+}
+
 // DeleteUser godoc
 // @Summary 删除用户
 // @Description 删除一个用户
@@ -88,6 +137,7 @@ func UpdateUser(c *gin.Context) {
 // @Success 200 {object} model.BaseResponse
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/users/{user_id} [delete]
 func DeleteUser(c *gin.Context) {
 	id := c.Param("user_id")
@@ -110,6 +160,7 @@ func DeleteUser(c *gin.Context) {
 // @Success 200 {object} model.Response[model.User]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/users/{user_id} [get]
 func GetUser(c *gin.Context) {
 	id := c.Param("user_id")
@@ -132,6 +183,7 @@ func GetUser(c *gin.Context) {
 // @Param req body model.UserReqList true "分页与搜索"
 // @Success 200 {object} model.ListResponse[model.User]
 // @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/users/list [post]
 func ListUsers(c *gin.Context) {
 	var req model.UserReqList
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"travel-ar-backend/internal/mailer"
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/database"
+	"travel-ar-backend/pkg/randcode"
+	"travel-ar-backend/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	verifyCodeLength       = 6                // 验证码位数
+	verifyCodeTTL          = 10 * time.Minute // 验证码有效期
+	maxVerifyAttempts      = 5                // 每个验证码允许的错误次数，超过后需重新获取
+	verifyResendCooling    = time.Minute      // 两次发送验证码的最短间隔
+	maxVerifyResendPerHour = 5                // 同一账号每小时最多重新发送的次数，限制可尝试的验证码总数
+	verifySendTimeout      = 30 * time.Second // 发送邮件超时
+	verifyRequestPerIPHour = 10               // 同一 IP 每小时最多申请重新发送的次数
+)
+
+// verifyResendLimiter 按账号限制重新发送验证码的次数
+var verifyResendLimiter = ratelimit.New(maxVerifyResendPerHour, time.Hour)
+
+// verifyRequestLimiter 按客户端 IP 限制重新发送申请的频率
+var verifyRequestLimiter = ratelimit.New(verifyRequestPerIPHour, time.Hour)
+
+// verifyResendAccepted 重新发送验证码的统一响应，不论邮箱是否注册、是否已验证都相同，避免泄露账号信息
+const verifyResendAccepted = "如果该邮箱正在等待验证，我们已重新发送验证码，请查收"
+
+// errVerifyRejected 验证或重发条件不满足，status 与 message 直接返回给客户端
+type errVerifyRejected struct {
+	status  int
+	message string
+}
+
+func (e *errVerifyRejected) Error() string { return e.message }
+
+// VerifyEmail godoc
+// @Summary 邮箱验证
+// @Description 校验注册时发送到邮箱的验证码，成功后激活账号并返回登录结果。每个验证码最多可错误 5 次，超过后需重新获取
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.VerifyRequest true "邮箱与验证码"
+// @Param X-Device-Name header string false "设备名称，显示在登录会话列表中"
+// @Success 200 {object} model.Response[model.AuthResponse]
+// @Failure 400 {object} model.BaseResponse
+// @Failure 404 {object} model.BaseResponse
+// @Failure 429 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/verify [post]
+func VerifyEmail(c *gin.Context) {
+	var req model.VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	db := database.GetDB()
+	var user model.User
+	var rejected *errVerifyRejected
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("email = ?", req.Email).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				rejected = &errVerifyRejected{http.StatusNotFound, "用户不存在"}
+				return nil
+			}
+			return err
+		}
+		if user.Status != model.UserStatusPending {
+			rejected = &errVerifyRejected{http.StatusBadRequest, "账号已验证"}
+			return nil
+		}
+		if user.VerifyAttempts >= maxVerifyAttempts {
+			rejected = &errVerifyRejected{http.StatusTooManyRequests, "验证码错误次数过多，请重新获取验证码"}
+			return nil
+		}
+		if user.VerifyCode == "" || user.VerifyCodeExpire == nil || time.Now().After(*user.VerifyCodeExpire) {
+			rejected = &errVerifyRejected{http.StatusBadRequest, "验证码已过期，请重新获取验证码"}
+			return nil
+		}
+		if subtle.ConstantTimeCompare([]byte(req.Code), []byte(user.VerifyCode)) != 1 {
+			// 错误次数需要保存，不回滚事务
+			rejected = &errVerifyRejected{http.StatusBadRequest,
+				fmt.Sprintf("验证码错误，还可尝试 %d 次", maxVerifyAttempts-user.VerifyAttempts-1)}
+			return tx.Model(&user).Update("verify_attempts", gorm.Expr("verify_attempts + 1")).Error
+		}
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"status":             model.UserStatusActive,
+			"verify_code":        "",
+			"verify_code_expire": nil,
+			"verify_attempts":    0,
+			"updated_at":         time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		// 以第三方身份注册的账号，验证通过后才绑定该身份
+		return applyVerifiedLinks(tx, &user)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if rejected != nil {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	respondAuth(c, db, user)
+}
+
+// ResendVerifyCode godoc
+// @Summary 重新发送验证码
+// @Description 为待验证账号生成新的验证码并发送到邮箱，旧验证码失效。无论邮箱是否注册、是否已验证都返回相同结果；两次发送至少间隔 1 分钟，同一账号每小时最多发送 5 次，同一 IP 每小时最多申请 10 次
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.ResendVerifyRequest true "邮箱"
+// @Param lang query string false "邮件语言（如 en, zh-TW），未指定时按 Accept-Language"
+// @Success 200 {object} model.BaseResponse
+// @Failure 400 {object} model.BaseResponse
+// @Failure 429 {object} model.BaseResponse
+// @Failure 500 {object} model.BaseResponse
+// @Router /api/verify/resend [post]
+func ResendVerifyCode(c *gin.Context) {
+	var req model.ResendVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.BaseResponse{Success: false, ErrMessage: err.Error()})
+		return
+	}
+	if !verifyRequestLimiter.Allow(c.ClientIP()) {
+		c.JSON(http.StatusTooManyRequests, model.BaseResponse{Success: false, ErrMessage: "请求过于频繁，请稍后再试"})
+		return
+	}
+	user, err := renewVerifyCode(database.GetDB(), req.Email)
+	var rejected *errVerifyRejected
+	if err != nil && !errors.As(err, &rejected) {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "验证码更新失败: " + err.Error()})
+		return
+	}
+	// 账号不存在、已验证或发送受限时不发送邮件，响应与正常发送相同；邮件在后台发送，耗时也一致
+	if err == nil {
+		langs := languageChain(c)
+		go func() {
+			if err := deliverVerifyCode(context.Background(), langs, user); err != nil {
+				log.Printf("send verify code to user %d: %v", user.UserID, err)
+			}
+		}()
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: verifyResendAccepted})
+}
+
+// resendVerifyCode 重新生成验证码并同步发送，拒绝原因直接返回给客户端
+func resendVerifyCode(c *gin.Context, db *gorm.DB, email string) {
+	user, err := renewVerifyCode(db, email)
+	var rejected *errVerifyRejected
+	if errors.As(err, &rejected) {
+		c.JSON(rejected.status, model.BaseResponse{Success: false, ErrMessage: rejected.message})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "验证码更新失败: " + err.Error()})
+		return
+	}
+	if err := sendVerifyCode(c, user); err != nil {
+		log.Printf("send verify code to user %d: %v", user.UserID, err)
+		c.JSON(http.StatusInternalServerError, model.BaseResponse{Success: false, ErrMessage: "验证邮件发送失败，请稍后重新发送"})
+		return
+	}
+	c.JSON(http.StatusOK, model.BaseResponse{Success: true, ErrMessage: "验证码已重新发送，请查收邮箱"})
+}
+
+// renewVerifyCode 为待验证账号重新生成验证码并重置错误次数，受发送间隔限制；不满足条件时返回 *errVerifyRejected
+func renewVerifyCode(db *gorm.DB, email string) (model.User, error) {
+	var user model.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("email = ?", email).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &errVerifyRejected{http.StatusNotFound, "用户不存在"}
+			}
+			return err
+		}
+		if user.Status != model.UserStatusPending {
+			return &errVerifyRejected{http.StatusBadRequest, "账号已验证"}
+		}
+		now := time.Now()
+		if user.VerifySentAt != nil {
+			if wait := user.VerifySentAt.Add(verifyResendCooling).Sub(now); wait > 0 {
+				return &errVerifyRejected{http.StatusTooManyRequests,
+					fmt.Sprintf("发送过于频繁，请 %d 秒后再试", int(wait.Seconds())+1)}
+			}
+		}
+		if !verifyResendLimiter.Allow(strconv.Itoa(user.UserID)) {
+			return &errVerifyRejected{http.StatusTooManyRequests, "验证码发送次数过多，请 1 小时后再试"}
+		}
+		expire := now.Add(verifyCodeTTL)
+		user.VerifyCode = randcode.Digits(verifyCodeLength)
+		user.VerifyCodeExpire = &expire
+		user.VerifyAttempts = 0
+		user.VerifySentAt = &now
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"verify_code":        user.VerifyCode,
+			"verify_code_expire": expire,
+			"verify_attempts":    0,
+			"verify_sent_at":     now,
+			"updated_at":         now,
+		}).Error
+	})
+	return user, err
+}
+
+// sendVerifyCode 按请求语言渲染验证码邮件并发送
+func sendVerifyCode(c *gin.Context, user model.User) error {
+	return deliverVerifyCode(c.Request.Context(), languageChain(c), user)
+}
+
+// deliverVerifyCode 按语言链渲染验证码邮件并发送
+func deliverVerifyCode(ctx context.Context, langs []string, user model.User) error {
+	msg, err := mailer.Render(mailer.TemplateVerifyCode, langs, map[string]interface{}{
+		"Code":          user.VerifyCode,
+		"ExpireMinutes": int(verifyCodeTTL / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	ctx, cancel := context.WithTimeout(ctx, verifySendTimeout)
+	defer cancel()
+	return mailer.Default().Send(ctx, msg)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"travel-ar-backend/internal/model"
+)
+
+func TestResendVerifyCodeDoesNotRevealAccounts(t *testing.T) {
+	db := setupDB(t)
+	pending := createUser(t, db, testEmail(t), "secret123", model.UserStatusPending)
+	active := createUser(t, db, testEmail(t), "secret123", model.UserStatusActive)
+
+	var first string
+	for i, email := range []string{pending.Email, active.Email, testEmail(t)} {
+		w := serve(t, ResendVerifyCode, request{
+			method: http.MethodPost,
+			target: "/api/verify/resend",
+			body:   model.ResendVerifyRequest{Email: email},
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body %s", email, w.Code, w.Body.String())
+		}
+		if i == 0 {
+			first = w.Body.String()
+		} else if w.Body.String() != first {
+			t.Fatalf("%s: body = %s, want %s", email, w.Body.String(), first)
+		}
+	}
+
+	var renewed model.User
+	db.First(&renewed, pending.UserID)
+	if renewed.VerifyCode == "" {
+		t.Fatal("pending account did not get a new verify code")
+	}
+}
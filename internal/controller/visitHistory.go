@@ -12,7 +12,7 @@ import (
 
 // CreateVisitHistory godoc
 // @Summary 新建访问记录
-// @Description 新建一个访问记录
+// @Description 管理员手动补录访问记录；用户签到请使用 /api/checkin/scan
 // @Tags VisitHistories
 // @Accept json
 // @Produce json
@@ -20,6 +20,7 @@ import (
 // @Success 200 {object} model.Response[model.VisitHistory]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/visit_history [post]
 func CreateVisitHistory(c *gin.Context) {
 	var req model.VisitHistoryReqCreate
@@ -53,6 +54,7 @@ func CreateVisitHistory(c *gin.Context) {
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/visit_history [put]
 func UpdateVisitHistory(c *gin.Context) {
 	var req model.VisitHistoryReqEdit
@@ -86,6 +88,7 @@ func UpdateVisitHistory(c *gin.Context) {
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
 // @Failure 500 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/visit_history/{history_id} [delete]
 func DeleteVisitHistory(c *gin.Context) {
 	id := c.Param("history_id")
@@ -112,6 +115,7 @@ func DeleteVisitHistory(c *gin.Context) {
 // @Success 200 {object} model.Response[model.VisitHistory]
 // @Failure 400 {object} model.BaseResponse
 // @Failure 404 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/visit_history/{history_id} [get]
 func GetVisitHistory(c *gin.Context) {
 	id := c.Param("history_id")
@@ -138,6 +142,7 @@ func GetVisitHistory(c *gin.Context) {
 // @Param req body model.VisitHistoryReqList true "分页与搜索"
 // @Success 200 {object} model.ListResponse[model.VisitHistory]
 // @Failure 400 {object} model.BaseResponse
+// @Security ApiKeyAuth
 // @Router /api/visit_history/list [post]
 func ListVisitHistories(c *gin.Context) {
 	var req model.VisitHistoryReqList
@@ -157,3 +162,23 @@ func ListVisitHistories(c *gin.Context) {
 		List:    histories,
 	})
 }
+
+// ListMyVisitHistories godoc
+// @Summary 获取我的访问记录
+// @Description 获取当前用户的访问记录，按扫码时间倒序
+// @Tags VisitHistories
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ListResponse[model.VisitHistory]
+// @Security ApiKeyAuth
+// @Router /api/visit_history/mine [get]
+func ListMyVisitHistories(c *gin.Context) {
+	db := database.GetDB()
+	var histories []model.VisitHistory
+	db.Where("user_id = ? AND is_active = ?", c.GetInt("user_id"), true).Order("scan_at DESC").Find(&histories)
+	c.JSON(http.StatusOK, model.ListResponse[model.VisitHistory]{
+		Success: true,
+		Total:   int64(len(histories)),
+		List:    histories,
+	})
+}
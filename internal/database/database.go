@@ -5,8 +5,6 @@ import (
 	"log"
 	"os"
 	"strconv"
-	"time"
-	"travel-ar-backend/internal/model"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/joho/godotenv/autoload"
@@ -23,13 +21,6 @@ type Service interface {
 	// Close terminates the database connection.
 	// It returns an error if the connection cannot be closed.
 	Close() error
-
-	GetUserByEmail(email string) (*model.User, error)
-	CreateUser(user model.User) (*model.User, error)
-	UpdateUserGoogleInfo(userID int, googleID, avatar string) error
-	GetUserByID(userID int) (*model.User, error)
-	SaveRefreshToken(userID int, refreshToken string, expiresAt time.Time) error
-	GetRefreshToken(token string) (*model.RefreshToken, error)
 }
 
 type service struct {
@@ -61,6 +52,11 @@ func New() Service {
 	return dbInstance
 }
 
+// Wrap 使用已建立的连接创建 Service，避免重复连接数据库
+func Wrap(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
 func (s *service) Health() map[string]string {
@@ -116,57 +112,3 @@ func (s *service) Close() error {
 	log.Printf("Disconnected from database: %s", database)
 	return db.Close()
 }
-
-func (s *service) GetUserByEmail(email string) (*model.User, error) {
-	user := &model.User{}
-	err := s.db.Where("email = ?", email).First(user).Error
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-func (s *service) CreateUser(user model.User) (*model.User, error) {
-	err := s.db.Create(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-func (s *service) UpdateUserGoogleInfo(userID int, googleID, avatar string) error {
-	return s.db.Model(&model.User{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
-		"google_id":  googleID,
-		"avatar":     avatar,
-		"updated_at": time.Now(),
-	}).Error
-}
-
-func (s *service) GetUserByID(userID int) (*model.User, error) {
-	user := &model.User{}
-	err := s.db.Where("user_id = ?", userID).First(user).Error
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-func (s *service) SaveRefreshToken(userID int, refreshToken string, expiresAt time.Time) error {
-	rt := model.RefreshToken{
-		UserID:       userID,
-		RefreshToken: refreshToken,
-		ExpiresAt:    expiresAt,
-		CreatedAt:    time.Now(),
-		Revoked:      false,
-	}
-	return s.db.Create(&rt).Error
-}
-
-func (s *service) GetRefreshToken(token string) (*model.RefreshToken, error) {
-	rt := &model.RefreshToken{}
-	err := s.db.Where("refresh_token = ? AND revoked = FALSE", token).First(rt).Error
-	if err != nil {
-		return nil, err
-	}
-	return rt, nil
-}
@@ -0,0 +1,65 @@
+// Package dbtest 为需要数据库的测试提供临时 PostgreSQL
+//
+// 设置 TEST_DATABASE_DSN 时直接连接该数据库；否则通过 testcontainers 启动容器，
+// 同一测试进程内共用一个容器，进程结束后由 testcontainers 回收。Docker 不可用时跳过测试。
+package dbtest
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	once   sync.Once
+	shared *gorm.DB
+	err    error
+)
+
+// Open 返回测试用数据库连接
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		testcontainers.SkipIfProviderIsNotHealthy(t)
+	}
+	once.Do(func() {
+		if dsn == "" {
+			dsn, err = startContainer()
+			if err != nil {
+				return
+			}
+		}
+		shared, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	})
+	if err != nil {
+		t.Fatalf("dbtest: %v", err)
+	}
+	return shared
+}
+
+func startContainer() (string, error) {
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, "postgres:latest",
+		tcpostgres.WithDatabase("database"),
+		tcpostgres.WithUsername("user"),
+		tcpostgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil {
+		return "", err
+	}
+	return container.ConnectionString(ctx, "sslmode=disable")
+}
@@ -0,0 +1,228 @@
+package idp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// mockIssuer 本地模拟签发方：提供 JWKS 并签发 ID token
+type mockIssuer struct {
+	server  *httptest.Server
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	fetches atomic.Int32
+	keys    atomic.Value // []map[string]string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{rsaKey: rsaKey, ecKey: ecKey}
+	m.publish("rsa1", "ec1")
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": m.keys.Load()})
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// publish 以指定 kid 公开两把公钥，模拟提供方轮换密钥
+func (m *mockIssuer) publish(rsaKid, ecKid string) {
+	b64 := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	m.keys.Store([]map[string]string{
+		{"kty": "RSA", "kid": rsaKid, "use": "sig", "alg": "RS256", "n": b64(m.rsaKey.N), "e": b64(big.NewInt(int64(m.rsaKey.E)))},
+		{"kty": "EC", "kid": ecKid, "crv": "P-256", "x": b64(m.ecKey.X), "y": b64(m.ecKey.Y)},
+		{"kty": "oct", "kid": "ignored", "k": "c2VjcmV0"},
+	})
+}
+
+func (m *mockIssuer) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	tok.Header["kid"] = kid
+	var key interface{} = m.rsaKey
+	if method == jwt.SigningMethodES256 {
+		key = m.ecKey
+	}
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+var testNow = time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
+func (m *mockIssuer) verifier(provider string, methods ...string) *OIDC {
+	keys := NewKeySet(m.server.URL, m.server.Client())
+	keys.now = func() time.Time { return testNow }
+	return &OIDC{
+		Provider:  provider,
+		Issuers:   []string{"https://issuer.test"},
+		Audiences: []string{"com.example.app", "web-client"},
+		Keys:      keys,
+		Methods:   methods,
+		now:       func() time.Time { return testNow },
+	}
+}
+
+func baseClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   "https://issuer.test",
+		"aud":   "com.example.app",
+		"sub":   "001234.abcd",
+		"iat":   testNow.Add(-time.Minute).Unix(),
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"email": "hanako@example.jp",
+		"nonce": "n-0S6",
+	}
+}
+
+func TestVerifyApple(t *testing.T) {
+	m := newMockIssuer(t)
+	v := m.verifier(Apple, "RS256")
+	claims := baseClaims()
+	claims["email_verified"] = "true" // Apple 使用字符串
+	id, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", claims), "n-0S6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Identity{Provider: Apple, Subject: "001234.abcd", Email: "hanako@example.jp", EmailVerified: true}
+	if *id != want {
+		t.Errorf("identity = %+v, want %+v", *id, want)
+	}
+}
+
+func TestVerifyLINE(t *testing.T) {
+	m := newMockIssuer(t)
+	v := m.verifier(LINE, "ES256")
+	claims := baseClaims()
+	claims["aud"] = []string{"other", "web-client"}
+	claims["name"] = "山田花子"
+	id, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodES256, "ec1", claims), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Name != "山田花子" || id.EmailVerified {
+		t.Errorf("identity = %+v", *id)
+	}
+	// 签名算法不在允许范围内
+	if _, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", claims), ""); err != ErrInvalidToken {
+		t.Errorf("RS256 token accepted by ES256 verifier: %v", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	m := newMockIssuer(t)
+	v := m.verifier(Google, "RS256")
+	cases := []struct {
+		name   string
+		mutate func(jwt.MapClaims)
+		nonce  string
+	}{
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.test" }, ""},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "someone-else" }, ""},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = testNow.Add(-2 * time.Minute).Unix() }, ""},
+		{"missing exp", func(c jwt.MapClaims) { delete(c, "exp") }, ""},
+		{"issued in future", func(c jwt.MapClaims) { c["iat"] = testNow.Add(10 * time.Minute).Unix() }, ""},
+		{"missing subject", func(c jwt.MapClaims) { delete(c, "sub") }, ""},
+		{"nonce mismatch", func(c jwt.MapClaims) {}, "other"},
+	}
+	for _, c := range cases {
+		claims := baseClaims()
+		c.mutate(claims)
+		if _, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", claims), c.nonce); err != ErrInvalidToken {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", c.name, err)
+		}
+	}
+
+	// 其他密钥签名
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, baseClaims())
+	tok.Header["kid"] = "rsa1"
+	forged, _ := tok.SignedString(other)
+	if _, err := v.Verify(context.Background(), forged, ""); err != ErrInvalidToken {
+		t.Errorf("forged token: err = %v", err)
+	}
+	// 对称算法（用公钥当 HMAC 密钥的攻击）
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, baseClaims())
+	hs.Header["kid"] = "rsa1"
+	hsToken, _ := hs.SignedString([]byte("secret"))
+	if _, err := v.Verify(context.Background(), hsToken, ""); err != ErrInvalidToken {
+		t.Errorf("HS256 token: err = %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	m := newMockIssuer(t)
+	v := m.verifier(Apple, "RS256")
+	now := testNow
+	v.Keys.now = func() time.Time { return now }
+
+	if _, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", baseClaims()), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", baseClaims()), ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.fetches.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1 (cached)", got)
+	}
+
+	// 提供方轮换密钥后，未知 kid 触发重新获取，但受最短间隔限制
+	m.publish("rsa2", "ec2")
+	rotated := m.sign(t, jwt.SigningMethodRS256, "rsa2", baseClaims())
+	if _, err := v.Verify(context.Background(), rotated, ""); err != ErrInvalidToken {
+		t.Errorf("refetched within min interval: err = %v", err)
+	}
+	now = now.Add(keySetMinRefresh)
+	if _, err := v.Verify(context.Background(), rotated, ""); err != nil {
+		t.Errorf("rotated key: %v", err)
+	}
+	if got := m.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	m := newMockIssuer(t)
+	t.Setenv("GOOGLE_CLIENT_ID", "")
+	t.Setenv("GOOGLE_APP_CLIENT_IDS", "")
+	t.Setenv("LINE_CHANNEL_ID", "")
+	t.Setenv("APPLE_CLIENT_ID", "com.example.app, web-client")
+	t.Setenv("APPLE_JWKS_URL", m.server.URL)
+	t.Setenv("APPLE_ISSUER", "https://issuer.test")
+
+	vs := FromEnv()
+	if len(vs) != 1 {
+		t.Fatalf("providers = %v, want apple only", vs)
+	}
+	apple := vs[Apple].(*OIDC)
+	apple.now = func() time.Time { return testNow }
+	claims := baseClaims()
+	claims["aud"] = "web-client"
+	if _, err := apple.Verify(context.Background(), m.sign(t, jwt.SigningMethodRS256, "rsa1", claims), ""); err != nil {
+		t.Errorf("Verify with env-configured issuer: %v", err)
+	}
+}
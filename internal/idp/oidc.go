@@ -0,0 +1,140 @@
+package idp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// clockSkew 校验有效期时允许的时钟误差
+const clockSkew = time.Minute
+
+// OIDC 按 OpenID Connect 规范校验 ID token
+type OIDC struct {
+	Provider  string
+	Issuers   []string // 允许的 iss
+	Audiences []string // 允许的 aud（本应用的客户端ID）
+	Keys      *KeySet
+	Methods   []string // 允许的签名算法
+
+	now func() time.Time
+}
+
+// NewGoogle Google 登录，audiences 为 OAuth 客户端ID
+func NewGoogle(audiences []string) *OIDC {
+	return &OIDC{
+		Provider:  Google,
+		Issuers:   []string{"https://accounts.google.com", "accounts.google.com"},
+		Audiences: audiences,
+		Keys:      NewKeySet("https://www.googleapis.com/oauth2/v3/certs", nil),
+		Methods:   []string{"RS256"},
+	}
+}
+
+// NewApple Sign in with Apple，audiences 为 Bundle ID（App）或 Services ID（Web）
+func NewApple(audiences []string) *OIDC {
+	return &OIDC{
+		Provider:  Apple,
+		Issuers:   []string{"https://appleid.apple.com"},
+		Audiences: audiences,
+		Keys:      NewKeySet("https://appleid.apple.com/auth/keys", nil),
+		Methods:   []string{"RS256"},
+	}
+}
+
+// NewLINE LINE Login，audiences 为 Channel ID；App（SDK）签发的 ID token 使用 ES256
+func NewLINE(audiences []string) *OIDC {
+	return &OIDC{
+		Provider:  LINE,
+		Issuers:   []string{"https://access.line.me"},
+		Audiences: audiences,
+		Keys:      NewKeySet("https://api.line.me/oauth2/v2.1/certs", nil),
+		Methods:   []string{"ES256"},
+	}
+}
+
+// idClaims ID token 中用到的字段
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	Picture       string   `json:"picture"`
+}
+
+// flexBool Apple 的 email_verified 为字符串 "true"，Google 为布尔值
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*b = flexBool(t)
+	case string:
+		*b = t == "true"
+	}
+	return nil
+}
+
+// Verify 校验签名、iss、aud、exp、iat 与 nonce
+func (o *OIDC) Verify(ctx context.Context, idToken, nonce string) (*Identity, error) {
+	now := time.Now()
+	if o.now != nil {
+		now = o.now()
+	}
+	claims := &idClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(o.Methods), jwt.WithoutClaimsValidation())
+	token, err := parser.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return o.Keys.Key(ctx, kid)
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if !o.validIssuer(claims.Issuer) || !o.validAudience(claims.Audience) || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt == nil || !now.Before(claims.ExpiresAt.Add(clockSkew)) {
+		return nil, ErrInvalidToken
+	}
+	if claims.IssuedAt != nil && now.Add(clockSkew).Before(claims.IssuedAt.Time) {
+		return nil, ErrInvalidToken
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, ErrInvalidToken
+	}
+	return &Identity{
+		Provider:      o.Provider,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified) && claims.Email != "",
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+func (o *OIDC) validIssuer(iss string) bool {
+	for _, v := range o.Issuers {
+		if v == iss {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *OIDC) validAudience(aud jwt.ClaimStrings) bool {
+	for _, a := range aud {
+		for _, v := range o.Audiences {
+			if a == v {
+				return true
+			}
+		}
+	}
+	return false
+}
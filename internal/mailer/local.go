@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFrom 未配置发件地址时文件实现使用的发件人
+const defaultFrom = "Travel AR <no-reply@localhost>"
+
+// File 将邮件写成 .eml 文件，用于开发环境
+type File struct {
+	Dir  string
+	From string
+}
+
+// Send 在 Dir 下写出 <时间>-<收件人>.eml
+func (f *File) Send(_ context.Context, msg Message) error {
+	from := f.From
+	if from == "" {
+		from = defaultFrom
+	}
+	body, err := msg.Bytes(from)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"),
+		strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To))
+	return os.WriteFile(filepath.Join(f.Dir, name), body, 0o644)
+}
+
+// Memory 将邮件保存在内存中，用于测试
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// Send 记录邮件
+func (m *Memory) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages 返回已记录的邮件副本
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,62 @@
+// Package mailer 邮件发送
+//
+// 提供 SMTP 实现、开发环境用的文件实现（写出 .eml 文件）与测试用的内存实现，
+// 以及按语言回退链选择的内嵌邮件模板。
+package mailer
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultMailer Mailer
+)
+
+// Default 返回全局邮件发送实现，首次调用时按环境变量创建
+func Default() Mailer {
+	defaultOnce.Do(func() {
+		if defaultMailer == nil {
+			defaultMailer = FromEnv()
+		}
+	})
+	return defaultMailer
+}
+
+// SetDefault 替换全局邮件发送实现，用于测试
+func SetDefault(m Mailer) {
+	defaultOnce.Do(func() {})
+	defaultMailer = m
+}
+
+// FromEnv 根据环境变量创建邮件发送实现
+// MAILER=smtp 使用 SMTP（SMTP_HOST、SMTP_PORT 默认 587、SMTP_USERNAME、SMTP_PASSWORD、MAIL_FROM）；
+// MAILER=memory 使用内存实现；否则写入 MAIL_DIR 目录（默认 ./mail）下的 .eml 文件
+func FromEnv() Mailer {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTP(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"), 30*time.Second)
+	case "memory":
+		return &Memory{}
+	}
+	dir := os.Getenv("MAIL_DIR")
+	if dir == "" {
+		dir = "mail"
+	}
+	log.Printf("mailer: SMTP not configured, writing mail to %s", dir)
+	return &File{Dir: dir, From: os.Getenv("MAIL_FROM")}
+}
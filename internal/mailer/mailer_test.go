@@ -0,0 +1,142 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderFallback(t *testing.T) {
+	data := map[string]interface{}{"Code": "0427", "ExpireMinutes": 10}
+	cases := []struct {
+		langs   []string
+		subject string
+	}{
+		{[]string{"en"}, "[Travel AR] Your verification code"},
+		{[]string{"zh-TW", "zh"}, "【Travel AR】電子郵件驗證碼"},
+		{[]string{"zh-CN", "zh"}, "【Travel AR】邮箱验证码"},
+		{[]string{"fr"}, "【Travel AR】メールアドレス確認コード"},
+		{nil, "【Travel AR】メールアドレス確認コード"},
+	}
+	for _, c := range cases {
+		msg, err := Render(TemplateVerifyCode, c.langs, data)
+		if err != nil {
+			t.Fatalf("Render(%v): %v", c.langs, err)
+		}
+		if msg.Subject != c.subject {
+			t.Errorf("Render(%v) subject = %q, want %q", c.langs, msg.Subject, c.subject)
+		}
+		if !strings.Contains(msg.Text, "0427") || !strings.Contains(msg.HTML, "<strong") || !strings.Contains(msg.HTML, "0427") {
+			t.Errorf("Render(%v) body missing code: %+v", c.langs, msg)
+		}
+	}
+	if _, err := Render("missing", []string{"en"}, data); err == nil {
+		t.Error("Render(missing) should fail")
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	msg, err := Render(TemplateVerifyCode, []string{"en"}, map[string]interface{}{"Code": "<b>", "ExpireMinutes": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(msg.HTML, "<b>") || !strings.Contains(msg.Text, "<b>") {
+		t.Errorf("html = %q, text = %q", msg.HTML, msg.Text)
+	}
+}
+
+func TestRenderPasswordReset(t *testing.T) {
+	for _, lang := range []string{"ja", "en", "zh", "zh-TW", "ko"} {
+		msg, err := Render(TemplatePasswordReset, []string{lang}, map[string]interface{}{
+			"Token": "TK42", "URL": "", "ExpireMinutes": 30,
+		})
+		if err != nil {
+			t.Fatalf("Render(%s): %v", lang, err)
+		}
+		if !strings.Contains(msg.Text, "TK42") || !strings.Contains(msg.HTML, "TK42") || !strings.Contains(msg.Text, "30") {
+			t.Errorf("Render(%s) body missing token: %+v", lang, msg)
+		}
+	}
+	link := "https://example.com/reset?token=TK42&x=1"
+	msg, err := Render(TemplatePasswordReset, []string{"en"}, map[string]interface{}{
+		"Token": "TK42", "URL": link, "ExpireMinutes": 30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg.Text, link) || !strings.Contains(msg.HTML, `href="https://example.com/reset?token=TK42&amp;x=1"`) {
+		t.Errorf("link not rendered: text = %q, html = %q", msg.Text, msg.HTML)
+	}
+}
+
+func TestMessageBytes(t *testing.T) {
+	raw, err := Message{To: "user@example.com", Subject: "確認コード", Text: "コード: 1234", HTML: "<p>1234</p>"}.
+		Bytes("Travel AR <no-reply@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if subject != "確認コード" {
+		t.Errorf("subject = %q", subject)
+	}
+	mediaType, params, _ := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q", mediaType)
+	}
+	r := multipart.NewReader(m.Body, params["boundary"])
+	var bodies []string
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(quotedprintable.NewReader(p))
+		bodies = append(bodies, string(b))
+	}
+	if len(bodies) != 2 || bodies[0] != "コード: 1234" || bodies[1] != "<p>1234</p>" {
+		t.Errorf("bodies = %q", bodies)
+	}
+
+	if _, err := (Message{To: "not an address", Text: "x"}).Bytes("a@example.com"); err == nil {
+		t.Error("invalid recipient should fail")
+	}
+}
+
+func TestLocalMailers(t *testing.T) {
+	msg := Message{To: "user@example.com", Subject: "s", Text: "t"}
+
+	var mem Memory
+	if err := mem.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if got := mem.Messages(); len(got) != 1 || got[0] != msg {
+		t.Errorf("messages = %+v", got)
+	}
+
+	dir := t.TempDir()
+	if err := (&File{Dir: dir}).Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*user_at_example.com.eml"))
+	if len(files) != 1 {
+		t.Fatalf("files = %v", files)
+	}
+	b, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(b), "To: user@example.com") {
+		t.Errorf("eml = %s", b)
+	}
+}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+
+	"travel-ar-backend/pkg/i18n"
+)
+
+// 邮件模板：templates/<名称>.<语言>.tmpl，每个文件定义 subject、text、html 三个模板
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// 模板名称
+const (
+	TemplateVerifyCode    = "verify_code"    // 注册邮箱验证码，数据：Code、ExpireMinutes
+	TemplatePasswordReset = "password_reset" // 密码重置，数据：Token、URL（未配置时为空）、ExpireMinutes
+)
+
+// Render 按语言回退链选择模板并渲染，返回未填写收件人的邮件
+// 回退链中没有对应语言的模板时使用 i18n.DefaultLanguage
+func Render(name string, langs []string, data interface{}) (Message, error) {
+	file := ""
+	for _, lang := range append(append([]string(nil), langs...), i18n.DefaultLanguage) {
+		candidate := "templates/" + name + "." + lang + ".tmpl"
+		if _, err := fs.Stat(templateFS, candidate); err == nil {
+			file = candidate
+			break
+		}
+	}
+	if file == "" {
+		return Message{}, fmt.Errorf("mailer: template %q not found", name)
+	}
+
+	text, err := texttemplate.ParseFS(templateFS, file)
+	if err != nil {
+		return Message{}, err
+	}
+	html, err := htmltemplate.ParseFS(templateFS, file)
+	if err != nil {
+		return Message{}, err
+	}
+	var msg Message
+	var b bytes.Buffer
+	if err := text.ExecuteTemplate(&b, "subject", data); err != nil {
+		return Message{}, err
+	}
+	msg.Subject = strings.TrimSpace(b.String())
+	b.Reset()
+	if err := text.ExecuteTemplate(&b, "text", data); err != nil {
+		return Message{}, err
+	}
+	msg.Text = strings.TrimSpace(b.String()) + "\n"
+	b.Reset()
+	if err := html.ExecuteTemplate(&b, "html", data); err != nil {
+		return Message{}, err
+	}
+	msg.HTML = strings.TrimSpace(b.String()) + "\n"
+	return msg, nil
+}
@@ -2,40 +2,44 @@ package middleware
 
 import (
 	"net/http"
-	"strings"
 	"travel-ar-backend/internal/model"
+	"travel-ar-backend/internal/token"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 )
 
-const secretKey = "my_secret_key" // 保持和主项目一致
-
-type UserIDClaims struct {
-	UserID int `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
+// JWTAuth 校验 access token（Authorization: Bearer 请求头或 access_token Cookie）
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		tokenStr := token.FromRequest(c.Request)
+		if tokenStr == "" {
 			c.JSON(http.StatusUnauthorized, model.BaseResponse{Success: false, ErrMessage: "未登录，缺少token"})
 			c.Abort()
 			return
 		}
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims := &UserIDClaims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secretKey), nil
-		})
-		if err != nil || !token.Valid {
+		claims, err := token.Default().ParseAccess(tokenStr)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, model.BaseResponse{Success: false, ErrMessage: "token无效或已过期"})
 			c.Abort()
 			return
 		}
-		// 用户ID写入上下文
+		// 用户ID、角色与会话ID写入上下文
 		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Next()
+	}
+}
+
+// OptionalJWTAuth 可选登录：携带有效 token 时写入用户ID与角色，未携带或无效时按游客继续处理
+// 用于公开接口中需要区分当前用户的字段（如是否已收藏）
+func OptionalJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := token.Default().ParseAccess(token.FromRequest(c.Request)); err == nil {
+			c.Set("user_id", claims.UserID)
+			c.Set("role", claims.Role)
+			c.Set("session_id", claims.SessionID)
+		}
 		c.Next()
 	}
 }
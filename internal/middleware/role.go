@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 校验当前用户角色是否在允许范围内，需在 JWTAuth 之后使用
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, model.BaseResponse{Success: false, ErrMessage: "权限不足"})
+		c.Abort()
+	}
+}
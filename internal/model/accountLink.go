@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// 待绑定身份的用途
+const (
+	LinkPurposeConfirm = "confirm" // 邮箱与已有账号重复，需以该账号的密码或登录状态确认
+	LinkPurposeVerify  = "verify"  // 以未确认邮箱的第三方身份注册，账号通过邮箱验证后自动绑定
+)
+
+// AccountLink 表示 account_links 表：尚未写入账号的第三方身份，等待确认或邮箱验证后绑定
+// 只保存确认凭证的 SHA-256 哈希，一次性使用，过期失效
+type AccountLink struct {
+	LinkID        int        `gorm:"column:link_id;primaryKey" json:"link_id"`
+	UserID        int        `gorm:"column:user_id;not null;index" json:"user_id"`                            // 已有账号
+	Purpose       string     `gorm:"column:purpose;type:varchar(20);not null;default:confirm" json:"purpose"` // 用途：confirm / verify
+	Provider      string     `gorm:"column:provider;type:varchar(20);not null" json:"provider"`
+	Subject       string     `gorm:"column:subject;type:varchar(255);not null" json:"subject"` // 第三方用户ID
+	Email         string     `gorm:"column:email;type:varchar(255);not null" json:"email"`
+	EmailVerified bool       `gorm:"column:email_verified;not null;default:false" json:"email_verified"`
+	TokenHash     string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	Attempts      int        `gorm:"column:attempts;not null;default:0" json:"attempts"` // 密码错误次数
+	ExpiresAt     time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt        *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
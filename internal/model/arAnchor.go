@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// AR 标记类型
+const (
+	MarkerTypeQR    = "qr"    // 二维码标记
+	MarkerTypeImage = "image" // 图片识别标记
+)
+
+// ARAnchor 表示数据库中的 ar_anchors 表，描述设施上的 AR 锚点及其展示内容
+type ARAnchor struct {
+	AnchorID     int        `gorm:"column:anchor_id;primaryKey" json:"anchor_id"`
+	FacilityID   int        `gorm:"column:facility_id;not null;index" json:"facility_id"`
+	AnchorName   string     `gorm:"column:anchor_name;type:varchar(255);not null" json:"anchor_name"`
+	MarkerType   string     `gorm:"column:marker_type;type:varchar(20);not null" json:"marker_type"`              // 标记类型：qr / image
+	MarkerCode   string     `gorm:"column:marker_code;type:varchar(100);not null;uniqueIndex" json:"marker_code"` // 标记识别码（二维码内容或图片标记ID）
+	MarkerFileID *int       `gorm:"column:marker_file_id" json:"marker_file_id"`                                  // 图片标记的参考图（files 表ID）
+	AssetFileID  int        `gorm:"column:asset_file_id;not null" json:"asset_file_id"`                           // 3D 模型或叠加素材（files 表ID）
+	Altitude     float64    `gorm:"column:altitude;type:decimal(8,2);not null;default:0" json:"altitude"`         // 海拔/相对高度（米）
+	Heading      float64    `gorm:"column:heading;type:decimal(5,2);not null;default:0" json:"heading"`           // 朝向（正北顺时针，度）
+	Scale        float64    `gorm:"column:scale;type:decimal(8,3);not null;default:1" json:"scale"`               // 缩放比例
+	ActiveFrom   *time.Time `gorm:"column:active_from" json:"active_from"`                                        // 启用开始时间（为空表示不限）
+	ActiveUntil  *time.Time `gorm:"column:active_until" json:"active_until"`                                      // 启用结束时间（为空表示不限）
+	DailyStart   *string    `gorm:"column:daily_start;type:varchar(5)" json:"daily_start"`                        // 每日启用开始时刻 HH:MM（日本时间）
+	DailyEnd     *string    `gorm:"column:daily_end;type:varchar(5)" json:"daily_end"`                            // 每日启用结束时刻 HH:MM，早于开始时刻表示跨午夜
+	IsActive     bool       `gorm:"column:is_active;not null" json:"is_active"`
+	CreatedAt    time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// ARAnchorReqCreate 新建 AR 锚点请求
+type ARAnchorReqCreate struct {
+	FacilityID   int        `json:"facility_id" binding:"required"`
+	AnchorName   string     `json:"anchor_name" binding:"required"`
+	MarkerType   string     `json:"marker_type" binding:"required,oneof=qr image"`
+	MarkerCode   string     `json:"marker_code" binding:"required"`
+	MarkerFileID *int       `json:"marker_file_id"`
+	AssetFileID  int        `json:"asset_file_id" binding:"required"`
+	Altitude     float64    `json:"altitude"`
+	Heading      float64    `json:"heading" binding:"gte=0,lt=360"`
+	Scale        float64    `json:"scale" binding:"gte=0"` // 0 时按 1 处理
+	ActiveFrom   *time.Time `json:"active_from"`
+	ActiveUntil  *time.Time `json:"active_until"`
+	DailyStart   *string    `json:"daily_start"`
+	DailyEnd     *string    `json:"daily_end"`
+	IsActive     *bool      `json:"is_active"` // 默认启用
+}
+
+// ARAnchorReqEdit 更新 AR 锚点请求（整体替换可编辑字段）
+type ARAnchorReqEdit struct {
+	AnchorID int `json:"anchor_id" binding:"required"`
+	ARAnchorReqCreate
+}
+
+// ARAnchorReqList AR 锚点分页请求
+type ARAnchorReqList struct {
+	Page       int    `json:"page" binding:"required"`
+	PageSize   int    `json:"page_size" binding:"required"`
+	Keyword    string `json:"keyword"`
+	FacilityID int    `json:"facility_id"`
+}
+
+// ARAsset AR 素材信息（不含文件内容，内容通过 URL 获取）
+type ARAsset struct {
+	FileID   int    `json:"file_id"`
+	FileName string `json:"file_name"`
+	FileType string `json:"file_type"`
+	FileSize int    `json:"file_size"`
+	URL      string `json:"url"`
+}
+
+// ARResolveResult 扫描标记的解析结果
+type ARResolveResult struct {
+	Facility Facility `json:"facility"`
+	Anchor   ARAnchor `json:"anchor"`
+	Asset    ARAsset  `json:"asset"`
+}
@@ -13,6 +13,9 @@ type Article struct {
 	CommentCount int        `gorm:"column:comment_count;not null" json:"comment_count"`
 	CreatedAt    time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt    *time.Time `gorm:"column:updated_at" json:"updated_at"`
+
+	IsFavorited   bool  `gorm:"-" json:"is_favorited"`   // 当前用户是否已收藏（未登录时为 false）
+	FavoriteCount int64 `gorm:"-" json:"favorite_count"` // 收藏人数
 }
 
 // ArticleReqCreate 文章创建请求
@@ -41,6 +44,8 @@ type ArticleReqList struct {
 	Page     int    `json:"page" binding:"required"`
 	PageSize int    `json:"page_size" binding:"required"`
 	Keyword  string `json:"keyword"`
+	TagIDs   []int  `json:"tag_ids"`                                     // 按标签筛选
+	TagMatch string `json:"tag_match" binding:"omitempty,oneof=any all"` // any=含任一标签（默认），all=含全部标签
 }
 
 // ArticleDetailRequest 获取单个文章请求
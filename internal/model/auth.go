@@ -25,18 +25,48 @@ type AuthResponse struct {
 	User         User   `json:"user"`
 }
 
+// RefreshTokenRequest 刷新请求，Web 端可不传，使用 refresh_token Cookie
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token"`
 }
 type RefreshTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
 
+// RevokeTokenRequest 登出请求，Web 端可不传，使用 refresh_token Cookie
 type RevokeTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-type GoogleAuthRequest struct {
+// SocialLoginRequest 第三方 ID token 登录请求
+type SocialLoginRequest struct {
 	IdToken string `json:"id_token" binding:"required"`
+	Nonce   string `json:"nonce"` // 客户端发起登录时使用的 nonce，提供时要求与 ID token 一致
+	Name    string `json:"name"`  // Apple 仅在首次授权时把姓名交给客户端，由客户端转交
+}
+
+// AccountLinkRequired 第三方账号的邮箱已被其他账号使用，需确认绑定
+type AccountLinkRequired struct {
+	LinkToken string   `json:"link_token"` // 绑定确认凭证，10 分钟内有效
+	Email     string   `json:"email"`
+	Provider  string   `json:"provider"` // 待绑定的登录方式
+	Methods   []string `json:"methods"`  // 已有账号可用的登录方式：password、google、apple、line
+}
+
+// AccountLinkConfirm 确认绑定请求：提供已有账号的密码，或以已有账号登录后提交
+type AccountLinkConfirm struct {
+	LinkToken string `json:"link_token"` // Web 端第三方登录回调时凭证保存在 link_token Cookie 中，可省略
+	Password  string `json:"password"`
+}
+
+// VerifyRequest 邮箱验证请求
+type VerifyRequest struct {
+	Email string `json:"email" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// ResendVerifyRequest 重新发送验证码请求
+type ResendVerifyRequest struct {
+	Email string `json:"email" binding:"required"`
 }
@@ -0,0 +1,107 @@
+package model
+
+import "time"
+
+// Campaign 表示数据库中的 campaigns 表（集章活动）
+type Campaign struct {
+	CampaignID        int       `gorm:"column:campaign_id;primaryKey" json:"campaign_id"`
+	CampaignName      string    `gorm:"column:campaign_name;type:varchar(255);not null" json:"campaign_name"`
+	Description       string    `gorm:"column:description;type:text" json:"description"`
+	StartAt           time.Time `gorm:"column:start_at;not null" json:"start_at"`
+	EndAt             time.Time `gorm:"column:end_at;not null" json:"end_at"`
+	RuleType          string    `gorm:"column:rule_type;type:varchar(20);not null" json:"rule_type"`           // 完成规则：all / any / ordered
+	RequiredCount     int       `gorm:"column:required_count;not null;default:0" json:"required_count"`        // any 规则下需访问的设施数
+	RewardDescription string    `gorm:"column:reward_description;type:varchar(255)" json:"reward_description"` // 奖励说明
+	IsActive          bool      `gorm:"column:is_active;not null" json:"is_active"`
+	CreatedAt         time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	FacilityIDs []int `gorm:"-" json:"facility_ids"` // 活动设施，ordered 规则下按访问顺序排列
+	StoreIDs    []int `gorm:"-" json:"store_ids"`    // 可核销奖励的合作商铺
+}
+
+// CampaignFacility 表示数据库中的 campaign_facilities 表
+type CampaignFacility struct {
+	CampaignFacilityID int `gorm:"column:campaign_facility_id;primaryKey" json:"campaign_facility_id"`
+	CampaignID         int `gorm:"column:campaign_id;not null;uniqueIndex:uq_campaign_facilities" json:"campaign_id"`
+	FacilityID         int `gorm:"column:facility_id;not null;uniqueIndex:uq_campaign_facilities" json:"facility_id"`
+	StepOrder          int `gorm:"column:step_order;not null" json:"step_order"`
+}
+
+// CampaignStore 表示数据库中的 campaign_stores 表（可核销奖励的合作商铺）
+type CampaignStore struct {
+	CampaignStoreID int `gorm:"column:campaign_store_id;primaryKey" json:"campaign_store_id"`
+	CampaignID      int `gorm:"column:campaign_id;not null;uniqueIndex:uq_campaign_stores" json:"campaign_id"`
+	StoreID         int `gorm:"column:store_id;not null;uniqueIndex:uq_campaign_stores;index" json:"store_id"`
+}
+
+// CampaignReward 表示数据库中的 campaign_rewards 表，每个用户每个活动最多一条
+type CampaignReward struct {
+	RewardID        int        `gorm:"column:reward_id;primaryKey" json:"reward_id"`
+	CampaignID      int        `gorm:"column:campaign_id;not null;uniqueIndex:uq_campaign_rewards_user" json:"campaign_id"`
+	UserID          int        `gorm:"column:user_id;not null;uniqueIndex:uq_campaign_rewards_user" json:"user_id"`
+	CouponCode      string     `gorm:"column:coupon_code;type:varchar(20);not null;uniqueIndex" json:"coupon_code"` // 兑换码
+	IssuedAt        time.Time  `gorm:"column:issued_at;not null" json:"issued_at"`
+	RedeemedAt      *time.Time `gorm:"column:redeemed_at" json:"redeemed_at"`
+	RedeemedBy      *int       `gorm:"column:redeemed_by" json:"redeemed_by"`             // 核销操作人
+	RedeemedStoreID *int       `gorm:"column:redeemed_store_id" json:"redeemed_store_id"` // 核销商铺
+}
+
+// CampaignReqCreate 新建活动请求
+type CampaignReqCreate struct {
+	CampaignName      string    `json:"campaign_name" binding:"required"`
+	Description       string    `json:"description"`
+	StartAt           time.Time `json:"start_at" binding:"required"`
+	EndAt             time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+	RuleType          string    `json:"rule_type" binding:"required,oneof=all any ordered"`
+	RequiredCount     int       `json:"required_count" binding:"gte=0"`
+	RewardDescription string    `json:"reward_description"`
+	FacilityIDs       []int     `json:"facility_ids" binding:"required,min=1,unique"`
+	StoreIDs          []int     `json:"store_ids" binding:"unique"` // 可核销奖励的合作商铺
+	IsActive          *bool     `json:"is_active"`                  // 默认启用
+}
+
+// CampaignReqEdit 更新活动请求（整体替换，含设施列表）
+type CampaignReqEdit struct {
+	CampaignID int `json:"campaign_id" binding:"required"`
+	CampaignReqCreate
+}
+
+// CampaignReqList 活动分页请求
+type CampaignReqList struct {
+	Page       int    `json:"page" binding:"required"`
+	PageSize   int    `json:"page_size" binding:"required"`
+	Keyword    string `json:"keyword"`
+	OngoingNow bool   `json:"ongoing_now"` // 仅返回进行中的活动
+}
+
+// CampaignReqRedeem 核销奖励请求
+type CampaignReqRedeem struct {
+	CouponCode string `json:"coupon_code" binding:"required"`
+}
+
+// CampaignProgress 用户的活动进度
+type CampaignProgress struct {
+	CampaignID     int             `json:"campaign_id"`
+	RuleType       string          `json:"rule_type"`
+	Required       int             `json:"required"`         // 完成所需设施数
+	VisitedIDs     []int           `json:"visited_ids"`      // 已计入进度的设施
+	NextFacilityID *int            `json:"next_facility_id"` // ordered 规则下应访问的下一个设施
+	Completed      bool            `json:"completed"`
+	Reward         *CampaignReward `json:"reward"`
+}
+
+// CampaignFacilityStat 活动设施访问统计
+type CampaignFacilityStat struct {
+	FacilityID int   `gorm:"column:facility_id" json:"facility_id"`
+	Visitors   int64 `gorm:"column:visitors" json:"visitors"` // 活动期间访问的用户数
+}
+
+// CampaignStats 活动参与统计
+type CampaignStats struct {
+	CampaignID   int                    `json:"campaign_id"`
+	Participants int64                  `json:"participants"` // 活动期间访问过任一活动设施的用户数
+	Completed    int64                  `json:"completed"`    // 已完成（已发放奖励）人数
+	Redeemed     int64                  `json:"redeemed"`     // 已核销奖励数
+	Facilities   []CampaignFacilityStat `json:"facilities"`
+}
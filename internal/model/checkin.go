@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// CheckinReqScan 扫码签到请求
+type CheckinReqScan struct {
+	Payload   string   `json:"payload" binding:"required"` // 二维码内容
+	Latitude  *float64 `json:"latitude"`                   // 设备纬度（可选，提供时校验与设施的距离）
+	Longitude *float64 `json:"longitude"`                  // 设备经度
+}
+
+// CheckinResult 签到结果
+type CheckinResult struct {
+	History   VisitHistory     `json:"history"`
+	Duplicate bool             `json:"duplicate"`         // 冷却时间内重复扫码时为 true，返回已有记录
+	Rewards   []CampaignReward `json:"rewards,omitempty"` // 本次签到新完成的集章活动奖励
+}
+
+// CheckinQR 设施当前的签到二维码
+type CheckinQR struct {
+	FacilityID int       `json:"facility_id"`
+	Payload    string    `json:"payload"`
+	ExpiresAt  time.Time `json:"expires_at"` // 到期后需重新获取
+}
@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// Coupon 表示数据库中的 coupons 表（商铺优惠券）
+type Coupon struct {
+	CouponID            int       `gorm:"column:coupon_id;primaryKey" json:"coupon_id"`
+	StoreID             int       `gorm:"column:store_id;not null;index" json:"store_id"`
+	Title               string    `gorm:"column:title;type:varchar(255);not null" json:"title"`
+	DiscountDescription string    `gorm:"column:discount_description;type:varchar(255);not null" json:"discount_description"` // 优惠内容，如「饮品九折」
+	Terms               string    `gorm:"column:terms;type:text" json:"terms"`                                                // 使用条件
+	ValidFrom           time.Time `gorm:"column:valid_from;not null" json:"valid_from"`
+	ValidUntil          time.Time `gorm:"column:valid_until;not null" json:"valid_until"`
+	TotalLimit          int       `gorm:"column:total_limit;not null;default:0" json:"total_limit"` // 发放与核销总数上限，0 表示不限
+	PerUserLimit        int       `gorm:"column:per_user_limit;not null" json:"per_user_limit"`     // 每位用户可领取与核销的次数，0 表示不限
+	ClaimedCount        int       `gorm:"column:claimed_count;not null;default:0" json:"claimed_count"`
+	RedeemedCount       int       `gorm:"column:redeemed_count;not null;default:0" json:"redeemed_count"`
+	TargetLanguage      *string   `gorm:"column:target_language;type:varchar(10)" json:"target_language"` // 仅面向该语言的用户，空表示不限
+	CampaignID          *int      `gorm:"column:campaign_id" json:"campaign_id"`                          // 仅限完成该集章活动的用户领取
+	IsActive            bool      `gorm:"column:is_active;not null" json:"is_active"`
+	CreatedAt           time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// UserCoupon 表示数据库中的 user_coupons 表（用户券包）
+type UserCoupon struct {
+	UserCouponID int        `gorm:"column:user_coupon_id;primaryKey" json:"user_coupon_id"`
+	CouponID     int        `gorm:"column:coupon_id;not null;index:idx_user_coupons_coupon_user" json:"coupon_id"`
+	UserID       int        `gorm:"column:user_id;not null;index:idx_user_coupons_coupon_user" json:"user_id"`
+	RedeemCode   string     `gorm:"column:redeem_code;type:varchar(20);not null;uniqueIndex" json:"redeem_code"` // 一次性核销码，核销后失效
+	ClaimedAt    time.Time  `gorm:"column:claimed_at;not null" json:"claimed_at"`
+	RedeemedAt   *time.Time `gorm:"column:redeemed_at" json:"redeemed_at"`
+	RedeemedBy   *int       `gorm:"column:redeemed_by" json:"redeemed_by"` // 核销操作人
+
+	Coupon *Coupon `gorm:"-" json:"coupon,omitempty"`
+}
+
+// CouponReqCreate 新建优惠券请求
+type CouponReqCreate struct {
+	StoreID             int       `json:"store_id"` // 店主接口以路径中的商铺ID为准
+	Title               string    `json:"title" binding:"required"`
+	DiscountDescription string    `json:"discount_description" binding:"required"`
+	Terms               string    `json:"terms"`
+	ValidFrom           time.Time `json:"valid_from" binding:"required"`
+	ValidUntil          time.Time `json:"valid_until" binding:"required,gtfield=ValidFrom"`
+	TotalLimit          int       `json:"total_limit" binding:"gte=0"`
+	PerUserLimit        *int      `json:"per_user_limit" binding:"omitempty,gte=0"` // 默认 1
+	TargetLanguage      *string   `json:"target_language"`
+	CampaignID          *int      `json:"campaign_id"`
+	IsActive            *bool     `json:"is_active"` // 默认启用
+}
+
+// CouponReqEdit 更新优惠券请求（整体替换，商铺不可变更）
+type CouponReqEdit struct {
+	CouponID int `json:"coupon_id"` // 店主接口以路径中的优惠券ID为准
+	CouponReqCreate
+}
+
+// CouponReqList 优惠券分页请求（仅返回当前可领取的优惠券）
+type CouponReqList struct {
+	Page     int    `json:"page" binding:"required"`
+	PageSize int    `json:"page_size" binding:"required"`
+	Keyword  string `json:"keyword"`
+	StoreID  int    `json:"store_id"`
+}
+
+// CouponReqRedeem 核销请求
+type CouponReqRedeem struct {
+	RedeemCode string `json:"redeem_code" binding:"required"` // 用户出示的一次性核销码
+}
+
+// CouponRedemptionStat 单张优惠券的领取与核销统计
+type CouponRedemptionStat struct {
+	CouponID      int    `json:"coupon_id"`
+	Title         string `json:"title"`
+	ClaimedCount  int64  `json:"claimed_count"`
+	RedeemedCount int64  `json:"redeemed_count"`
+}
+
+// CouponRedemptionReport 商铺优惠券核销报表
+type CouponRedemptionReport struct {
+	StoreID       int                    `json:"store_id"`
+	From          *time.Time             `json:"from"`
+	To            *time.Time             `json:"to"`
+	ClaimedCount  int64                  `json:"claimed_count"`
+	RedeemedCount int64                  `json:"redeemed_count"`
+	Coupons       []CouponRedemptionStat `json:"coupons"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// 实体类型，用于 taggings.taggable_type、files.related_type 等多态关联字段
+const (
+	EntityStore    = "Store"
+	EntityFacility = "Facility"
+	EntityReview   = "Review"
+	EntityArticle  = "Article"
+	EntityNotice   = "Notice"
+	EntityMenuItem = "StoreMenuItem"
+	EntityTag      = "Tag"
+	EntityHistory  = "History"
+	EntityComment  = "Comment"
+)
+
+// EntityTables 实体类型对应的表名与主键，用于校验多态关联的目标是否存在
+var EntityTables = map[string]struct{ Table, PrimaryKey string }{
+	EntityStore:    {"stores", "store_id"},
+	EntityFacility: {"facilities", "facility_id"},
+	EntityArticle:  {"articles", "article_id"},
+	EntityHistory:  {"visit_history", "history_id"},
+	EntityComment:  {"comments", "comment_id"},
+}
@@ -5,43 +5,54 @@ import (
 )
 
 type Facility struct {
-	FacilityID      int       `gorm:"column:facility_id;primaryKey" json:"facility_id"`                     // 设施ID
-	FacilityName    string    `gorm:"column:facility_name;type:varchar(255);not null" json:"facility_name"` // 设施名
-	Location        string    `gorm:"column:location;type:varchar(255);not null" json:"location"`           // 所在地
-	DescriptionText string    `gorm:"column:description_text;type:text" json:"description"`                 // 设施描述
-	Latitude        float64   `gorm:"column:latitude;type:decimal(10,6);not null" json:"latitude"`          // 纬度
-	Longitude       float64   `gorm:"column:longitude;type:decimal(10,6);not null" json:"longitude"`        // 经度
-	PersonID        *int      `gorm:"column:person_id" json:"person_id"`                                    // 相关人物ID（可选）
-	CreatedAt       time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt       time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	FacilityID       int       `gorm:"column:facility_id;primaryKey" json:"facility_id"`                      // 设施ID
+	FacilityName     string    `gorm:"column:facility_name;type:varchar(255);not null" json:"facility_name"`  // 设施名
+	FacilityNameKana string    `gorm:"column:facility_name_kana;type:varchar(255)" json:"facility_name_kana"` // 设施名读音（假名），用于输入联想
+	FacilityCategory string    `gorm:"column:facility_category;type:varchar(100)" json:"facility_category"`   // 设施分类（例：神社, 博物馆）
+	Location         string    `gorm:"column:location;type:varchar(255);not null" json:"location"`            // 所在地
+	DescriptionText  string    `gorm:"column:description_text;type:text" json:"description"`                  // 设施描述
+	Latitude         float64   `gorm:"column:latitude;type:decimal(10,6);not null" json:"latitude"`           // 纬度
+	Longitude        float64   `gorm:"column:longitude;type:decimal(10,6);not null" json:"longitude"`         // 经度
+	PersonID         *int      `gorm:"column:person_id" json:"person_id"`                                     // 相关人物ID（可选）
+	CreatedAt        time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	IsFavorited   bool  `gorm:"-" json:"is_favorited"`   // 当前用户是否已收藏（未登录时为 false）
+	FavoriteCount int64 `gorm:"-" json:"favorite_count"` // 收藏人数
 }
 
 // FacilityReqCreate 用于创建设施时的请求参数
 type FacilityReqCreate struct {
-	FacilityName    string  `json:"facility_name" binding:"required"` // 设施名
-	Location        string  `json:"location" binding:"required"`      // 所在地
-	DescriptionText string  `json:"description"`                      // 描述
-	Latitude        float64 `json:"latitude" binding:"required"`      // 纬度
-	Longitude       float64 `json:"longitude" binding:"required"`     // 经度
-	PersonID        *int    `json:"person_id"`                        // 相关人物ID（可选）
+	FacilityName     string  `json:"facility_name" binding:"required"` // 设施名
+	FacilityNameKana string  `json:"facility_name_kana"`               // 设施名读音（假名）
+	FacilityCategory string  `json:"facility_category"`                // 设施分类
+	Location         string  `json:"location" binding:"required"`      // 所在地
+	DescriptionText  string  `json:"description"`                      // 描述
+	Latitude         float64 `json:"latitude" binding:"required"`      // 纬度
+	Longitude        float64 `json:"longitude" binding:"required"`     // 经度
+	PersonID         *int    `json:"person_id"`                        // 相关人物ID（可选）
 }
 
 // FacilityReqEdit 用于编辑设施时的请求参数
 type FacilityReqEdit struct {
-	FacilityID      int     `json:"facility_id" binding:"required"`   // 设施ID
-	FacilityName    string  `json:"facility_name" binding:"required"` // 设施名
-	Location        string  `json:"location" binding:"required"`      // 所在地
-	DescriptionText string  `json:"description"`                      // 描述
-	Latitude        float64 `json:"latitude" binding:"required"`      // 纬度
-	Longitude       float64 `json:"longitude" binding:"required"`     // 经度
-	PersonID        *int    `json:"person_id"`                        // 相关人物ID（可选）
+	FacilityID       int     `json:"facility_id" binding:"required"`   // 设施ID
+	FacilityName     string  `json:"facility_name" binding:"required"` // 设施名
+	FacilityNameKana string  `json:"facility_name_kana"`               // 设施名读音（假名）
+	FacilityCategory string  `json:"facility_category"`                // 设施分类
+	Location         string  `json:"location" binding:"required"`      // 所在地
+	DescriptionText  string  `json:"description"`                      // 描述
+	Latitude         float64 `json:"latitude" binding:"required"`      // 纬度
+	Longitude        float64 `json:"longitude" binding:"required"`     // 经度
+	PersonID         *int    `json:"person_id"`                        // 相关人物ID（可选）
 }
 
 // FacilityReqList 用于分页与查询设施时的请求参数
 type FacilityReqList struct {
-	Page     int    `json:"page" binding:"required"`      // 页码
-	PageSize int    `json:"page_size" binding:"required"` // 每页数量
-	Keyword  string `json:"keyword"`                      // 关键字（设施名模糊搜索）
+	Page     int    `json:"page" binding:"required"`                     // 页码
+	PageSize int    `json:"page_size" binding:"required"`                // 每页数量
+	Keyword  string `json:"keyword"`                                     // 关键字（设施名模糊搜索）
+	TagIDs   []int  `json:"tag_ids"`                                     // 按标签筛选
+	TagMatch string `json:"tag_match" binding:"omitempty,oneof=any all"` // any=含任一标签（默认），all=含全部标签
 }
 
 // FacilityCreateRequest 兼容风格，新建请求（备用，与 ReqCreate 作用相同）
@@ -57,3 +68,18 @@ type FacilityQueryRequest = FacilityReqList
 type FacilityDetailRequest struct {
 	FacilityID int `json:"facility_id" binding:"required"` // 设施ID
 }
+
+// FacilityReqNearby 附近设施查询请求
+type FacilityReqNearby struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`    // 中心点纬度（0 为有效值，故用指针区分未提供）
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"` // 中心点经度
+	RadiusM   float64  `json:"radius_m" binding:"required,gt=0"`              // 半径（米）
+	Category  string   `json:"category"`                                      // 设施分类（可选）
+	Limit     int      `json:"limit"`                                         // 最大返回条数（默认50）
+}
+
+// FacilityNearby 附近设施查询结果，附带与中心点的距离
+type FacilityNearby struct {
+	Facility
+	DistanceM float64 `gorm:"column:distance_m" json:"distance_m"` // 距离（米）
+}
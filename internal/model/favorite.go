@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Favorite 表示数据库中的 favorites 表（用户收藏，多态关联）
+type Favorite struct {
+	FavoriteID      int       `gorm:"column:favorite_id;primaryKey" json:"favorite_id"`
+	UserID          int       `gorm:"column:user_id;not null;uniqueIndex:uq_favorites_user_target" json:"user_id"`
+	FavoritableType string    `gorm:"column:favoritable_type;type:varchar(50);not null;uniqueIndex:uq_favorites_user_target;index:idx_favorites_target" json:"favoritable_type"` // 实体类型：Store / Facility / Article
+	FavoritableID   int       `gorm:"column:favoritable_id;not null;uniqueIndex:uq_favorites_user_target;index:idx_favorites_target" json:"favoritable_id"`
+	CreatedAt       time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	Target interface{} `gorm:"-" json:"target,omitempty"` // 收藏对象详情（Store / Facility / Article）
+}
+
+// FavoriteReqCreate 添加收藏请求
+type FavoriteReqCreate struct {
+	FavoritableType string `json:"favoritable_type" binding:"required,oneof=Store Facility Article"`
+	FavoritableID   int    `json:"favoritable_id" binding:"required"`
+}
+
+// FavoriteReqList 我的收藏分页请求
+type FavoriteReqList struct {
+	Page            int    `json:"page" binding:"required"`
+	PageSize        int    `json:"page_size" binding:"required"`
+	FavoritableType string `json:"favoritable_type"` // 为空时返回全部类型
+}
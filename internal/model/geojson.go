@@ -0,0 +1,39 @@
+package model
+
+// MapReqViewport 地图视口查询请求
+type MapReqViewport struct {
+	MinLat float64  `json:"min_lat"` // 视口最小纬度
+	MinLng float64  `json:"min_lng"` // 视口最小经度
+	MaxLat float64  `json:"max_lat"` // 视口最大纬度
+	MaxLng float64  `json:"max_lng"` // 视口最大经度
+	Types  []string `json:"types"`   // 实体类型：Store, Facility（为空时返回全部）
+	Zoom   int      `json:"zoom"`    // 地图缩放级别（0-22），低缩放级别下点过多时在服务端聚合
+}
+
+// FeatureCollection GeoJSON 要素集合
+type FeatureCollection struct {
+	Type     string    `json:"type" example:"FeatureCollection"`
+	Features []Feature `json:"features"`
+}
+
+// Feature GeoJSON 要素
+type Feature struct {
+	Type       string                 `json:"type" example:"Feature"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry GeoJSON 几何对象（仅使用 Point，坐标顺序为 [经度, 纬度]）
+type Geometry struct {
+	Type        string    `json:"type" example:"Point"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// NewPointFeature 创建点要素
+func NewPointFeature(lat, lng float64, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "Point", Coordinates: []float64{lng, lat}},
+		Properties: properties,
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+// Itinerary 表示数据库中的 itineraries 表（用户行程）
+type Itinerary struct {
+	ItineraryID int        `gorm:"column:itinerary_id;primaryKey" json:"itinerary_id"`
+	UserID      int        `gorm:"column:user_id;not null;index" json:"user_id"`
+	Title       string     `gorm:"column:title;type:varchar(255);not null" json:"title"`
+	Description string     `gorm:"column:description;type:text" json:"description"`
+	StartDate   *time.Time `gorm:"column:start_date;type:date" json:"start_date"`                      // 第一天的日期（可选）
+	ShareToken  *string    `gorm:"column:share_token;type:varchar(32);uniqueIndex" json:"share_token"` // 只读分享链接令牌，为空表示未分享
+	ClonedFrom  *int       `gorm:"column:cloned_from" json:"cloned_from"`                              // 复制来源行程ID
+	CreatedAt   time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	Days           []ItineraryDay `gorm:"-" json:"days,omitempty"`
+	TotalDistanceM float64        `gorm:"-" json:"total_distance_m"` // 各天停留点之间直线距离之和（米）
+}
+
+// ItineraryDay 表示数据库中的 itinerary_days 表
+type ItineraryDay struct {
+	DayID       int    `gorm:"column:day_id;primaryKey" json:"day_id"`
+	ItineraryID int    `gorm:"column:itinerary_id;not null;index" json:"itinerary_id"`
+	DayIndex    int    `gorm:"column:day_index;not null" json:"day_index"` // 第几天，从 1 开始
+	Title       string `gorm:"column:title;type:varchar(255)" json:"title"`
+
+	Date      *string         `gorm:"-" json:"date"` // 由行程开始日期推算，YYYY-MM-DD
+	Stops     []ItineraryStop `gorm:"-" json:"stops"`
+	DistanceM float64         `gorm:"-" json:"distance_m"` // 当天停留点之间直线距离之和（米）
+}
+
+// ItineraryStop 表示数据库中的 itinerary_stops 表（按顺序排列的停留点）
+type ItineraryStop struct {
+	StopID       int     `gorm:"column:stop_id;primaryKey" json:"stop_id"`
+	DayID        int     `gorm:"column:day_id;not null;index" json:"day_id"`
+	StopOrder    int     `gorm:"column:stop_order;not null" json:"stop_order"`
+	StopType     string  `gorm:"column:stop_type;type:varchar(50);not null" json:"stop_type"` // 实体类型：Store / Facility
+	TargetID     int     `gorm:"column:target_id;not null" json:"target_id"`
+	PlannedStart *string `gorm:"column:planned_start;type:varchar(5)" json:"planned_start"` // 计划到达时刻 HH:MM
+	PlannedEnd   *string `gorm:"column:planned_end;type:varchar(5)" json:"planned_end"`     // 计划离开时刻 HH:MM
+	Note         string  `gorm:"column:note;type:text" json:"note"`
+
+	Name              string  `gorm:"-" json:"name"`
+	Latitude          float64 `gorm:"-" json:"latitude"`
+	Longitude         float64 `gorm:"-" json:"longitude"`
+	DistanceFromPrevM float64 `gorm:"-" json:"distance_from_prev_m"` // 与上一停留点的直线距离（米），第一站为 0
+}
+
+// ItineraryReqCreate 新建行程请求
+type ItineraryReqCreate struct {
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description"`
+	StartDate   *string `json:"start_date"`                                 // YYYY-MM-DD
+	DayCount    int     `json:"day_count" binding:"omitempty,min=1,max=30"` // 初始天数，默认 1
+}
+
+// ItineraryReqEdit 更新行程请求
+type ItineraryReqEdit struct {
+	ItineraryID int     `json:"itinerary_id" binding:"required"`
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description"`
+	StartDate   *string `json:"start_date"` // YYYY-MM-DD，传空字符串清除
+}
+
+// ItineraryReqList 我的行程分页请求
+type ItineraryReqList struct {
+	Page     int    `json:"page" binding:"required"`
+	PageSize int    `json:"page_size" binding:"required"`
+	Keyword  string `json:"keyword"`
+}
+
+// ItineraryDayReqEdit 新建或更新行程日请求
+type ItineraryDayReqEdit struct {
+	Title string `json:"title"`
+}
+
+// ItineraryStopReqCreate 新增停留点请求
+type ItineraryStopReqCreate struct {
+	StopType     string  `json:"stop_type" binding:"required,oneof=Store Facility"`
+	TargetID     int     `json:"target_id" binding:"required"`
+	PlannedStart *string `json:"planned_start"`
+	PlannedEnd   *string `json:"planned_end"`
+	Note         string  `json:"note"`
+	Position     int     `json:"position" binding:"gte=0"` // 插入位置（从 1 开始），0 表示追加到末尾
+}
+
+// ItineraryStopReqEdit 更新停留点请求，未传的字段保持不变
+type ItineraryStopReqEdit struct {
+	PlannedStart *string `json:"planned_start"` // 传空字符串清除
+	PlannedEnd   *string `json:"planned_end"`   // 传空字符串清除
+	Note         *string `json:"note"`
+}
+
+// ItineraryStopReqReorder 停留点排序请求
+type ItineraryStopReqReorder struct {
+	StopIDs []int `json:"stop_ids" binding:"required"` // 当天全部停留点ID的新顺序
+}
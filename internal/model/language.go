@@ -6,6 +6,7 @@ import "time"
 type Language struct {
 	LanguageID   int        `gorm:"column:language_id;primaryKey" json:"language_id"`
 	LanguageName string     `gorm:"column:language_name;type:varchar(50);not null" json:"language_name"`
+	LanguageCode string     `gorm:"column:language_code;type:varchar(20);not null;uniqueIndex" json:"language_code"` // BCP 47 语言标签（如 ja, en, zh-TW）
 	DisplayOrder *int       `gorm:"column:display_order" json:"display_order"`
 	IsActive     bool       `gorm:"column:is_active;not null;default:true" json:"is_active"`
 	CreatedAt    time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
@@ -15,6 +16,7 @@ type Language struct {
 // LanguageReqCreate 创建语言请求
 type LanguageReqCreate struct {
 	LanguageName string `json:"language_name" binding:"required"`
+	LanguageCode string `json:"language_code" binding:"required"`
 	DisplayOrder *int   `json:"display_order"`
 	IsActive     bool   `json:"is_active"`
 }
@@ -23,6 +25,7 @@ type LanguageReqCreate struct {
 type LanguageReqEdit struct {
 	LanguageID   int    `json:"language_id" binding:"required"`
 	LanguageName string `json:"language_name"`
+	LanguageCode string `json:"language_code"`
 	DisplayOrder *int   `json:"display_order"`
 	IsActive     bool   `json:"is_active"`
 }
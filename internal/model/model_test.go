@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestInsertKeepsZeroValues 建表脚本中带默认值的列，false / 0 也要显式写入，不能被列默认值替换
+func TestInsertKeepsZeroValues(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  interface{}
+		column string
+		want   interface{}
+	}{
+		{"open holiday", &StoreHoliday{StoreID: 1, IsClosed: false}, "is_closed", false},
+		{"unavailable menu item", &StoreMenuItem{StoreID: 1, ItemName: "抹茶パフェ", Currency: "JPY"}, "is_available", false},
+		{"inactive AR anchor", &ARAnchor{FacilityID: 1, AnchorName: "雷门", MarkerType: "qr", MarkerCode: "kaminarimon", Scale: 1}, "is_active", false},
+		{"inactive campaign", &Campaign{CampaignName: "浅草集章", RuleType: "all"}, "is_active", false},
+		{"unlimited coupon", &Coupon{StoreID: 1, Title: "饮品九折", PerUserLimit: 0}, "per_user_limit", 0},
+		{"inactive coupon", &Coupon{StoreID: 1, Title: "饮品九折", IsActive: false}, "is_active", false},
+		{"machine translation", &Translation{Content: "Senso-ji Temple", Source: TranslationSourceMachine}, "is_approved", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := insertValues(t, tc.value)
+			if v, ok := values[tc.column]; !ok || v != tc.want {
+				t.Fatalf("%s = %v (present %v), want explicit %v", tc.column, v, ok, tc.want)
+			}
+		})
+	}
+}
+
+// insertValues 以 DryRun 方式生成 INSERT 语句，返回列名到参数值的映射
+// gorm 会省略带 default 标签的零值字段，可借此确认零值被显式写入
+func insertValues(t *testing.T, value interface{}) map[string]interface{} {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := db.Create(value).Statement
+	if stmt.Error != nil {
+		t.Fatal(stmt.Error)
+	}
+	// INSERT INTO "t" ("a","b") VALUES ($1,$2) ...
+	sql := stmt.SQL.String()
+	start := strings.Index(sql, "(")
+	end := strings.Index(sql, ")")
+	if start < 0 || end < start {
+		t.Fatalf("unexpected insert: %s", sql)
+	}
+	columns := strings.Split(sql[start+1:end], ",")
+	if len(columns) > len(stmt.Vars) {
+		t.Fatalf("columns %v do not match vars %v", columns, stmt.Vars)
+	}
+	values := map[string]interface{}{}
+	for i, col := range columns {
+		values[strings.Trim(col, `"`)] = stmt.Vars[i]
+	}
+	return values
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// PasswordResetToken 表示 password_reset_tokens 表：密码重置令牌
+// 只保存令牌的 SHA-256 哈希，一次性使用，过期失效
+type PasswordResetToken struct {
+	ResetID   int        `gorm:"column:reset_id;primaryKey" json:"reset_id"`
+	UserID    int        `gorm:"column:user_id;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"` // 使用或作废时间，NULL 表示仍有效
+	RequestIP string     `gorm:"column:request_ip;type:varchar(45)" json:"request_ip"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// PasswordResetRequest 申请重置密码请求
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// PasswordResetConfirm 重置密码请求
+type PasswordResetConfirm struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
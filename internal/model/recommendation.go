@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ItemCoVisitation 表示 item_co_visitations 表：共同访问矩阵
+// 同一用户访问或收藏过的两个地点构成一次共同访问，由后台任务定期重建
+type ItemCoVisitation struct {
+	ItemType  string    `gorm:"column:item_type;type:varchar(20);primaryKey" json:"item_type"`   // Store / Facility
+	ItemID    int       `gorm:"column:item_id;primaryKey" json:"item_id"`                        // 已访问、收藏的地点
+	OtherType string    `gorm:"column:other_type;type:varchar(20);primaryKey" json:"other_type"` // Store / Facility
+	OtherID   int       `gorm:"column:other_id;primaryKey" json:"other_id"`                      // 同一批用户也访问、收藏过的地点
+	Users     int       `gorm:"column:users;not null" json:"users"`                              // 共同用户数
+	Score     float64   `gorm:"column:score;not null" json:"score"`                              // 余弦相似度：共同用户数 / sqrt(两地点用户数之积)
+	UpdatedAt time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ItemCoVisitation) TableName() string {
+	return "item_co_visitations"
+}
+
+// RecommendationReq 个性化推荐请求
+type RecommendationReq struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`    // 当前位置纬度（0 为有效值，故用指针区分未提供）
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"` // 当前位置经度
+	RadiusM   float64  `json:"radius_m" binding:"gte=0"`                      // 搜索半径（米），默认 3000
+	Types     []string `json:"types" binding:"dive,oneof=Store Facility"`
+	Limit     int      `json:"limit" binding:"gte=0,lte=50"` // 最大返回条数，默认 20
+}
+
+// Recommendation 推荐结果
+type Recommendation struct {
+	EntityType string      `json:"entity_type"` // Store / Facility
+	EntityID   int         `json:"entity_id"`
+	DistanceM  float64     `json:"distance_m"`
+	Score      float64     `json:"score"`
+	Reasons    []string    `json:"reasons"` // similar_tags / co_visited / popular / nearby
+	Target     interface{} `json:"target"`  // 地点详情（Store / Facility）
+}
@@ -2,30 +2,40 @@ package model
 
 import "time"
 
+// RefreshToken 表示 refresh_tokens 表：每次刷新都会签发新令牌并作废旧令牌（轮换）
+// 同一次登录产生的令牌属于同一 family，即一个登录会话；只保存令牌的 SHA-256 哈希
 type RefreshToken struct {
-	TokenID      int       `gorm:"column:token_id;primaryKey" json:"token_id"`
-	UserID       int       `gorm:"column:user_id;not null" json:"user_id"`
-	RefreshToken string    `gorm:"column:refresh_token;type:varchar(255);not null" json:"refresh_token"`
-	ExpiresAt    time.Time `gorm:"column:expires_at;not null" json:"expires_at"`
-	CreatedAt    time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	Revoked      bool      `gorm:"column:revoked;not null;default:false" json:"revoked"`
+	TokenID    int        `gorm:"column:token_id;primaryKey" json:"token_id"`
+	UserID     int        `gorm:"column:user_id;not null;index" json:"user_id"`
+	FamilyID   string     `gorm:"column:family_id;type:varchar(32);not null;index" json:"family_id"`
+	TokenHash  string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	DeviceName string     `gorm:"column:device_name;type:varchar(100)" json:"device_name"`
+	IPAddress  string     `gorm:"column:ip_address;type:varchar(45)" json:"ip_address"`
+	UserAgent  string     `gorm:"column:user_agent;type:varchar(255)" json:"user_agent"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Revoked    bool       `gorm:"column:revoked;not null;default:false" json:"revoked"`
+	RotatedAt  *time.Time `gorm:"column:rotated_at" json:"rotated_at"` // 被轮换的时间，非空时再次使用视为令牌被盗用
+	ReplacedBy *int       `gorm:"column:replaced_by" json:"replaced_by"`
 }
 
 // RefreshTokenReqCreate 创建 Refresh Token 请求
 type RefreshTokenReqCreate struct {
 	UserID       int       `json:"user_id" binding:"required"`
 	RefreshToken string    `json:"refresh_token" binding:"required"`
+	FamilyID     string    `json:"family_id"`
+	DeviceName   string    `json:"device_name"`
 	ExpiresAt    time.Time `json:"expires_at" binding:"required"`
 	Revoked      bool      `json:"revoked"`
 }
 
 // RefreshTokenReqEdit 更新 Refresh Token 请求
 type RefreshTokenReqEdit struct {
-	TokenID      int       `json:"token_id" binding:"required"`
-	UserID       int       `json:"user_id"`
-	RefreshToken string    `json:"refresh_token"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	Revoked      bool      `json:"revoked"`
+	TokenID    int       `json:"token_id" binding:"required"`
+	UserID     int       `json:"user_id"`
+	DeviceName string    `json:"device_name"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Revoked    bool      `json:"revoked"`
 }
 
 // RefreshTokenReqList 分页与搜索请求
@@ -33,3 +43,15 @@ type RefreshTokenReqList struct {
 	Page     int `json:"page" binding:"required"`
 	PageSize int `json:"page_size" binding:"required"`
 }
+
+// Session 登录会话（一个 refresh token family）
+type Session struct {
+	SessionID    string    `json:"session_id"`
+	DeviceName   string    `json:"device_name"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	CreatedAt    time.Time `json:"created_at"`     // 登录时间
+	LastActiveAt time.Time `json:"last_active_at"` // 最近一次刷新时间
+	ExpiresAt    time.Time `json:"expires_at"`
+	Current      bool      `json:"current"` // 是否为发起请求的会话
+}
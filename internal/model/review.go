@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// StoreReview 表示数据库中的 store_reviews 表，每个用户对每个商铺只能有一条评价
+type StoreReview struct {
+	ReviewID    int       `gorm:"column:review_id;primaryKey" json:"review_id"`
+	StoreID     int       `gorm:"column:store_id;not null;uniqueIndex:uq_store_reviews_store_user" json:"store_id"`
+	UserID      int       `gorm:"column:user_id;not null;uniqueIndex:uq_store_reviews_store_user" json:"user_id"`
+	Rating      int       `gorm:"column:rating;not null" json:"rating"` // 评分 1~5
+	ReviewText  string    `gorm:"column:review_text;type:text" json:"review_text"`
+	IsPublished bool      `gorm:"column:is_published;not null" json:"is_published"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	PhotoFileIDs []int `gorm:"-" json:"photo_file_ids"` // 评价图片（files 表ID）
+}
+
+// ReviewReqPhoto 评价图片
+type ReviewReqPhoto struct {
+	FileName string `json:"file_name" binding:"required"`
+	FileType string `json:"file_type" binding:"required"`
+	FileData []byte `json:"file_data" binding:"required"`
+}
+
+// ReviewReqCreate 新建评价请求
+type ReviewReqCreate struct {
+	StoreID    int              `json:"store_id" binding:"required"`
+	Rating     int              `json:"rating" binding:"required,min=1,max=5"`
+	ReviewText string           `json:"review_text"`
+	Photos     []ReviewReqPhoto `json:"photos" binding:"omitempty,max=9,dive"`
+}
+
+// ReviewReqEdit 更新评价请求（仅本人），Photos 不为空时替换全部图片
+type ReviewReqEdit struct {
+	ReviewID   int              `json:"review_id" binding:"required"`
+	Rating     int              `json:"rating" binding:"omitempty,min=1,max=5"`
+	ReviewText *string          `json:"review_text"`
+	Photos     []ReviewReqPhoto `json:"photos" binding:"omitempty,max=9,dive"`
+}
+
+// ReviewReqPublish 评价审核请求
+type ReviewReqPublish struct {
+	IsPublished bool `json:"is_published"`
+}
+
+// ReviewReqList 评价分页请求
+type ReviewReqList struct {
+	Page        int    `json:"page" binding:"required"`
+	PageSize    int    `json:"page_size" binding:"required"`
+	Keyword     string `json:"keyword"`
+	StoreID     int    `json:"store_id"`
+	UserID      int    `json:"user_id"`
+	Rating      int    `json:"rating"`       // 按评分筛选
+	IsPublished *bool  `json:"is_published"` // 审核列表按公开状态筛选，公开列表忽略
+}
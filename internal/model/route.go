@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// RoutePoint 路线中的地点：商铺、设施，或直接指定坐标（如酒店、车站）
+type RoutePoint struct {
+	StopType  string   `json:"stop_type" binding:"omitempty,oneof=Store Facility"`
+	TargetID  int      `json:"target_id"`
+	Latitude  *float64 `json:"latitude"` // 未指定 stop_type 时使用
+	Longitude *float64 `json:"longitude"`
+}
+
+// RouteReqOptimize 访问顺序优化请求
+type RouteReqOptimize struct {
+	Stops        []RoutePoint `json:"stops" binding:"required,min=1,max=25,dive"`
+	Start        *RoutePoint  `json:"start"`                                 // 固定起点
+	End          *RoutePoint  `json:"end"`                                   // 固定终点
+	DepartAt     *time.Time   `json:"depart_at"`                             // 出发时间，指定时考虑商铺营业时间
+	DwellMinutes int          `json:"dwell_minutes" binding:"gte=0,lte=600"` // 每个停留点的停留时间，默认 30 分钟
+	SpeedKMH     float64      `json:"speed_kmh" binding:"gte=0,lte=100"`     // 移动速度，默认步行
+}
+
+// ItineraryReqOptimize 行程日停留点顺序优化请求
+type ItineraryReqOptimize struct {
+	KeepFirst    bool       `json:"keep_first"` // 第一个停留点保持为起点
+	KeepLast     bool       `json:"keep_last"`  // 最后一个停留点保持为终点
+	DepartAt     *time.Time `json:"depart_at"`
+	DwellMinutes int        `json:"dwell_minutes" binding:"gte=0,lte=600"`
+	SpeedKMH     float64    `json:"speed_kmh" binding:"gte=0,lte=100"`
+}
+
+// RouteStop 优化结果中的停留点
+type RouteStop struct {
+	StopType          string     `json:"stop_type,omitempty"`
+	TargetID          int        `json:"target_id,omitempty"`
+	Name              string     `json:"name"`
+	Latitude          float64    `json:"latitude"`
+	Longitude         float64    `json:"longitude"`
+	ArriveAt          *time.Time `json:"arrive_at"`            // 预计到达时间（指定出发时间时）
+	WaitMinutes       int        `json:"wait_minutes"`         // 等待开门的分钟数
+	Closed            bool       `json:"closed"`               // 到达时未营业且短时间内不会开门
+	DistanceFromPrevM float64    `json:"distance_from_prev_m"` // 与上一地点（含起点）的直线距离（米）
+}
+
+// RouteResult 访问顺序优化结果
+type RouteResult struct {
+	Stops          []RouteStop `json:"stops"`            // 按建议顺序排列，不含固定起点与终点
+	TotalDistanceM float64     `json:"total_distance_m"` // 含起点、终点的直线距离合计（米）
+	Violations     int         `json:"violations"`       // 无法在营业时间内到达的停留点数
+}
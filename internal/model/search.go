@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// SearchReq 全文检索请求（GET 查询参数）
+type SearchReq struct {
+	Q        string `form:"q" binding:"required,max=100"`
+	Types    string `form:"types"`    // 逗号分隔的实体类型（Article,Store,Facility），为空表示全部
+	Category string `form:"category"` // 分类筛选
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// SearchHit 检索结果
+type SearchHit struct {
+	EntityType string   `json:"entity_type"` // Article / Store / Facility
+	EntityID   int      `json:"entity_id"`
+	Title      string   `json:"title"`
+	Category   string   `json:"category"`
+	Snippet    string   `json:"snippet"` // 命中片段，关键词以 <mark></mark> 标注，其余内容已做 HTML 转义
+	Score      float64  `json:"score"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+// SearchFacet 分面统计
+type SearchFacet struct {
+	EntityType string `json:"entity_type"`
+	Category   string `json:"category,omitempty"`
+	Count      int64  `json:"count"`
+}
+
+// SearchResult 检索结果与分面
+type SearchResult struct {
+	Total      int64         `json:"total"`
+	Hits       []SearchHit   `json:"hits"`
+	Types      []SearchFacet `json:"types"`      // 按实体类型统计（不受类型、分类筛选影响）
+	Categories []SearchFacet `json:"categories"` // 按实体类型与分类统计（不受类型、分类筛选影响）
+}
+
+// SearchSuggestion 表示 search_suggestions 表：输入联想候选词
+// 由后台任务根据商铺名、设施名、标签名、文章标题定期重建
+type SearchSuggestion struct {
+	EntityType string    `gorm:"column:entity_type;type:varchar(20);primaryKey" json:"entity_type"` // Store / Facility / Tag / Article
+	EntityID   int       `gorm:"column:entity_id;primaryKey" json:"entity_id"`
+	Label      string    `gorm:"column:label;type:varchar(255);not null" json:"label"`          // 显示文本（原文）
+	Normalized string    `gorm:"column:normalized;type:varchar(255);not null" json:"-"`         // 显示文本的检索键（textnorm.Fold）
+	Reading    string    `gorm:"column:reading;type:varchar(255);not null;default:''" json:"-"` // 读音（假名）的检索键，无读音时为空
+	Popularity int64     `gorm:"column:popularity;not null;default:0" json:"-"`                 // 热度：访问、收藏、评价等加权计数
+	UpdatedAt  time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// TableName 指定表名
+func (SearchSuggestion) TableName() string {
+	return "search_suggestions"
+}
+
+// SearchSuggestReq 输入联想请求（GET 查询参数）
+type SearchSuggestReq struct {
+	Q     string `form:"q" binding:"required,max=50"`
+	Types string `form:"types"` // 逗号分隔的实体类型（Store,Facility,Tag,Article），为空表示全部
+	Limit int    `form:"limit,default=8" binding:"min=1,max=20"`
+}
@@ -6,6 +6,7 @@ import "time"
 type Store struct {
 	StoreID         int       `gorm:"column:store_id;primaryKey" json:"store_id"`
 	StoreName       string    `gorm:"column:store_name;type:varchar(255);not null" json:"store_name"`
+	StoreNameKana   string    `gorm:"column:store_name_kana;type:varchar(255)" json:"store_name_kana"` // 店名读音（假名），用于输入联想
 	StoreCategory   string    `gorm:"column:store_category;type:varchar(100);not null" json:"store_category"`
 	Location        string    `gorm:"column:location;type:varchar(255);not null" json:"location"`
 	DescriptionText string    `gorm:"column:description_text;type:text" json:"description"`
@@ -13,15 +14,23 @@ type Store struct {
 	Latitude        float64   `gorm:"column:latitude;type:decimal(10,6);not null" json:"latitude"`
 	Longitude       float64   `gorm:"column:longitude;type:decimal(10,6);not null" json:"longitude"`
 	BusinessHours   string    `gorm:"column:business_hours;type:varchar(100);not null" json:"business_hours"`
-	RatingScore     float64   `gorm:"column:rating_score;type:decimal(3,2);not null" json:"rating_score"`
+	RatingScore     float64   `gorm:"column:rating_score;type:decimal(3,2);not null;default:0" json:"rating_score"` // 由已公开评价的平均分计算
+	ReviewCount     int       `gorm:"column:review_count;not null;default:0" json:"review_count"`                   // 已公开评价数
 	PhoneNumber     string    `gorm:"column:phone_number;type:varchar(20);not null" json:"phone_number"`
 	CreatedAt       time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt       time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	IsOpenNow  *bool      `gorm:"-" json:"is_open_now"`  // 当前是否营业（未设置结构化营业时间时为 null）
+	NextOpenAt *time.Time `gorm:"-" json:"next_open_at"` // 下次开始营业时间（营业中或未知时为 null）
+
+	IsFavorited   bool  `gorm:"-" json:"is_favorited"`   // 当前用户是否已收藏（未登录时为 false）
+	FavoriteCount int64 `gorm:"-" json:"favorite_count"` // 收藏人数
 }
 
 // StoreReqCreate 创建请求
 type StoreReqCreate struct {
 	StoreName     string  `json:"store_name" binding:"required"`
+	StoreNameKana string  `json:"store_name_kana"`
 	StoreCategory string  `json:"store_category" binding:"required"`
 	Location      string  `json:"location" binding:"required"`
 	Description   string  `json:"description"`
@@ -29,7 +38,6 @@ type StoreReqCreate struct {
 	Latitude      float64 `json:"latitude" binding:"required"`
 	Longitude     float64 `json:"longitude" binding:"required"`
 	BusinessHours string  `json:"business_hours" binding:"required"`
-	RatingScore   float64 `json:"rating_score" binding:"required"`
 	PhoneNumber   string  `json:"phone_number" binding:"required"`
 }
 
@@ -37,6 +45,7 @@ type StoreReqCreate struct {
 type StoreReqEdit struct {
 	StoreID       int     `json:"store_id" binding:"required"`
 	StoreName     string  `json:"store_name"`
+	StoreNameKana string  `json:"store_name_kana"`
 	StoreCategory string  `json:"store_category"`
 	Location      string  `json:"location"`
 	Description   string  `json:"description"`
@@ -44,7 +53,6 @@ type StoreReqEdit struct {
 	Latitude      float64 `json:"latitude"`
 	Longitude     float64 `json:"longitude"`
 	BusinessHours string  `json:"business_hours"`
-	RatingScore   float64 `json:"rating_score"`
 	PhoneNumber   string  `json:"phone_number"`
 }
 
@@ -53,6 +61,9 @@ type StoreReqList struct {
 	Page     int    `json:"page" binding:"required"`
 	PageSize int    `json:"page_size" binding:"required"`
 	Keyword  string `json:"keyword"`
+	OpenNow  bool   `json:"open_now"`                                    // 仅返回当前营业中的商铺
+	TagIDs   []int  `json:"tag_ids"`                                     // 按标签筛选
+	TagMatch string `json:"tag_match" binding:"omitempty,oneof=any all"` // any=含任一标签（默认），all=含全部标签
 }
 
 // StoreDetailRequest 单个查询请求
@@ -63,3 +74,18 @@ type StoreDetailRequest struct {
 type StoreTagReq struct {
 	TagID uint `json:"tag_id" binding:"required"`
 }
+
+// StoreReqNearby 附近商铺查询请求
+type StoreReqNearby struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`    // 中心点纬度（0 为有效值，故用指针区分未提供）
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"` // 中心点经度
+	RadiusM   float64  `json:"radius_m" binding:"required,gt=0"`              // 半径（米）
+	Category  string   `json:"category"`                                      // 商铺分类（可选）
+	Limit     int      `json:"limit"`                                         // 最大返回条数（默认50）
+}
+
+// StoreNearby 附近商铺查询结果，附带与中心点的距离
+type StoreNearby struct {
+	Store
+	DistanceM float64 `gorm:"column:distance_m" json:"distance_m"` // 距离（米）
+}
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// StoreBusinessHour 表示 store_business_hours 表，每周固定营业区间
+// 结束时间小于等于开始时间表示跨越午夜（例：18:00-02:00）
+type StoreBusinessHour struct {
+	BusinessHourID int       `gorm:"column:business_hour_id;primaryKey" json:"business_hour_id"`
+	StoreID        int       `gorm:"column:store_id;not null;index" json:"store_id"`
+	Weekday        int       `gorm:"column:weekday;not null" json:"weekday"`                       // 星期：0=周日 ... 6=周六
+	OpenTime       string    `gorm:"column:open_time;type:varchar(5);not null" json:"open_time"`   // 开始时间 HH:MM
+	CloseTime      string    `gorm:"column:close_time;type:varchar(5);not null" json:"close_time"` // 结束时间 HH:MM，允许 24:00
+	CreatedAt      time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// StoreHoliday 表示 store_holidays 表，特定日期的休息日或临时营业时间
+type StoreHoliday struct {
+	HolidayID   int       `gorm:"column:holiday_id;primaryKey" json:"holiday_id"`
+	StoreID     int       `gorm:"column:store_id;not null;index" json:"store_id"`
+	HolidayDate time.Time `gorm:"column:holiday_date;type:date;not null" json:"holiday_date"`
+	IsClosed    bool      `gorm:"column:is_closed;not null" json:"is_closed"`          // 全天休息
+	OpenTime    *string   `gorm:"column:open_time;type:varchar(5)" json:"open_time"`   // 临时营业开始时间（IsClosed=false时有效）
+	CloseTime   *string   `gorm:"column:close_time;type:varchar(5)" json:"close_time"` // 临时营业结束时间（IsClosed=false时有效）
+	Note        string    `gorm:"column:note;type:varchar(255)" json:"note"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// StoreHoursInterval 每周营业区间
+type StoreHoursInterval struct {
+	Weekday   int    `json:"weekday" binding:"min=0,max=6"` // 星期：0=周日 ... 6=周六
+	OpenTime  string `json:"open_time" binding:"required"`  // HH:MM
+	CloseTime string `json:"close_time" binding:"required"` // HH:MM，小于等于开始时间表示跨越午夜
+}
+
+// StoreHolidayItem 例外日期
+type StoreHolidayItem struct {
+	Date      string `json:"date" binding:"required"` // YYYY-MM-DD（日本时间）
+	IsClosed  bool   `json:"is_closed"`               // 全天休息
+	OpenTime  string `json:"open_time"`               // 临时营业开始时间
+	CloseTime string `json:"close_time"`              // 临时营业结束时间
+	Note      string `json:"note"`
+}
+
+// StoreHours 商铺结构化营业时间（整体替换）
+type StoreHours struct {
+	Weekly   []StoreHoursInterval `json:"weekly" binding:"dive"`
+	Holidays []StoreHolidayItem   `json:"holidays" binding:"dive"`
+}
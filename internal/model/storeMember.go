@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// 商铺成员角色
+const (
+	StoreMemberOwner = "owner" // 店主，可编辑商铺信息
+	StoreMemberStaff = "staff" // 店员，可维护营业时间、照片
+)
+
+// StoreMember 表示 store_members 表，关联用户与其管理的商铺
+type StoreMember struct {
+	StoreMemberID int        `gorm:"column:store_member_id;primaryKey" json:"store_member_id"`
+	StoreID       int        `gorm:"column:store_id;not null;uniqueIndex:unique_store_member" json:"store_id"`
+	UserID        int        `gorm:"column:user_id;not null;uniqueIndex:unique_store_member;index" json:"user_id"`
+	MemberRole    string     `gorm:"column:member_role;type:varchar(20);not null" json:"member_role"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// StoreMemberReqCreate 新增商铺成员请求
+type StoreMemberReqCreate struct {
+	StoreID    int    `json:"store_id" binding:"required"`
+	UserID     int    `json:"user_id" binding:"required"`
+	MemberRole string `json:"member_role" binding:"required,oneof=owner staff"`
+}
+
+// StoreMemberReqList 商铺成员分页请求
+type StoreMemberReqList struct {
+	Page     int `json:"page" binding:"required"`
+	PageSize int `json:"page_size" binding:"required"`
+	StoreID  int `json:"store_id"` // 按商铺筛选（可选）
+	UserID   int `json:"user_id"`  // 按用户筛选（可选）
+}
+
+// MyStore 当前用户管理的商铺
+type MyStore struct {
+	Store
+	MemberRole string `gorm:"column:member_role" json:"member_role"` // 当前用户在该商铺的角色
+}
+
+// MyStoreReqList 我的商铺分页请求
+type MyStoreReqList struct {
+	Page     int `json:"page" binding:"required"`
+	PageSize int `json:"page_size" binding:"required"`
+}
+
+// MyStoreReqEdit 店主编辑商铺请求（空值字段不更新）
+type MyStoreReqEdit struct {
+	StoreName     string  `json:"store_name"`
+	StoreNameKana string  `json:"store_name_kana"`
+	StoreCategory string  `json:"store_category"`
+	Location      string  `json:"location"`
+	Description   string  `json:"description"`
+	Address       string  `json:"address"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	PhoneNumber   string  `json:"phone_number"`
+}
+
+// MyStoreReqBusinessHours 更新营业时间请求
+type MyStoreReqBusinessHours struct {
+	BusinessHours string `json:"business_hours" binding:"required"`
+}
+
+// MyStoreReqPhoto 上传商铺照片请求
+type MyStoreReqPhoto struct {
+	FileName string `json:"file_name" binding:"required"`
+	FileType string `json:"file_type" binding:"required"` // MIME类型，仅支持图片
+	FileData []byte `json:"file_data" binding:"required"` // Base64编码的图片数据
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// DefaultCurrency 菜单价格默认币种
+const DefaultCurrency = "JPY"
+
+// StoreMenuItem 表示数据库中的 store_menu_items 表（商铺菜单项，与界面导航用的 Menu 无关）
+type StoreMenuItem struct {
+	MenuItemID   int       `gorm:"column:menu_item_id;primaryKey" json:"menu_item_id"`
+	StoreID      int       `gorm:"column:store_id;not null;index" json:"store_id"`
+	Section      string    `gorm:"column:section;type:varchar(100);not null;default:''" json:"section"` // 分组（如：主食、饮品）
+	ItemName     string    `gorm:"column:item_name;type:varchar(255);not null" json:"item_name"`
+	Description  string    `gorm:"column:description;type:text" json:"description"`
+	Price        float64   `gorm:"column:price;type:decimal(10,2);not null" json:"price"`
+	Currency     string    `gorm:"column:currency;type:varchar(3);not null;default:JPY" json:"currency"` // ISO 4217 币种代码
+	PhotoFileID  *int      `gorm:"column:photo_file_id" json:"photo_file_id"`                            // 菜品图片（files 表ID）
+	Allergens    []string  `gorm:"column:allergens;type:jsonb;serializer:json" json:"allergens"`         // 过敏原（如：egg, milk, wheat）
+	IsVegan      bool      `gorm:"column:is_vegan;not null;default:false" json:"is_vegan"`
+	IsHalal      bool      `gorm:"column:is_halal;not null;default:false" json:"is_halal"`
+	IsGlutenFree bool      `gorm:"column:is_gluten_free;not null;default:false" json:"is_gluten_free"`
+	IsAvailable  bool      `gorm:"column:is_available;not null" json:"is_available"`
+	DisplayOrder int       `gorm:"column:display_order;not null;default:0" json:"display_order"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// StoreMenuItemReqCreate 新建菜单项请求
+type StoreMenuItemReqCreate struct {
+	StoreID      int      `json:"store_id"` // 店主接口以路径中的商铺ID为准
+	Section      string   `json:"section"`
+	ItemName     string   `json:"item_name" binding:"required"`
+	Description  string   `json:"description"`
+	Price        float64  `json:"price" binding:"gte=0"`
+	Currency     string   `json:"currency" binding:"omitempty,len=3"`
+	PhotoFileID  *int     `json:"photo_file_id"`
+	Allergens    []string `json:"allergens"`
+	IsVegan      bool     `json:"is_vegan"`
+	IsHalal      bool     `json:"is_halal"`
+	IsGlutenFree bool     `json:"is_gluten_free"`
+	IsAvailable  *bool    `json:"is_available"` // 默认可供应
+	DisplayOrder int      `json:"display_order"`
+}
+
+// StoreMenuItemReqEdit 更新菜单项请求，未传的字段保持不变
+type StoreMenuItemReqEdit struct {
+	MenuItemID   int       `json:"menu_item_id"` // 店主接口以路径中的菜单项ID为准
+	Section      *string   `json:"section"`
+	ItemName     *string   `json:"item_name"`
+	Description  *string   `json:"description"`
+	Price        *float64  `json:"price" binding:"omitempty,gte=0"`
+	Currency     *string   `json:"currency" binding:"omitempty,len=3"`
+	PhotoFileID  *int      `json:"photo_file_id"` // 传 0 表示移除图片
+	Allergens    *[]string `json:"allergens"`
+	IsVegan      *bool     `json:"is_vegan"`
+	IsHalal      *bool     `json:"is_halal"`
+	IsGlutenFree *bool     `json:"is_gluten_free"`
+	IsAvailable  *bool     `json:"is_available"`
+	DisplayOrder *int      `json:"display_order"`
+}
+
+// StoreMenuItemReqList 菜单项分页与搜索请求
+type StoreMenuItemReqList struct {
+	Page     int    `json:"page" binding:"required"`
+	PageSize int    `json:"page_size" binding:"required"`
+	Keyword  string `json:"keyword"`
+	StoreID  int    `json:"store_id"`
+}
+
+// StoreMenuSection 按分组聚合的菜单
+type StoreMenuSection struct {
+	Section string          `json:"section"`
+	Items   []StoreMenuItem `json:"items"`
+}
+
+// StoreMenu 商铺菜单
+type StoreMenu struct {
+	StoreID  int                `json:"store_id"`
+	Sections []StoreMenuSection `json:"sections"`
+}
@@ -9,6 +9,17 @@ type Tag struct {
 	IsActive  bool       `gorm:"column:is_active;not null;default:true" json:"is_active"`
 	CreatedAt time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt *time.Time `gorm:"column:updated_at" json:"updated_at"`
+
+	UsageCount int64 `gorm:"-" json:"usage_count"` // 关联实体数（列表接口填充）
+}
+
+// TaggableTypes 可打标签的实体类型
+var TaggableTypes = map[string]bool{
+	EntityArticle:  true,
+	EntityHistory:  true,
+	EntityStore:    true,
+	EntityComment:  true,
+	EntityFacility: true,
 }
 
 // TagReqCreate 创建Tag请求
@@ -29,14 +40,33 @@ type TagReqList struct {
 	Page     int    `json:"page" binding:"required"`
 	PageSize int    `json:"page_size" binding:"required"`
 	Keyword  string `json:"keyword"`
+	IsActive *bool  `json:"is_active"` // 按启用状态筛选，为空表示全部
+}
+
+// TagReqActive 启用/停用标签请求
+// 停用的标签不能再关联新实体，也不参与检索与展示，但已有关联保留
+type TagReqActive struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// TagReqMerge 合并标签请求：源标签的关联全部转移到目标标签后删除源标签
+type TagReqMerge struct {
+	SourceTagID int `json:"source_tag_id" binding:"required"`
+	TargetTagID int `json:"target_tag_id" binding:"required,nefield=SourceTagID"`
+}
+
+// TagUsage 标签按实体类型统计的使用次数
+type TagUsage struct {
+	TaggableType string `json:"taggable_type"`
+	Count        int64  `json:"count"`
 }
 
 // Tagging 表示 taggings 表
 type Tagging struct {
 	TaggingID    int        `gorm:"column:tagging_id;primaryKey" json:"tagging_id"`
-	TagID        int        `gorm:"column:tag_id;not null" json:"tag_id"`
-	TaggableType string     `gorm:"column:taggable_type;type:varchar(50);not null" json:"taggable_type"`
-	TaggableID   int        `gorm:"column:taggable_id;not null" json:"taggable_id"`
+	TagID        int        `gorm:"column:tag_id;not null;uniqueIndex:uq_taggings_tag_target" json:"tag_id"`
+	TaggableType string     `gorm:"column:taggable_type;type:varchar(50);not null;uniqueIndex:uq_taggings_tag_target;index:idx_taggings_target" json:"taggable_type"`
+	TaggableID   int        `gorm:"column:taggable_id;not null;uniqueIndex:uq_taggings_tag_target;index:idx_taggings_target" json:"taggable_id"`
 	CreatedAt    time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt    *time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
@@ -48,6 +78,11 @@ type TaggingReqCreate struct {
 	TaggableID   int    `json:"taggable_id" binding:"required"`
 }
 
+// TaggingReqAttach 为实体添加标签请求
+type TaggingReqAttach struct {
+	TagID int `json:"tag_id" binding:"required"`
+}
+
 // TaggingReqEdit 更新Tagging请求
 type TaggingReqEdit struct {
 	TaggingID    int    `json:"tagging_id" binding:"required"`
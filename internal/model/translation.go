@@ -0,0 +1,112 @@
+package model
+
+import "time"
+
+// 译文来源
+const (
+	TranslationSourceHuman   = "human"   // 编辑人工录入
+	TranslationSourceMachine = "machine" // 机器翻译，待编辑审核
+)
+
+// Translation 表示数据库中的 translations 表，保存各实体字段的多语言译文
+type Translation struct {
+	TranslationID    int       `gorm:"column:translation_id;primaryKey" json:"translation_id"`
+	TranslatableType string    `gorm:"column:translatable_type;type:varchar(50);not null;uniqueIndex:uq_translations_target" json:"translatable_type"` // 实体类型（如 Facility）
+	TranslatableID   int       `gorm:"column:translatable_id;not null;uniqueIndex:uq_translations_target" json:"translatable_id"`
+	FieldName        string    `gorm:"column:field_name;type:varchar(50);not null;uniqueIndex:uq_translations_target" json:"field_name"`       // 字段列名（如 description_text）
+	LanguageCode     string    `gorm:"column:language_code;type:varchar(20);not null;uniqueIndex:uq_translations_target" json:"language_code"` // 语言标签（如 zh-TW）
+	Content          string    `gorm:"column:content;type:text;not null" json:"content"`
+	Source           string    `gorm:"column:source;type:varchar(20);not null;default:human" json:"source"` // 来源：human / machine
+	IsApproved       bool      `gorm:"column:is_approved;not null" json:"is_approved"`                      // 机器译文经编辑审核后为 true，默认值只在建表脚本中声明
+	CreatedAt        time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TranslationFailure 表示数据库中的 translation_failures 表，记录后台机器翻译连续失败的目标，用于退避重试
+type TranslationFailure struct {
+	FailureID        int       `gorm:"column:failure_id;primaryKey" json:"failure_id"`
+	TranslatableType string    `gorm:"column:translatable_type;type:varchar(50);not null;uniqueIndex:uq_translation_failures_target" json:"translatable_type"`
+	TranslatableID   int       `gorm:"column:translatable_id;not null;uniqueIndex:uq_translation_failures_target" json:"translatable_id"`
+	FieldName        string    `gorm:"column:field_name;type:varchar(50);not null;uniqueIndex:uq_translation_failures_target" json:"field_name"`
+	LanguageCode     string    `gorm:"column:language_code;type:varchar(20);not null;uniqueIndex:uq_translation_failures_target" json:"language_code"`
+	Attempts         int       `gorm:"column:attempts;not null" json:"attempts"`                     // 连续失败次数
+	LastError        string    `gorm:"column:last_error;type:text" json:"last_error"`                // 最近一次失败原因
+	NextAttemptAt    time.Time `gorm:"column:next_attempt_at;not null;index" json:"next_attempt_at"` // 退避结束前不再重试
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TranslatableEntity 可翻译实体的表结构信息
+type TranslatableEntity struct {
+	Table      string   // 表名
+	PrimaryKey string   // 主键列名
+	Fields     []string // 可翻译的字段列名
+}
+
+// TranslatableEntities 可翻译实体，键为实体类型
+var TranslatableEntities = map[string]TranslatableEntity{
+	EntityFacility: {Table: "facilities", PrimaryKey: "facility_id", Fields: []string{"facility_name", "description_text"}},
+	EntityStore:    {Table: "stores", PrimaryKey: "store_id", Fields: []string{"store_name", "description_text"}},
+	EntityArticle:  {Table: "articles", PrimaryKey: "article_id", Fields: []string{"title", "body_text"}},
+	EntityNotice:   {Table: "notices", PrimaryKey: "notice_id", Fields: []string{"title", "content"}},
+	EntityMenuItem: {Table: "store_menu_items", PrimaryKey: "menu_item_id", Fields: []string{"section", "item_name", "description"}},
+}
+
+// TranslationReqCreate 新建或覆盖译文请求
+type TranslationReqCreate struct {
+	TranslatableType string `json:"translatable_type" binding:"required"`
+	TranslatableID   int    `json:"translatable_id" binding:"required"`
+	FieldName        string `json:"field_name" binding:"required"`
+	LanguageCode     string `json:"language_code" binding:"required"`
+	Content          string `json:"content" binding:"required"`
+}
+
+// TranslationReqEdit 更新译文请求
+type TranslationReqEdit struct {
+	TranslationID int    `json:"translation_id" binding:"required"`
+	Content       string `json:"content" binding:"required"`
+}
+
+// TranslationReqList 译文分页与筛选请求
+type TranslationReqList struct {
+	Page             int    `json:"page" binding:"required"`
+	PageSize         int    `json:"page_size" binding:"required"`
+	Keyword          string `json:"keyword"`
+	TranslatableType string `json:"translatable_type"`
+	TranslatableID   int    `json:"translatable_id"`
+	LanguageCode     string `json:"language_code"`
+}
+
+// TranslationReqMissing 缺失译文查询请求
+type TranslationReqMissing struct {
+	Page             int    `json:"page" binding:"required"`
+	PageSize         int    `json:"page_size" binding:"required"`
+	TranslatableType string `json:"translatable_type"` // 为空时查询全部可翻译实体
+	LanguageCode     string `json:"language_code"`     // 为空时查询全部启用语言
+}
+
+// MissingTranslation 缺失的译文
+type MissingTranslation struct {
+	TranslatableType string `gorm:"column:translatable_type" json:"translatable_type"`
+	TranslatableID   int    `gorm:"column:translatable_id" json:"translatable_id"`
+	FieldName        string `gorm:"column:field_name" json:"field_name"`
+	LanguageCode     string `gorm:"column:language_code" json:"language_code"`
+}
+
+// TranslationReqApprove 审核机器译文请求，content 不为空时同时修正译文
+type TranslationReqApprove struct {
+	Content string `json:"content"`
+}
+
+// TranslationReqReview 待审核译文分页请求
+type TranslationReqReview struct {
+	Page             int    `json:"page" binding:"required"`
+	PageSize         int    `json:"page_size" binding:"required"`
+	TranslatableType string `json:"translatable_type"`
+	LanguageCode     string `json:"language_code"`
+}
+
+// TranslationReview 待审核译文，附带原文
+type TranslationReview struct {
+	Translation
+	SourceText string `json:"source_text"`
+}
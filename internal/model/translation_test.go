@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"travel-ar-backend/internal/dbtest"
+)
+
+func TestTranslationUnapprovedRoundTrip(t *testing.T) {
+	db := dbtest.Open(t)
+	if err := db.AutoMigrate(&Translation{}); err != nil {
+		t.Fatal(err)
+	}
+	// 与建表脚本一致，列默认值为 true
+	if err := db.Exec("ALTER TABLE translations ALTER COLUMN is_approved SET DEFAULT TRUE").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	created := Translation{
+		TranslatableType: EntityFacility,
+		TranslatableID:   1,
+		FieldName:        "facility_name",
+		LanguageCode:     "en",
+		Content:          "Senso-ji Temple",
+		Source:           TranslationSourceMachine,
+	}
+	if err := db.Create(&created).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Delete(&created) })
+	var got Translation
+	if err := db.First(&got, created.TranslationID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.IsApproved {
+		t.Fatal("machine translation stored as approved")
+	}
+}
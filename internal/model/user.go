@@ -4,6 +4,64 @@ import (
 	"time"
 )
 
+// 用户角色
+const (
+	RoleAdmin      = "admin"       // 管理员
+	RoleEditor     = "editor"      // 内容编辑
+	RoleStoreOwner = "store_owner" // 店主
+	RoleTourist    = "tourist"     // 游客（默认）
+)
+
+// 账号状态
+const (
+	UserStatusPending  = "pending"  // 已注册、邮箱待验证
+	UserStatusActive   = "active"   // 正常
+	UserStatusDisabled = "disabled" // 已停用
+)
+
+// IdentityColumns 第三方登录方式对应的用户ID列
+var IdentityColumns = map[string]string{
+	"google": "google_id",
+	"apple":  "apple_id",
+	"line":   "line_id",
+}
+
+// Identity 返回用户在第三方登录方式中的ID，未绑定时为空
+func (u User) Identity(provider string) string {
+	switch provider {
+	case "google":
+		return u.GoogleID
+	case "apple":
+		return u.AppleID
+	case "line":
+		return u.LineID
+	}
+	return ""
+}
+
+// LoginMethods 返回用户可用的登录方式：password 及已绑定的第三方
+func (u User) LoginMethods() []string {
+	var methods []string
+	if u.Password != "" {
+		methods = append(methods, "password")
+	}
+	for _, p := range []string{"google", "apple", "line"} {
+		if u.Identity(p) != "" {
+			methods = append(methods, p)
+		}
+	}
+	return methods
+}
+
+// IsValidRole 判断角色是否合法
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleEditor, RoleStoreOwner, RoleTourist:
+		return true
+	}
+	return false
+}
+
 // User 表示数据库中的 users 表
 type User struct {
 	UserID           int        `gorm:"primaryKey;column:user_id" json:"user_id"`
@@ -14,14 +72,18 @@ type User struct {
 	Gender           *string    `gorm:"column:gender" json:"gender"`
 	PhoneNumber      string     `gorm:"column:phone_number" json:"phone_number"`
 	Email            string     `gorm:"column:email;not null;unique" json:"email"`
-	Password         string     `gorm:"column:password" json:"password"`
+	Password         string     `gorm:"column:password" json:"-"`
 	Avatar           string     `gorm:"column:avatar" json:"avatar"`
-	GoogleID         string     `gorm:"column:google_id" json:"google_id"`
-	AppleID          string     `gorm:"column:apple_id" json:"apple_id"`
+	GoogleID         string     `gorm:"column:google_id;uniqueIndex:uq_users_google_id,where:google_id <> ''" json:"google_id"`
+	AppleID          string     `gorm:"column:apple_id;uniqueIndex:uq_users_apple_id,where:apple_id <> ''" json:"apple_id"`
+	LineID           string     `gorm:"column:line_id;uniqueIndex:uq_users_line_id,where:line_id <> ''" json:"line_id"`
 	Provider         string     `gorm:"column:provider;not null" json:"provider"`
 	Status           string     `gorm:"column:status;not null" json:"status"`
-	VerifyCode       string     `gorm:"column:verify_code" json:"verify_code"`
+	Role             string     `gorm:"column:role;type:varchar(20);not null;default:tourist" json:"role"`
+	VerifyCode       string     `gorm:"column:verify_code" json:"-"`
 	VerifyCodeExpire *time.Time `gorm:"column:verify_code_expire" json:"verify_code_expire"`
+	VerifyAttempts   int        `gorm:"column:verify_attempts;not null;default:0" json:"-"` // 当前验证码的错误尝试次数
+	VerifySentAt     *time.Time `gorm:"column:verify_sent_at" json:"-"`                     // 最近一次发送验证码的时间
 	CreatedAt        time.Time  `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt        *time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
@@ -38,8 +100,10 @@ type UserReqCreate struct {
 	Password    string `json:"password"`
 	GoogleID    string `json:"google_id"`
 	AppleID     string `json:"apple_id"`
+	LineID      string `json:"line_id"`
 	Provider    string `json:"provider" binding:"required"`
 	Status      string `json:"status" binding:"required"`
+	Role        string `json:"role"`
 }
 
 // UserReqEdit 用户更新请求
@@ -55,10 +119,17 @@ type UserReqEdit struct {
 	Password    string `json:"password"`
 	GoogleID    string `json:"google_id"`
 	AppleID     string `json:"apple_id"`
+	LineID      string `json:"line_id"`
 	Provider    string `json:"provider"`
 	Status      string `json:"status"`
 }
 
+// UserReqRole 修改用户角色请求
+type UserReqRole struct {
+	UserID int    `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
 // UserReqList 用户分页与搜索请求
 type UserReqList struct {
 	Page     int    `json:"page" binding:"required"`
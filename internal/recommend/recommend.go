@@ -0,0 +1,237 @@
+// Package recommend 基于访问记录与收藏的个性化地点推荐
+//
+// 综合四项信号为用户尚未访问、收藏的附近商铺与设施打分：
+// 与用户去过地点的标签相似度、共同访问（去过 X 的人也去过 Y）、热度、距当前位置的距离。
+// 共同访问矩阵由后台任务预先计算并保存在 item_co_visitations 表中。
+package recommend
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/geo"
+
+	"gorm.io/gorm"
+)
+
+// maxNeighbors 共同访问矩阵中每个地点保留的相似地点数
+const maxNeighbors = 50
+
+// interactionsSQL 用户与地点的交互：有效访问记录与对商铺、设施的收藏
+const interactionsSQL = `
+SELECT user_id, 'Facility' AS item_type, facility_id AS item_id FROM visit_history WHERE is_active
+UNION
+SELECT user_id, favoritable_type, favoritable_id FROM favorites WHERE favoritable_type IN ('Store', 'Facility')`
+
+// userItemsSQL 指定用户访问、收藏过的地点，参数为两次用户ID
+const userItemsSQL = `
+SELECT 'Facility' AS item_type, facility_id AS item_id FROM visit_history WHERE user_id = ? AND is_active
+UNION
+SELECT favoritable_type, favoritable_id FROM favorites WHERE user_id = ? AND favoritable_type IN ('Store', 'Facility')`
+
+// candidatesSQL 范围内的商铺与设施及其热度，参数为两组经纬度范围
+// 热度：商铺为公开评价数加收藏数，设施为有效访问数加收藏数
+const candidatesSQL = `
+SELECT 'Store' AS entity_type, s.store_id AS entity_id,
+	CAST(s.latitude AS DOUBLE PRECISION) AS latitude, CAST(s.longitude AS DOUBLE PRECISION) AS longitude,
+	s.review_count + (SELECT COUNT(*) FROM favorites fv
+		WHERE fv.favoritable_type = 'Store' AND fv.favoritable_id = s.store_id) AS popularity
+FROM stores s
+WHERE s.latitude BETWEEN ? AND ? AND s.longitude BETWEEN ? AND ?
+UNION ALL
+SELECT 'Facility', f.facility_id,
+	CAST(f.latitude AS DOUBLE PRECISION), CAST(f.longitude AS DOUBLE PRECISION),
+	(SELECT COUNT(*) FROM visit_history v WHERE v.facility_id = f.facility_id AND v.is_active)
+	+ (SELECT COUNT(*) FROM favorites fv
+		WHERE fv.favoritable_type = 'Facility' AND fv.favoritable_id = f.facility_id)
+FROM facilities f
+WHERE f.latitude BETWEEN ? AND ? AND f.longitude BETWEEN ? AND ?`
+
+// Query 推荐条件
+type Query struct {
+	UserID    int
+	Latitude  float64
+	Longitude float64
+	RadiusM   float64
+	Types     []string // 为空表示商铺与设施
+	Limit     int
+}
+
+type itemKey struct {
+	Type string
+	ID   int
+}
+
+// Recommend 返回按得分排序的推荐地点，排除用户已访问、收藏过的地点
+func Recommend(db *gorm.DB, q Query) ([]Scored, error) {
+	// 用户去过的地点
+	var seenRows []struct {
+		ItemType string
+		ItemID   int
+	}
+	if err := db.Raw(userItemsSQL, q.UserID, q.UserID).Scan(&seenRows).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[itemKey]bool, len(seenRows))
+	for _, r := range seenRows {
+		seen[itemKey{r.ItemType, r.ItemID}] = true
+	}
+
+	// 范围内的候选
+	minLat, minLng, maxLat, maxLng := geo.BoundingBox(q.Latitude, q.Longitude, q.RadiusM)
+	var rows []struct {
+		EntityType string
+		EntityID   int
+		Latitude   float64
+		Longitude  float64
+		Popularity float64
+	}
+	if err := db.Raw(candidatesSQL, minLat, maxLat, minLng, maxLng, minLat, maxLat, minLng, maxLng).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	types := map[string]bool{}
+	for _, t := range q.Types {
+		types[t] = true
+	}
+	candidates := make([]Candidate, 0, len(rows))
+	index := map[itemKey]int{}
+	ids := map[string][]int{}
+	for _, r := range rows {
+		k := itemKey{r.EntityType, r.EntityID}
+		if seen[k] || (len(types) > 0 && !types[r.EntityType]) {
+			continue
+		}
+		d := geo.Distance(q.Latitude, q.Longitude, r.Latitude, r.Longitude)
+		if d > q.RadiusM {
+			continue
+		}
+		index[k] = len(candidates)
+		ids[r.EntityType] = append(ids[r.EntityType], r.EntityID)
+		candidates = append(candidates, Candidate{
+			EntityType: r.EntityType,
+			EntityID:   r.EntityID,
+			DistanceM:  d,
+			Popularity: r.Popularity,
+		})
+	}
+	if len(candidates) == 0 {
+		return []Scored{}, nil
+	}
+
+	profile := map[int]float64{}
+	if len(seen) > 0 {
+		// 标签偏好：去过的地点上各个已启用标签出现的次数
+		var tagRows []struct {
+			TagID  int
+			Weight float64
+		}
+		if err := db.Raw(`
+SELECT tg.tag_id, COUNT(*) AS weight
+FROM taggings tg
+JOIN tags t ON t.tag_id = tg.tag_id AND t.is_active
+JOIN (`+userItemsSQL+`) u ON u.item_type = tg.taggable_type AND u.item_id = tg.taggable_id
+GROUP BY tg.tag_id`, q.UserID, q.UserID).Scan(&tagRows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range tagRows {
+			profile[r.TagID] = r.Weight
+		}
+
+		// 共同访问：以用户去过的地点为起点的相似地点得分之和
+		var coRows []struct {
+			OtherType string
+			OtherID   int
+			Score     float64
+		}
+		if err := db.Raw(`
+SELECT cv.other_type, cv.other_id, SUM(cv.score) AS score
+FROM item_co_visitations cv
+JOIN (`+userItemsSQL+`) u ON u.item_type = cv.item_type AND u.item_id = cv.item_id
+GROUP BY cv.other_type, cv.other_id`, q.UserID, q.UserID).Scan(&coRows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range coRows {
+			if i, ok := index[itemKey{r.OtherType, r.OtherID}]; ok {
+				candidates[i].CoVisit = r.Score
+			}
+		}
+	}
+
+	// 候选的标签
+	if len(profile) > 0 {
+		for entityType, list := range ids {
+			var tagRows []struct {
+				TaggableID int
+				TagID      int
+			}
+			if err := db.Model(&model.Tagging{}).
+				Select("taggings.taggable_id, taggings.tag_id").
+				Joins("JOIN tags ON tags.tag_id = taggings.tag_id AND tags.is_active").
+				Where("taggings.taggable_type = ? AND taggings.taggable_id IN ?", entityType, list).
+				Scan(&tagRows).Error; err != nil {
+				return nil, err
+			}
+			for _, r := range tagRows {
+				i := index[itemKey{entityType, r.TaggableID}]
+				candidates[i].Tags = append(candidates[i].Tags, r.TagID)
+			}
+		}
+	}
+
+	return Rank(candidates, profile, q.RadiusM, DefaultWeights, q.Limit), nil
+}
+
+// RebuildCoVisitations 重新计算共同访问矩阵，返回写入的行数
+// 在同一事务内清空并写入，重建期间读取方仍看到旧数据
+func RebuildCoVisitations(db *gorm.DB) (int64, error) {
+	var n int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.ItemCoVisitation{}).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(`
+WITH interactions AS (`+interactionsSQL+`),
+counts AS (
+	SELECT item_type, item_id, COUNT(*) AS n FROM interactions GROUP BY item_type, item_id
+),
+pairs AS (
+	SELECT a.item_type, a.item_id, b.item_type AS other_type, b.item_id AS other_id,
+		COUNT(*) AS users, COUNT(*) / SQRT(MAX(ca.n) * MAX(cb.n)) AS score
+	FROM interactions a
+	JOIN interactions b ON b.user_id = a.user_id AND (b.item_type, b.item_id) <> (a.item_type, a.item_id)
+	JOIN counts ca ON ca.item_type = a.item_type AND ca.item_id = a.item_id
+	JOIN counts cb ON cb.item_type = b.item_type AND cb.item_id = b.item_id
+	GROUP BY a.item_type, a.item_id, b.item_type, b.item_id
+),
+ranked AS (
+	SELECT pairs.*, ROW_NUMBER() OVER (PARTITION BY item_type, item_id ORDER BY score DESC, users DESC) AS rn
+	FROM pairs
+)
+INSERT INTO item_co_visitations (item_type, item_id, other_type, other_id, users, score, updated_at)
+SELECT item_type, item_id, other_type, other_id, users, score, ?
+FROM ranked
+WHERE rn <= ?`, time.Now(), maxNeighbors)
+		n = result.RowsAffected
+		return result.Error
+	})
+	return n, err
+}
+
+// RunJob 每隔 interval 重建一次共同访问矩阵，直到 ctx 结束
+func RunJob(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := RebuildCoVisitations(db.WithContext(ctx)); err != nil && ctx.Err() == nil {
+			log.Printf("co-visitation job: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
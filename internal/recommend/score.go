@@ -0,0 +1,104 @@
+package recommend
+
+import (
+	"math"
+	"sort"
+)
+
+// 推荐理由
+const (
+	ReasonTags       = "similar_tags" // 与访问、收藏过的地点标签相近
+	ReasonCoVisit    = "co_visited"   // 访问过相同地点的用户也去过
+	ReasonPopular    = "popular"      // 访问、收藏人数多
+	ReasonNearby     = "nearby"       // 距当前位置近
+	reasonMinPartial = 0.5            // 单项得分不低于该值时计入推荐理由
+)
+
+// Weights 各项得分的权重
+type Weights struct {
+	Tags       float64
+	CoVisit    float64
+	Popularity float64
+	Distance   float64
+}
+
+// DefaultWeights 默认权重：个性化信号（标签、共同访问）优先，热度与距离用于补充和冷启动
+var DefaultWeights = Weights{Tags: 0.35, CoVisit: 0.35, Popularity: 0.15, Distance: 0.15}
+
+// Candidate 候选地点
+type Candidate struct {
+	EntityType string
+	EntityID   int
+	DistanceM  float64
+	Tags       []int
+	Popularity float64 // 访问、收藏等计数
+	CoVisit    float64 // 与用户已访问、收藏地点的共同访问得分之和
+}
+
+// Scored 打分后的候选
+type Scored struct {
+	Candidate
+	Score   float64
+	Reasons []string
+}
+
+// Rank 计算候选得分并按得分降序返回前 limit 个（limit<=0 表示全部）
+// profile 为用户标签偏好（标签ID → 权重），radiusM 为距离得分归零的半径
+// 标签得分为候选标签在偏好中的权重占比，共同访问与热度按候选中的最大值归一化（热度取对数）
+func Rank(candidates []Candidate, profile map[int]float64, radiusM float64, w Weights, limit int) []Scored {
+	var profileTotal, profileMax, maxCoVisit, maxPopularity float64
+	for _, v := range profile {
+		profileTotal += v
+		profileMax = math.Max(profileMax, v)
+	}
+	for _, c := range candidates {
+		maxCoVisit = math.Max(maxCoVisit, c.CoVisit)
+		maxPopularity = math.Max(maxPopularity, c.Popularity)
+	}
+
+	scored := make([]Scored, 0, len(candidates))
+	for _, c := range candidates {
+		var tags, coVisit, popularity, distance float64
+		if profileTotal > 0 && len(c.Tags) > 0 {
+			var hit, best float64
+			for _, t := range c.Tags {
+				hit += profile[t]
+				best = math.Max(best, profile[t])
+			}
+			// 命中偏好最强的标签即得满分的一半，其余按命中权重占比补足
+			tags = math.Min(1, 0.5*best/profileMax+0.5*hit/profileTotal)
+		}
+		if maxCoVisit > 0 {
+			coVisit = c.CoVisit / maxCoVisit
+		}
+		if maxPopularity > 0 {
+			popularity = math.Log1p(c.Popularity) / math.Log1p(maxPopularity)
+		}
+		if radiusM > 0 {
+			distance = math.Max(0, 1-c.DistanceM/radiusM)
+		}
+
+		s := Scored{Candidate: c, Reasons: []string{}}
+		s.Score = w.Tags*tags + w.CoVisit*coVisit + w.Popularity*popularity + w.Distance*distance
+		for _, r := range []struct {
+			name  string
+			value float64
+		}{{ReasonTags, tags}, {ReasonCoVisit, coVisit}, {ReasonPopular, popularity}, {ReasonNearby, distance}} {
+			if r.value >= reasonMinPartial {
+				s.Reasons = append(s.Reasons, r.name)
+			}
+		}
+		scored = append(scored, s)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].DistanceM < scored[j].DistanceM
+	})
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
@@ -0,0 +1,65 @@
+package recommend
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankColdStart(t *testing.T) {
+	// 无偏好、无共同访问时按热度与距离排序
+	got := Rank([]Candidate{
+		{EntityType: "Store", EntityID: 1, DistanceM: 900, Popularity: 0},
+		{EntityType: "Store", EntityID: 2, DistanceM: 100, Popularity: 10},
+		{EntityType: "Facility", EntityID: 3, DistanceM: 500, Popularity: 10},
+	}, nil, 1000, DefaultWeights, 0)
+	ids := []int{got[0].EntityID, got[1].EntityID, got[2].EntityID}
+	if ids[0] != 2 || ids[1] != 3 || ids[2] != 1 {
+		t.Fatalf("order = %v", ids)
+	}
+	if !hasReason(got[0], ReasonPopular) || !hasReason(got[0], ReasonNearby) {
+		t.Errorf("reasons = %v", got[0].Reasons)
+	}
+	if hasReason(got[2], ReasonPopular) {
+		t.Errorf("unexpected popular reason for %v", got[2])
+	}
+}
+
+func TestRankPersonalized(t *testing.T) {
+	profile := map[int]float64{1: 3, 2: 1}
+	got := Rank([]Candidate{
+		{EntityType: "Store", EntityID: 1, DistanceM: 100, Popularity: 50},
+		{EntityType: "Facility", EntityID: 2, DistanceM: 800, Tags: []int{1}},
+		{EntityType: "Facility", EntityID: 3, DistanceM: 800, CoVisit: 2},
+	}, profile, 1000, DefaultWeights, 2)
+	if len(got) != 2 {
+		t.Fatalf("len = %d", len(got))
+	}
+	// 个性化信号优先于近处的热门商铺
+	if got[0].EntityID != 3 || got[1].EntityID != 2 {
+		t.Fatalf("order = %d, %d", got[0].EntityID, got[1].EntityID)
+	}
+	if !hasReason(got[0], ReasonCoVisit) || !hasReason(got[1], ReasonTags) {
+		t.Errorf("reasons = %v / %v", got[0].Reasons, got[1].Reasons)
+	}
+	// 标签得分：0.5*3/3 + 0.5*3/4 = 0.875
+	want := 0.35*0.875 + 0.15*0.2
+	if math.Abs(got[1].Score-want) > 1e-9 {
+		t.Errorf("score = %v, want %v", got[1].Score, want)
+	}
+}
+
+func TestRankOutsideRadius(t *testing.T) {
+	got := Rank([]Candidate{{EntityID: 1, DistanceM: 2000}}, nil, 1000, DefaultWeights, 0)
+	if got[0].Score != 0 || len(got[0].Reasons) != 0 {
+		t.Errorf("got %+v", got[0])
+	}
+}
+
+func hasReason(s Scored, reason string) bool {
+	for _, r := range s.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ARAnchorRouter AR锚点路由模块
+type ARAnchorRouter struct{}
+
+// Register 注册AR锚点路由
+func (ARAnchorRouter) Register(r *gin.RouterGroup) {
+	anchor := r.Group("/ar_anchors")
+	{
+		anchor.GET("/resolve/:marker_code", controller.ResolveARMarker)
+	}
+
+	// 管理员、内容编辑（锚点配置含未启用内容，不公开）
+	anchorAdmin := r.Group("/ar_anchors", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		anchorAdmin.POST("", controller.CreateARAnchor)
+		anchorAdmin.PUT("", controller.UpdateARAnchor)
+		anchorAdmin.DELETE(":anchor_id", controller.DeleteARAnchor)
+		anchorAdmin.GET(":anchor_id", controller.GetARAnchor)
+		anchorAdmin.POST("/list", controller.ListARAnchors)
+	}
+}
+
+func init() {
+	Register(ARAnchorRouter{})
+}
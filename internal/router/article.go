@@ -2,7 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
-	"travel-ar-backend/internal/middleware"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,16 +12,18 @@ type ArticleRouter struct{}
 
 // Register 注册文章路由
 func (ArticleRouter) Register(api *gin.RouterGroup) {
-	article := api.Group("/articles")
-	api.GET("/articles/:article_id", controller.GetArticle)
-	api.POST("/articles/list", controller.ListArticles)
+	article := api.Group("/articles", optionalAuth())
+	{
+		article.GET(":article_id", controller.GetArticle)
+		article.POST("/list", controller.ListArticles)
+	}
 
-	aritcleAuth := article.Group("/articles")
-	aritcleAuth.Use(middleware.JWTAuth())
+	// 管理员、内容编辑
+	articleAuth := api.Group("/articles", authorize(model.RoleAdmin, model.RoleEditor)...)
 	{
-		article.POST("", controller.CreateArticle)
-		article.PUT("", controller.UpdateArticle)
-		article.DELETE(":article_id", controller.DeleteArticle)
+		articleAuth.POST("", controller.CreateArticle)
+		articleAuth.PUT("", controller.UpdateArticle)
+		articleAuth.DELETE(":article_id", controller.DeleteArticle)
 	}
 }
 
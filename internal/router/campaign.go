@@ -0,0 +1,40 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CampaignRouter 集章活动路由模块
+type CampaignRouter struct{}
+
+// Register 注册集章活动路由
+func (CampaignRouter) Register(r *gin.RouterGroup) {
+	campaign := r.Group("/campaigns")
+	{
+		campaign.GET(":campaign_id", controller.GetCampaign)
+		campaign.POST("/list", controller.ListCampaigns)
+	}
+
+	// 登录用户
+	campaignUser := r.Group("/campaigns", authorize()...)
+	{
+		campaignUser.GET(":campaign_id/progress", controller.GetCampaignProgress)
+		campaignUser.GET("/rewards/mine", controller.ListMyCampaignRewards)
+	}
+
+	// 管理员、内容编辑
+	campaignAdmin := r.Group("/campaigns", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		campaignAdmin.POST("", controller.CreateCampaign)
+		campaignAdmin.PUT("", controller.UpdateCampaign)
+		campaignAdmin.DELETE(":campaign_id", controller.DeleteCampaign)
+		campaignAdmin.GET(":campaign_id/stats", controller.GetCampaignStats)
+	}
+}
+
+func init() {
+	Register(CampaignRouter{})
+}
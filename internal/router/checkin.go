@@ -0,0 +1,30 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckinRouter 扫码签到路由模块
+type CheckinRouter struct{}
+
+// Register 注册扫码签到路由
+func (CheckinRouter) Register(r *gin.RouterGroup) {
+	// 任意已登录用户
+	checkin := r.Group("/checkin", authorize()...)
+	{
+		checkin.POST("/scan", controller.ScanCheckin)
+	}
+
+	// 管理员、内容编辑（设施现场的二维码显示终端）
+	checkinAdmin := r.Group("/checkin", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		checkinAdmin.GET("/qr/:facility_id", controller.GetCheckinQR)
+	}
+}
+
+func init() {
+	Register(CheckinRouter{})
+}
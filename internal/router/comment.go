@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +14,22 @@ type CommentRouter struct{}
 func (CommentRouter) Register(r *gin.RouterGroup) {
 	comment := r.Group("/comments")
 	{
-		comment.POST("", controller.CreateComment)
-		comment.PUT("", controller.UpdateComment)
-		comment.DELETE(":comment_id", controller.DeleteComment)
 		comment.GET(":comment_id", controller.GetComment)
 		comment.POST("/list", controller.ListComments)
 	}
+
+	// 任意已登录用户
+	commentUser := r.Group("/comments", authorize()...)
+	{
+		commentUser.POST("", controller.CreateComment)
+	}
+
+	// 管理员、内容编辑
+	commentAdmin := r.Group("/comments", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		commentAdmin.PUT("", controller.UpdateComment)
+		commentAdmin.DELETE(":comment_id", controller.DeleteComment)
+	}
 }
 
 func init() {
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CouponRouter 优惠券路由模块
+type CouponRouter struct{}
+
+// Register 注册优惠券路由
+// 商铺自助管理与核销接口见 MyStoreRouter
+func (CouponRouter) Register(r *gin.RouterGroup) {
+	coupon := r.Group("/coupons")
+	{
+		coupon.GET(":coupon_id", controller.GetCoupon)
+		coupon.POST("/list", controller.ListCoupons)
+	}
+
+	// 登录用户
+	couponUser := r.Group("/coupons", authorize()...)
+	{
+		couponUser.POST(":coupon_id/claim", controller.ClaimCoupon)
+		couponUser.GET("/wallet", controller.ListMyCoupons)
+	}
+
+	// 管理员、内容编辑
+	couponAdmin := r.Group("/coupons", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		couponAdmin.POST("", controller.CreateCoupon)
+		couponAdmin.PUT("", controller.UpdateCoupon)
+		couponAdmin.DELETE(":coupon_id", controller.DeleteCoupon)
+	}
+}
+
+func init() {
+	Register(CouponRouter{})
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,13 +12,19 @@ type FacilityRouter struct{}
 
 // Register 注册设施路由
 func (FacilityRouter) Register(r *gin.RouterGroup) {
-	facility := r.Group("/facilities")
+	facility := r.Group("/facilities", optionalAuth())
 	{
-		facility.POST("", controller.CreateFacility)
-		facility.PUT(":id", controller.UpdateFacility)
-		facility.DELETE(":id", controller.DeleteFacility)
 		facility.GET(":id", controller.GetFacility)
 		facility.POST("/list", controller.ListFacilities)
+		facility.POST("/nearby", controller.NearbyFacilities)
+	}
+
+	// 管理员、内容编辑
+	facilityAdmin := r.Group("/facilities", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		facilityAdmin.POST("", controller.CreateFacility)
+		facilityAdmin.PUT(":id", controller.UpdateFacility)
+		facilityAdmin.DELETE(":id", controller.DeleteFacility)
 	}
 }
 
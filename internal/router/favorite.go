@@ -0,0 +1,25 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FavoriteRouter 收藏路由模块
+type FavoriteRouter struct{}
+
+// Register 注册收藏路由
+func (FavoriteRouter) Register(r *gin.RouterGroup) {
+	// 登录用户
+	favorite := r.Group("/favorites", authorize()...)
+	{
+		favorite.POST("", controller.AddFavorite)
+		favorite.DELETE(":favoritable_type/:favoritable_id", controller.RemoveFavorite)
+		favorite.POST("/mine", controller.ListMyFavorites)
+	}
+}
+
+func init() {
+	Register(FavoriteRouter{})
+}
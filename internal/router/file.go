@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +14,18 @@ type FileRouter struct{}
 func (FileRouter) Register(r *gin.RouterGroup) {
 	file := r.Group("/files")
 	{
-		file.POST("", controller.CreateFile)
-		file.PUT("", controller.UpdateFile)
-		file.DELETE(":file_id", controller.DeleteFile)
 		file.GET(":file_id", controller.GetFile)
+		file.GET(":file_id/content", controller.GetFileContent)
 		file.POST("/list", controller.ListFiles)
 	}
+
+	// 管理员、内容编辑
+	fileAdmin := r.Group("/files", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		fileAdmin.POST("", controller.CreateFile)
+		fileAdmin.PUT("", controller.UpdateFile)
+		fileAdmin.DELETE(":file_id", controller.DeleteFile)
+	}
 }
 
 func init() {
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ItineraryRouter 行程路由模块
+type ItineraryRouter struct{}
+
+// Register 注册行程路由
+func (ItineraryRouter) Register(r *gin.RouterGroup) {
+	// 分享链接只读查看，无需登录
+	itinerary := r.Group("/itineraries")
+	{
+		itinerary.GET("/shared/:share_token", controller.GetSharedItinerary)
+	}
+
+	// 登录用户，只能操作自己的行程
+	itineraryUser := r.Group("/itineraries", authorize()...)
+	{
+		itineraryUser.POST("", controller.CreateItinerary)
+		itineraryUser.PUT("", controller.UpdateItinerary)
+		itineraryUser.POST("/mine", controller.ListMyItineraries)
+		itineraryUser.GET(":itinerary_id", controller.GetItinerary)
+		itineraryUser.DELETE(":itinerary_id", controller.DeleteItinerary)
+		itineraryUser.POST(":itinerary_id/share", controller.ShareItinerary)
+		itineraryUser.DELETE(":itinerary_id/share", controller.UnshareItinerary)
+		itineraryUser.POST("/shared/:share_token/clone", controller.CloneItinerary)
+
+		itineraryUser.POST(":itinerary_id/days", controller.AddItineraryDay)
+		itineraryUser.PUT(":itinerary_id/days/:day_id", controller.UpdateItineraryDay)
+		itineraryUser.DELETE(":itinerary_id/days/:day_id", controller.DeleteItineraryDay)
+		itineraryUser.POST(":itinerary_id/days/:day_id/duplicate", controller.DuplicateItineraryDay)
+
+		itineraryUser.POST(":itinerary_id/days/:day_id/stops", controller.AddItineraryStop)
+		itineraryUser.PUT(":itinerary_id/days/:day_id/stops/order", controller.ReorderItineraryStops)
+		itineraryUser.POST(":itinerary_id/days/:day_id/stops/optimize", controller.OptimizeItineraryStops)
+		itineraryUser.PUT(":itinerary_id/days/:day_id/stops/:stop_id", controller.UpdateItineraryStop)
+		itineraryUser.DELETE(":itinerary_id/days/:day_id/stops/:stop_id", controller.DeleteItineraryStop)
+	}
+}
+
+func init() {
+	Register(ItineraryRouter{})
+}
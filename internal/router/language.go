@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,11 +14,16 @@ type LanguageRouter struct{}
 func (LanguageRouter) Register(r *gin.RouterGroup) {
 	languages := r.Group("/languages")
 	{
-		languages.POST("", controller.CreateLanguage)               // 新建语言
-		languages.PUT("", controller.UpdateLanguage)                // 更新语言
-		languages.DELETE(":language_id", controller.DeleteLanguage) // 删除语言
-		languages.GET(":language_id", controller.GetLanguage)       // 获取单个语言
-		languages.POST("/list", controller.ListLanguages)           // 获取语言分页列表
+		languages.GET(":language_id", controller.GetLanguage) // 获取单个语言
+		languages.POST("/list", controller.ListLanguages)     // 获取语言分页列表
+	}
+
+	// 仅管理员
+	languagesAdmin := r.Group("/languages", authorize(model.RoleAdmin)...)
+	{
+		languagesAdmin.POST("", controller.CreateLanguage)               // 新建语言
+		languagesAdmin.PUT("", controller.UpdateLanguage)                // 更新语言
+		languagesAdmin.DELETE(":language_id", controller.DeleteLanguage) // 删除语言
 	}
 }
 
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MapRouter 地图路由模块
+type MapRouter struct{}
+
+// Register 注册地图路由
+func (MapRouter) Register(r *gin.RouterGroup) {
+	mapGroup := r.Group("/map")
+	{
+		mapGroup.POST("/features", controller.ViewportFeatures)
+	}
+}
+
+func init() {
+	Register(MapRouter{})
+}
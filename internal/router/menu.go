@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +14,17 @@ type MenuRouter struct{}
 func (MenuRouter) Register(r *gin.RouterGroup) {
 	menu := r.Group("/menus")
 	{
-		menu.POST("", controller.CreateMenu)
-		menu.PUT("", controller.UpdateMenu)
-		menu.DELETE(":menu_id", controller.DeleteMenu)
 		menu.GET(":menu_id", controller.GetMenu)
 		menu.POST("/list", controller.ListMenus)
 	}
+
+	// 仅管理员
+	menuAdmin := r.Group("/menus", authorize(model.RoleAdmin)...)
+	{
+		menuAdmin.POST("", controller.CreateMenu)
+		menuAdmin.PUT("", controller.UpdateMenu)
+		menuAdmin.DELETE(":menu_id", controller.DeleteMenu)
+	}
 }
 
 func init() {
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MyStoreRouter 店主自助管理路由模块
+type MyStoreRouter struct{}
+
+// Register 注册店主自助管理路由
+// 任意已登录用户均可访问，具体商铺的权限由控制器按 store_members 校验
+func (MyStoreRouter) Register(r *gin.RouterGroup) {
+	myStore := r.Group("/my/stores", authorize()...)
+	{
+		myStore.POST("/list", controller.ListMyStores)
+		myStore.GET(":store_id", controller.GetMyStore)
+		myStore.PUT(":store_id", controller.UpdateMyStore)
+		myStore.PUT(":store_id/business_hours", controller.UpdateMyStoreBusinessHours)
+		myStore.PUT(":store_id/hours", controller.UpdateMyStoreHours)
+		myStore.GET(":store_id/photos", controller.ListMyStorePhotos)
+		myStore.POST(":store_id/photos", controller.UploadMyStorePhoto)
+		myStore.DELETE(":store_id/photos/:file_id", controller.DeleteMyStorePhoto)
+		myStore.POST(":store_id/menu_items", controller.CreateMyStoreMenuItem)
+		myStore.PUT(":store_id/menu_items/:menu_item_id", controller.UpdateMyStoreMenuItem)
+		myStore.DELETE(":store_id/menu_items/:menu_item_id", controller.DeleteMyStoreMenuItem)
+		myStore.GET(":store_id/coupons", controller.ListMyStoreCoupons)
+		myStore.POST(":store_id/coupons", controller.CreateMyStoreCoupon)
+		myStore.PUT(":store_id/coupons/:coupon_id", controller.UpdateMyStoreCoupon)
+		myStore.DELETE(":store_id/coupons/:coupon_id", controller.DeleteMyStoreCoupon)
+		myStore.POST(":store_id/coupons/redeem", controller.RedeemMyStoreCoupon)
+		myStore.GET(":store_id/coupons/report", controller.GetMyStoreCouponReport)
+		myStore.POST(":store_id/campaign_rewards/redeem", controller.RedeemMyStoreCampaignReward)
+	}
+}
+
+func init() {
+	Register(MyStoreRouter{})
+}
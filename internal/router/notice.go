@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +14,17 @@ type NoticeRouter struct{}
 func (NoticeRouter) Register(r *gin.RouterGroup) {
 	notice := r.Group("/notices")
 	{
-		notice.POST("", controller.CreateNotice)
-		notice.PUT("", controller.UpdateNotice)
-		notice.DELETE(":notice_id", controller.DeleteNotice)
 		notice.GET(":notice_id", controller.GetNotice)
 		notice.POST("/list", controller.ListNotices)
 	}
+
+	// 管理员、内容编辑
+	noticeAdmin := r.Group("/notices", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		noticeAdmin.POST("", controller.CreateNotice)
+		noticeAdmin.PUT("", controller.UpdateNotice)
+		noticeAdmin.DELETE(":notice_id", controller.DeleteNotice)
+	}
 }
 
 func init() {
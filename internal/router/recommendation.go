@@ -0,0 +1,30 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecommendationRouter 个性化推荐路由模块
+type RecommendationRouter struct{}
+
+// Register 注册个性化推荐路由
+func (RecommendationRouter) Register(r *gin.RouterGroup) {
+	// 登录用户
+	recommendation := r.Group("/recommendations", authorize()...)
+	{
+		recommendation.POST("", controller.GetRecommendations)
+	}
+
+	// 管理员
+	recommendationAdmin := r.Group("/recommendations", authorize(model.RoleAdmin)...)
+	{
+		recommendationAdmin.POST("/rebuild", controller.RebuildCoVisitations)
+	}
+}
+
+func init() {
+	Register(RecommendationRouter{})
+}
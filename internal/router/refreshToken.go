@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,9 +10,9 @@ import (
 // RefreshTokenRouter Refresh Token 路由模块
 type RefreshTokenRouter struct{}
 
-// Register 注册 Refresh Token 路由
+// Register 注册 Refresh Token 路由（仅管理员）
 func (RefreshTokenRouter) Register(r *gin.RouterGroup) {
-	refreshToken := r.Group("/refresh_tokens")
+	refreshToken := r.Group("/refresh_tokens", authorize(model.RoleAdmin)...)
 	{
 		refreshToken.POST("", controller.CreateRefreshToken)
 		refreshToken.PUT("", controller.UpdateRefreshToken)
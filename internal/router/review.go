@@ -0,0 +1,40 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewRouter 商铺评价路由模块
+type ReviewRouter struct{}
+
+// Register 注册商铺评价路由
+func (ReviewRouter) Register(r *gin.RouterGroup) {
+	review := r.Group("/reviews")
+	{
+		review.GET(":review_id", controller.GetReview)
+		review.POST("/list", controller.ListReviews)
+	}
+
+	// 任意已登录用户（修改、删除时校验本人）
+	reviewUser := r.Group("/reviews", authorize()...)
+	{
+		reviewUser.POST("", controller.CreateReview)
+		reviewUser.PUT("", controller.UpdateReview)
+		reviewUser.DELETE(":review_id", controller.DeleteReview)
+		reviewUser.GET("/mine", controller.ListMyReviews)
+	}
+
+	// 管理员、内容编辑审核
+	reviewAdmin := r.Group("/reviews", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		reviewAdmin.PUT(":review_id/publish", controller.PublishReview)
+		reviewAdmin.POST("/moderation/list", controller.ListModerationReviews)
+	}
+}
+
+func init() {
+	Register(ReviewRouter{})
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteRouter 路线优化路由模块
+type RouteRouter struct{}
+
+// Register 注册路线优化路由
+func (RouteRouter) Register(r *gin.RouterGroup) {
+	routes := r.Group("/routes")
+	{
+		routes.POST("/optimize", controller.OptimizeRoute)
+	}
+}
+
+func init() {
+	Register(RouteRouter{})
+}
@@ -19,6 +19,20 @@ func Register(rr RouteRegister) {
 	routeRegisters = append(routeRegisters, rr)
 }
 
+// authorize 返回登录校验与角色校验中间件；roles 为空时只要求登录
+func authorize(roles ...string) []gin.HandlerFunc {
+	handlers := []gin.HandlerFunc{middleware.JWTAuth()}
+	if len(roles) > 0 {
+		handlers = append(handlers, middleware.RequireRole(roles...))
+	}
+	return handlers
+}
+
+// optionalAuth 返回可选登录中间件，用于公开接口中按当前用户返回的字段
+func optionalAuth() gin.HandlerFunc {
+	return middleware.OptionalJWTAuth()
+}
+
 // InitRouter 初始化路由
 func InitRouter() *gin.Engine {
 	r := gin.Default()
@@ -26,19 +40,37 @@ func InitRouter() *gin.Engine {
 
 	api.POST("/login", controller.Login)
 	api.POST("/register", controller.Register)
+	api.POST("/verify", controller.VerifyEmail)
+	api.POST("/verify/resend", controller.ResendVerifyCode)
 	api.POST("/refresh", controller.RefreshToken)
 	api.POST("/logout", controller.RevokeRefreshToken)
+	api.GET("/me", middleware.JWTAuth(), controller.UserProfile)
+	api.POST("/password/reset", controller.RequestPasswordReset)
+	api.POST("/password/reset/confirm", controller.ConfirmPasswordReset)
 
 	// 注册所有模块路由
 	for _, rr := range routeRegisters {
 		rr.Register(api)
 	}
 
+	// 第三方登录：移动端提交 ID token，Web 端跳转授权后回调，均签发相同的 access/refresh token
+	api.POST("/auth/:provider", controller.SocialLogin)
+	api.GET("/auth/:provider", controller.BeginOAuth)
+	api.GET("/auth/:provider/callback", controller.OAuthCallback)
+	// 邮箱重复时确认绑定到已有账号：提供密码，或以已有账号登录后提交
+	api.POST("/auth/link/confirm", optionalAuth(), controller.ConfirmAccountLink)
+
 	// 注册auth路由
 	auth := api.Group("/auth")
 	auth.Use(middleware.JWTAuth())
 	{
 		auth.GET("/user/profile", controller.UserProfile)
+		auth.PUT("/password", controller.ChangePassword)
+		auth.GET("/sessions", controller.ListSessions)
+		auth.DELETE("/sessions", controller.RevokeSessions)
+		auth.DELETE("/sessions/:session_id", controller.RevokeSession)
+		auth.POST("/link/:provider", controller.LinkProvider)
+		auth.DELETE("/link/:provider", controller.UnlinkProvider)
 		// 其他需要登录的接口
 	}
 
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchRouter 全文检索路由模块
+type SearchRouter struct{}
+
+// Register 注册全文检索路由
+func (SearchRouter) Register(r *gin.RouterGroup) {
+	search := r.Group("/search")
+	{
+		search.GET("", controller.Search)
+		search.GET("/suggest", controller.SuggestSearch)
+	}
+
+	// 管理员、内容编辑
+	searchAdmin := r.Group("/search", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		searchAdmin.POST("/suggest/rebuild", controller.RebuildSearchSuggestions)
+	}
+}
+
+func init() {
+	Register(SearchRouter{})
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreMemberRouter 商铺成员路由模块
+type StoreMemberRouter struct{}
+
+// Register 注册商铺成员路由（仅管理员）
+func (StoreMemberRouter) Register(r *gin.RouterGroup) {
+	member := r.Group("/store_members", authorize(model.RoleAdmin)...)
+	{
+		member.POST("", controller.CreateStoreMember)
+		member.DELETE(":store_member_id", controller.DeleteStoreMember)
+		member.POST("/list", controller.ListStoreMembers)
+	}
+}
+
+func init() {
+	Register(StoreMemberRouter{})
+}
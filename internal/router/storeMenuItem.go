@@ -0,0 +1,32 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreMenuItemRouter 商铺菜单项路由模块
+type StoreMenuItemRouter struct{}
+
+// Register 注册商铺菜单项路由
+func (StoreMenuItemRouter) Register(r *gin.RouterGroup) {
+	item := r.Group("/menu_items")
+	{
+		item.GET(":menu_item_id", controller.GetStoreMenuItem)
+		item.POST("/list", controller.ListStoreMenuItems)
+	}
+
+	// 管理员、内容编辑
+	itemAdmin := r.Group("/menu_items", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		itemAdmin.POST("", controller.CreateStoreMenuItem)
+		itemAdmin.PUT("", controller.UpdateStoreMenuItem)
+		itemAdmin.DELETE(":menu_item_id", controller.DeleteStoreMenuItem)
+	}
+}
+
+func init() {
+	Register(StoreMenuItemRouter{})
+}
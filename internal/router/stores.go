@@ -2,25 +2,32 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
 
-// StoreRouter Refresh Token 路由模块
+// StoreRouter 商铺路由模块
 type StoreRouter struct{}
 
-// Register 注册 Refresh Token 路由
+// Register 注册商铺路由
 func (StoreRouter) Register(r *gin.RouterGroup) {
-	Store := r.Group("/stores")
+	Store := r.Group("/stores", optionalAuth())
 	{
-		Store.POST("", controller.CreateStore)
-		Store.PUT("", controller.UpdateStore)
-		Store.DELETE(":token_id", controller.DeleteStore)
-		Store.GET(":token_id", controller.GetStore) //
+		Store.GET(":store_id", controller.GetStore)
+		Store.GET(":store_id/hours", controller.GetStoreHours)
+		Store.GET(":store_id/menu", controller.GetStoreMenu)
 		Store.POST("/list", controller.ListStores)
-		// Store.GET(":store_id/tags", controller.GetTagsByStore)
-		// Store.POST(":store_id/tags", controller.AddTagToStore) //
-		// Store.DELETE(":store_id/tags/:tag_id", controller.RemoveTagFromStore)
+		Store.POST("/nearby", controller.NearbyStores)
+	}
+
+	// 管理员、内容编辑
+	storeAdmin := r.Group("/stores", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		storeAdmin.POST("", controller.CreateStore)
+		storeAdmin.PUT("", controller.UpdateStore)
+		storeAdmin.DELETE(":store_id", controller.DeleteStore)
+		storeAdmin.PUT(":store_id/hours", controller.UpdateStoreHours)
 	}
 }
 
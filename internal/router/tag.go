@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,11 +14,31 @@ type TagRouter struct{}
 func (TagRouter) Register(r *gin.RouterGroup) {
 	tags := r.Group("/tags")
 	{
-		tags.POST("", controller.CreateTag)          // 新建标签
-		tags.PUT("", controller.UpdateTag)           // 更新标签
-		tags.DELETE(":tag_id", controller.DeleteTag) // 删除标签
-		tags.GET(":tag_id", controller.GetTag)       // 获取单个标签
-		tags.POST("/list", controller.ListTags)      // 标签分页列表
+		tags.GET(":tag_id", controller.GetTag)            // 获取单个标签
+		tags.GET(":tag_id/usage", controller.GetTagUsage) // 标签使用统计
+		tags.POST("/list", controller.ListTags)           // 标签分页列表
+	}
+
+	// 管理员、内容编辑
+	tagsAdmin := r.Group("/tags", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		tagsAdmin.POST("", controller.CreateTag)                 // 新建标签
+		tagsAdmin.PUT("", controller.UpdateTag)                  // 更新标签
+		tagsAdmin.DELETE(":tag_id", controller.DeleteTag)        // 删除标签
+		tagsAdmin.PUT(":tag_id/active", controller.SetTagActive) // 启用/停用标签
+		tagsAdmin.POST("/merge", controller.MergeTags)           // 合并标签
+	}
+
+	taggings := r.Group("/taggings")
+	{
+		taggings.GET(":taggable_type/:taggable_id", controller.ListEntityTags) // 获取实体的标签
+	}
+
+	// 管理员、内容编辑
+	taggingsAdmin := r.Group("/taggings", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		taggingsAdmin.POST(":taggable_type/:taggable_id", controller.AttachTag)           // 为实体添加标签
+		taggingsAdmin.DELETE(":taggable_type/:taggable_id/:tag_id", controller.DetachTag) // 移除实体的标签
 	}
 }
 
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TranslationRouter 多语言译文路由模块
+type TranslationRouter struct{}
+
+// Register 注册多语言译文路由
+func (TranslationRouter) Register(r *gin.RouterGroup) {
+	translation := r.Group("/translations")
+	{
+		translation.GET(":translation_id", controller.GetTranslation)
+		translation.POST("/list", controller.ListTranslations)
+	}
+
+	// 管理员、内容编辑
+	translationAdmin := r.Group("/translations", authorize(model.RoleAdmin, model.RoleEditor)...)
+	{
+		translationAdmin.POST("", controller.CreateTranslation)
+		translationAdmin.PUT("", controller.UpdateTranslation)
+		translationAdmin.DELETE(":translation_id", controller.DeleteTranslation)
+		translationAdmin.POST("/missing", controller.ListMissingTranslations)
+		translationAdmin.POST("/review/list", controller.ListReviewTranslations)
+		translationAdmin.PUT(":translation_id/approve", controller.ApproveTranslation)
+	}
+}
+
+func init() {
+	Register(TranslationRouter{})
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,11 +10,13 @@ import (
 // UserRouter 用户路由模块
 type UserRouter struct{}
 
+// Register 注册用户路由（仅管理员）
 func (UserRouter) Register(r *gin.RouterGroup) {
-	user := r.Group("/users")
+	user := r.Group("/users", authorize(model.RoleAdmin)...)
 	{
 		user.POST("", controller.CreateUser)
 		user.PUT("", controller.UpdateUser)
+		user.PUT("/role", controller.UpdateUserRole)
 		user.DELETE(":user_id", controller.DeleteUser)
 		user.GET(":user_id", controller.GetUser)
 		user.POST("/list", controller.ListUsers)
@@ -2,6 +2,7 @@ package router
 
 import (
 	"travel-ar-backend/internal/controller"
+	"travel-ar-backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,13 +12,20 @@ type VisitHistoryRouter struct{}
 
 // Register 注册访问记录路由
 func (VisitHistoryRouter) Register(r *gin.RouterGroup) {
-	visitHistory := r.Group("/visit_history")
+	// 任意已登录用户（访问记录通过 /checkin/scan 扫码创建）
+	visitHistory := r.Group("/visit_history", authorize()...)
 	{
-		visitHistory.POST("", controller.CreateVisitHistory)
-		visitHistory.PUT("", controller.UpdateVisitHistory)
-		visitHistory.DELETE(":history_id", controller.DeleteVisitHistory)
-		visitHistory.GET(":history_id", controller.GetVisitHistory)
-		visitHistory.POST("/list", controller.ListVisitHistories)
+		visitHistory.GET("/mine", controller.ListMyVisitHistories)
+	}
+
+	// 管理员（手动补录、修正）
+	visitHistoryAdmin := r.Group("/visit_history", authorize(model.RoleAdmin)...)
+	{
+		visitHistoryAdmin.POST("", controller.CreateVisitHistory)
+		visitHistoryAdmin.PUT("", controller.UpdateVisitHistory)
+		visitHistoryAdmin.DELETE(":history_id", controller.DeleteVisitHistory)
+		visitHistoryAdmin.GET(":history_id", controller.GetVisitHistory)
+		visitHistoryAdmin.POST("/list", controller.ListVisitHistories)
 	}
 }
 
@@ -0,0 +1,122 @@
+// Package search 文章、商铺、设施的统一全文检索
+//
+// 使用 PostgreSQL 全文检索（simple 配置）处理以空格分词的文本，
+// 日文等不分词的文本则依赖 pg_trgm 的包含匹配与相似度兜底。
+package search
+
+import (
+	"strings"
+
+	"travel-ar-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// snippetRadius 摘要中命中位置前后保留的字符数
+const snippetRadius = 40
+
+// Query 检索条件
+type Query struct {
+	Text     string
+	Types    []string // 为空表示全部
+	Category string
+	Limit    int
+	Offset   int
+}
+
+// documentsSQL 各实体的检索文档：标题、分类、正文（含地址等）、标签名
+const documentsSQL = `
+SELECT 'Article' AS entity_type, a.article_id AS entity_id, a.title AS title,
+	COALESCE(a.category, '') AS category, a.body_text AS body,
+	COALESCE((SELECT string_agg(t.tag_name, ' ') FROM taggings tg JOIN tags t ON t.tag_id = tg.tag_id
+		WHERE tg.taggable_type = 'Article' AND tg.taggable_id = a.article_id AND t.is_active), '') AS tags,
+	CAST(NULL AS DOUBLE PRECISION) AS latitude, CAST(NULL AS DOUBLE PRECISION) AS longitude
+FROM articles a
+UNION ALL
+SELECT 'Store', s.store_id, s.store_name, COALESCE(s.store_category, ''),
+	concat_ws(' ', s.description_text, s.address),
+	COALESCE((SELECT string_agg(t.tag_name, ' ') FROM taggings tg JOIN tags t ON t.tag_id = tg.tag_id
+		WHERE tg.taggable_type = 'Store' AND tg.taggable_id = s.store_id AND t.is_active), ''),
+	CAST(s.latitude AS DOUBLE PRECISION), CAST(s.longitude AS DOUBLE PRECISION)
+FROM stores s
+UNION ALL
+SELECT 'Facility', f.facility_id, f.facility_name, COALESCE(f.facility_category, ''),
+	COALESCE(f.description_text, ''),
+	COALESCE((SELECT string_agg(t.tag_name, ' ') FROM taggings tg JOIN tags t ON t.tag_id = tg.tag_id
+		WHERE tg.taggable_type = 'Facility' AND tg.taggable_id = f.facility_id AND t.is_active), ''),
+	CAST(f.latitude AS DOUBLE PRECISION), CAST(f.longitude AS DOUBLE PRECISION)
+FROM facilities f`
+
+// matches 构造命中文档的子查询，附带相关度 score
+// 命中条件：全文检索匹配，或每个关键词都包含在标题、标签、正文之一中
+// 相关度：全文检索得分（标题 > 标签 > 正文）加上三元组词相似度
+func matches(db *gorm.DB, terms []string) *gorm.DB {
+	raw := strings.Join(terms, " ")
+	contains := make([]string, len(terms))
+	args := []interface{}{raw, raw, raw, raw, raw}
+	for i, t := range terms {
+		contains[i] = "(d.title ILIKE ? OR d.tags ILIKE ? OR d.body ILIKE ?)"
+		p := likePattern(t)
+		args = append(args, p, p, p)
+	}
+	return db.Raw(`
+SELECT d.*,
+	ts_rank(setweight(to_tsvector('simple', d.title), 'A') || setweight(to_tsvector('simple', d.tags), 'B')
+		|| setweight(to_tsvector('simple', d.body), 'C'), plainto_tsquery('simple', ?))
+	+ word_similarity(?, d.title) + 0.5 * word_similarity(?, d.tags) + 0.2 * word_similarity(?, d.body) AS score
+FROM (`+documentsSQL+`) d
+WHERE to_tsvector('simple', d.title || ' ' || d.tags || ' ' || d.body) @@ plainto_tsquery('simple', ?)
+	OR (`+strings.Join(contains, " AND ")+`)`, args...)
+}
+
+// Search 执行检索，返回按相关度排序的结果与分面统计
+func Search(db *gorm.DB, q Query) (model.SearchResult, error) {
+	result := model.SearchResult{Hits: []model.SearchHit{}, Types: []model.SearchFacet{}, Categories: []model.SearchFacet{}}
+	terms := Terms(q.Text)
+	if len(terms) == 0 {
+		return result, nil
+	}
+
+	// 分面基于全部命中结果，方便客户端切换筛选条件
+	if err := db.Table("(?) AS m", matches(db, terms)).
+		Select("entity_type, COUNT(*) AS count").
+		Group("entity_type").Order("entity_type").
+		Scan(&result.Types).Error; err != nil {
+		return result, err
+	}
+	if err := db.Table("(?) AS m", matches(db, terms)).
+		Select("entity_type, category, COUNT(*) AS count").
+		Where("category <> ''").
+		Group("entity_type, category").Order("entity_type, count DESC, category").
+		Scan(&result.Categories).Error; err != nil {
+		return result, err
+	}
+
+	hits := db.Table("(?) AS m", matches(db, terms))
+	if len(q.Types) > 0 {
+		hits = hits.Where("entity_type IN ?", q.Types)
+	}
+	if q.Category != "" {
+		hits = hits.Where("category = ?", q.Category)
+	}
+	if err := hits.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+
+	var rows []struct {
+		model.SearchHit
+		Body string
+	}
+	if err := hits.Select("entity_type, entity_id, title, category, body, score, latitude, longitude").
+		Order("score DESC, entity_type, entity_id").
+		Limit(q.Limit).Offset(q.Offset).
+		Scan(&rows).Error; err != nil {
+		return result, err
+	}
+	for _, r := range rows {
+		hit := r.SearchHit
+		hit.Snippet = Snippet(r.Body, terms, snippetRadius)
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
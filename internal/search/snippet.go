@@ -0,0 +1,100 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+)
+
+// maxTerms 参与检索的关键词上限
+const maxTerms = 8
+
+// Terms 按空白（含全角空格）拆分关键词，去重并忽略大小写
+func Terms(q string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, f := range strings.Fields(q) {
+		key := strings.ToLower(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, f)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// Snippet 截取 text 中第一个关键词命中处前后各 radius 个字符，命中的关键词以 <mark></mark> 包裹
+// 返回值中其余内容已做 HTML 转义；没有命中时返回开头 2*radius 个字符
+func Snippet(text string, terms []string, radius int) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	// ToLower 改变字符数时（极少数字符）无法按下标对应，退化为不标注
+	if len(lower) != len(runes) {
+		lower = runes
+		terms = nil
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, t := range terms {
+		needle := []rune(strings.ToLower(t))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(needle)], needle) {
+				spans = append(spans, span{i, i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	from, to := 0, min(len(runes), 2*radius)
+	if len(spans) > 0 {
+		from = max(spans[0].start-radius, 0)
+		to = min(spans[0].end+radius, len(runes))
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.start < pos || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString("</mark>")
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// likeEscaper 转义 LIKE 通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern 转义 LIKE 通配符后包裹为包含匹配
+func likePattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
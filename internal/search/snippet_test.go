@@ -0,0 +1,47 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	got := Terms("  浅草　Temple temple 寺 ")
+	want := []string{"浅草", "Temple", "寺"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Terms = %q, want %q", got, want)
+	}
+	if len(Terms(strings.Repeat("a b c d e f g h i j ", 2))) != maxTerms {
+		t.Errorf("expected terms capped at %d", maxTerms)
+	}
+}
+
+func TestSnippetJapanese(t *testing.T) {
+	text := "東京都台東区にある浅草寺は、都内最古の寺院です。雷門が有名です。"
+	got := Snippet(text, []string{"浅草寺", "雷門"}, 5)
+	want := "…東区にある<mark>浅草寺</mark>は、都内最…"
+	if got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+}
+
+func TestSnippetCaseAndEscape(t *testing.T) {
+	got := Snippet("Fish & Chips <b>SHOP</b>", []string{"shop", "fish"}, 40)
+	want := "<mark>Fish</mark> &amp; Chips &lt;b&gt;<mark>SHOP</mark>&lt;/b&gt;"
+	if got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+}
+
+func TestSnippetNoMatch(t *testing.T) {
+	got := Snippet("abcdefghij", []string{"xyz"}, 2)
+	if got != "abcd…" {
+		t.Errorf("Snippet = %q", got)
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	if got := likePattern(`50%_off\`); got != `%50\%\_off\\%` {
+		t.Errorf("likePattern = %q", got)
+	}
+}
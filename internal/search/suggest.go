@@ -0,0 +1,123 @@
+package search
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/textnorm"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxKeyRunes 检索键最大长度，与 search_suggestions 的列宽一致
+const maxKeyRunes = 255
+
+// suggestionSourceSQL 各实体的联想候选：显示文本、读音与热度
+// 热度：商铺为公开评价数，设施为有效访问数，标签为使用次数，文章为点赞数与评论数，收藏按 2 倍计入
+const suggestionSourceSQL = `
+SELECT 'Store' AS entity_type, s.store_id AS entity_id, s.store_name AS label,
+	COALESCE(s.store_name_kana, '') AS reading,
+	s.review_count + 2 * (SELECT COUNT(*) FROM favorites fv
+		WHERE fv.favoritable_type = 'Store' AND fv.favoritable_id = s.store_id) AS popularity
+FROM stores s
+UNION ALL
+SELECT 'Facility', f.facility_id, f.facility_name, COALESCE(f.facility_name_kana, ''),
+	(SELECT COUNT(*) FROM visit_history v WHERE v.facility_id = f.facility_id AND v.is_active)
+	+ 2 * (SELECT COUNT(*) FROM favorites fv
+		WHERE fv.favoritable_type = 'Facility' AND fv.favoritable_id = f.facility_id)
+FROM facilities f
+UNION ALL
+SELECT 'Tag', t.tag_id, t.tag_name, '',
+	(SELECT COUNT(*) FROM taggings tg WHERE tg.tag_id = t.tag_id)
+FROM tags t
+WHERE t.is_active
+UNION ALL
+SELECT 'Article', a.article_id, a.title, '',
+	a.like_count + a.comment_count + 2 * (SELECT COUNT(*) FROM favorites fv
+		WHERE fv.favoritable_type = 'Article' AND fv.favoritable_id = a.article_id)
+FROM articles a`
+
+// SuggestQuery 联想条件
+type SuggestQuery struct {
+	Text  string
+	Types []string // 为空表示全部
+	Limit int
+}
+
+// Suggest 按检索键前缀匹配显示文本或读音，完全一致的排在最前，其余按热度排序
+func Suggest(db *gorm.DB, q SuggestQuery) ([]model.SearchSuggestion, error) {
+	list := []model.SearchSuggestion{}
+	key := textnorm.Fold(q.Text)
+	if key == "" {
+		return list, nil
+	}
+	prefix := likeEscaper.Replace(key) + "%"
+	query := db.Model(&model.SearchSuggestion{}).
+		Where("normalized LIKE ? OR reading LIKE ?", prefix, prefix)
+	if len(q.Types) > 0 {
+		query = query.Where("entity_type IN ?", q.Types)
+	}
+	err := query.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "(normalized = ? OR reading = ?) DESC", Vars: []interface{}{key, key}}}).
+		Order("popularity DESC").Order("char_length(label)").Order("label").
+		Limit(q.Limit).Find(&list).Error
+	return list, err
+}
+
+// RebuildSuggestions 由各实体重新生成全部联想候选，返回候选数
+// 在同一事务内清空并写入，重建期间读取方仍看到旧数据
+func RebuildSuggestions(db *gorm.DB) (int, error) {
+	var rows []model.SearchSuggestion
+	if err := db.Raw(suggestionSourceSQL).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	list := rows[:0]
+	for _, r := range rows {
+		r.Normalized = truncate(textnorm.Fold(r.Label))
+		r.Reading = truncate(textnorm.Fold(r.Reading))
+		if r.Normalized == "" {
+			continue
+		}
+		r.UpdatedAt = now
+		list = append(list, r)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.SearchSuggestion{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(list, 500).Error
+	})
+	return len(list), err
+}
+
+// RunSuggestionJob 每隔 interval 重建一次联想候选，直到 ctx 结束
+func RunSuggestionJob(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := RebuildSuggestions(db.WithContext(ctx)); err != nil && ctx.Err() == nil {
+			log.Printf("suggestion job: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// truncate 截断到 maxKeyRunes 个字符（NFKC 可能使文本变长）
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxKeyRunes {
+		return s
+	}
+	return string(r[:maxKeyRunes])
+}
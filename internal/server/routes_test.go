@@ -29,3 +29,29 @@ func TestHandler(t *testing.T) {
 		t.Errorf("expected response body to be %v; got %v", expected, string(body))
 	}
 }
+
+func TestAllowedOrigins(t *testing.T) {
+	cases := []struct {
+		cors, redirect string
+		want           []string
+	}{
+		{"", "", []string{"http://localhost:5173"}},
+		{"", "https://app.example.com/auth/done?x=1", []string{"https://app.example.com"}},
+		{"https://a.example.com/, http://localhost:5173", "https://app.example.com", []string{"https://a.example.com", "http://localhost:5173"}},
+	}
+	for _, tc := range cases {
+		t.Setenv("CORS_ALLOWED_ORIGINS", tc.cors)
+		t.Setenv("OAUTH_REDIRECT_URL", tc.redirect)
+		got := allowedOrigins()
+		if len(got) != len(tc.want) {
+			t.Errorf("allowedOrigins(%q, %q) = %v, want %v", tc.cors, tc.redirect, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("allowedOrigins(%q, %q) = %v, want %v", tc.cors, tc.redirect, got, tc.want)
+				break
+			}
+		}
+	}
+}
@@ -8,11 +8,9 @@ import (
 	"time"
 
 	"travel-ar-backend/internal/database"
-	"travel-ar-backend/internal/router"
+	pkgdb "travel-ar-backend/pkg/database"
 
 	_ "github.com/joho/godotenv/autoload"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 type Server struct {
@@ -22,23 +20,22 @@ type Server struct {
 }
 
 func NewServer() *http.Server {
-
-	// 3. 初始化 Gin 路由
-	r := router.InitRouter()
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// 4. 端口
+	// 端口
 	port := 8080
 	if p := os.Getenv("SERVER_PORT"); p != "" {
 		if v, err := strconv.Atoi(p); err == nil {
 			port = v
 		}
 	}
+	s := &Server{
+		port: port,
+		db:   database.Wrap(pkgdb.GetDB()),
+	}
 
 	// Declare Server config
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      r,
+		Addr:         fmt.Sprintf(":%d", s.port),
+		Handler:      s.RegisterRoutes(),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
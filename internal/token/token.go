@@ -0,0 +1,259 @@
+// Package token 统一的令牌服务
+//
+// 签发与校验 access token、refresh token，并支持两种传递方式：
+// 移动端使用 Authorization: Bearer 请求头，Web 端使用 HttpOnly Cookie。
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AccessTTL  = 15 * time.Minute   // access token 有效期
+	RefreshTTL = 7 * 24 * time.Hour // refresh token 有效期，每次刷新重新计算
+
+	AccessCookie  = "access_token"  // Web 端保存 access token 的 Cookie
+	RefreshCookie = "refresh_token" // Web 端保存 refresh token 的 Cookie，仅发送到 /api
+	LinkCookie    = "link_token"    // Web 端第三方登录待绑定时的确认凭证，仅发送到 /api/auth/link
+)
+
+// ErrInvalid 令牌格式、签名或有效期不正确
+var ErrInvalid = errors.New("token: invalid token")
+
+// ErrNoSecret 未配置签名密钥
+var ErrNoSecret = errors.New("token: JWT_SECRET and JWT_REFRESH_SECRET must be set (or JWT_DEV_INSECURE=true for local development)")
+
+// Claims access token 载荷
+type Claims struct {
+	UserID    int    `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"` // 登录会话ID（refresh token family）
+	jwt.RegisteredClaims
+}
+
+// RefreshClaims refresh token 载荷，ID 保证同一秒内签发的令牌互不相同
+type RefreshClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// Service 令牌签发与校验，access 与 refresh 使用不同密钥，互相不能混用
+type Service struct {
+	accessSecret  []byte
+	refreshSecret []byte
+	secureCookie  bool
+	now           func() time.Time
+}
+
+// New 创建令牌服务；secureCookie 为 true 时 Cookie 仅通过 HTTPS 发送
+func New(accessSecret, refreshSecret string, secureCookie bool) *Service {
+	return &Service{
+		accessSecret:  []byte(accessSecret),
+		refreshSecret: []byte(refreshSecret),
+		secureCookie:  secureCookie,
+		now:           time.Now,
+	}
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultService *Service
+)
+
+// Default 返回全局令牌服务，未通过 SetDefault 设置时按环境变量创建，配置缺失则终止进程
+func Default() *Service {
+	defaultOnce.Do(func() {
+		if defaultService == nil {
+			s, err := FromEnv()
+			if err != nil {
+				log.Fatal(err)
+			}
+			defaultService = s
+		}
+	})
+	return defaultService
+}
+
+// SetDefault 替换全局令牌服务，启动时或测试中使用
+func SetDefault(s *Service) {
+	defaultOnce.Do(func() {})
+	defaultService = s
+}
+
+// FromEnv 根据环境变量创建令牌服务
+// JWT_SECRET、JWT_REFRESH_SECRET 为签名密钥，缺失时返回 ErrNoSecret；
+// 仅当 JWT_DEV_INSECURE=true 时改用进程内随机密钥（重启后已签发的令牌全部失效），用于本地开发。
+// COOKIE_SECURE=true 时 Cookie 加 Secure 属性
+func FromEnv() (*Service, error) {
+	access := os.Getenv("JWT_SECRET")
+	refresh := os.Getenv("JWT_REFRESH_SECRET")
+	if access == "" || refresh == "" {
+		if os.Getenv("JWT_DEV_INSECURE") != "true" {
+			return nil, ErrNoSecret
+		}
+		log.Print("token: JWT_SECRET/JWT_REFRESH_SECRET not set, using random secrets for development")
+		if access == "" {
+			access = randomSecret()
+		}
+		if refresh == "" {
+			refresh = randomSecret()
+		}
+	}
+	return New(access, refresh, os.Getenv("COOKIE_SECURE") == "true"), nil
+}
+
+// randomSecret 生成 32 字节随机密钥
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// IssueAccess 签发 access token
+func (s *Service) IssueAccess(userID int, role, sessionID string) (string, error) {
+	claims := &Claims{
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(s.now()),
+			ExpiresAt: jwt.NewNumericDate(s.now().Add(AccessTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.accessSecret)
+}
+
+// ParseAccess 校验 access token 并返回载荷
+func (s *Service) ParseAccess(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+	if err := s.parse(tokenStr, claims, s.accessSecret); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// IssueRefresh 签发在 expiresAt 过期的 refresh token，jti 为随机值
+func (s *Service) IssueRefresh(userID int, jti string, expiresAt time.Time) (string, error) {
+	claims := &RefreshClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(s.now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.refreshSecret)
+}
+
+// ParseRefresh 校验 refresh token 的签名与有效期；是否已作废需另行查库
+func (s *Service) ParseRefresh(tokenStr string) (*RefreshClaims, error) {
+	claims := &RefreshClaims{}
+	if err := s.parse(tokenStr, claims, s.refreshSecret); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// parse 校验签名后按 s.now 校验有效期（不使用 jwt 包的全局时钟）
+func (s *Service) parse(tokenStr string, claims expiring, secret []byte) error {
+	if tokenStr == "" {
+		return ErrInvalid
+	}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	token, err := parser.ParseWithClaims(tokenStr, claims, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	})
+	if err != nil || !token.Valid || !claims.VerifyExpiresAt(s.now(), true) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// expiring 带有效期的载荷
+type expiring interface {
+	jwt.Claims
+	VerifyExpiresAt(cmp time.Time, req bool) bool
+}
+
+// FromRequest 取出请求携带的 access token：优先 Authorization 请求头，其次 Cookie
+func FromRequest(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if t, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(t)
+		}
+		return ""
+	}
+	if c, err := r.Cookie(AccessCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// RefreshFromRequest 取出 Cookie 中的 refresh token，未携带时返回空字符串
+func RefreshFromRequest(r *http.Request) string {
+	if c, err := r.Cookie(RefreshCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// LinkFromRequest 取出 Cookie 中的绑定确认凭证，未携带时返回空字符串
+func LinkFromRequest(r *http.Request) string {
+	if c, err := r.Cookie(LinkCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// SetLinkCookie 将绑定确认凭证写入 HttpOnly Cookie，避免出现在跳转地址中
+func (s *Service) SetLinkCookie(w http.ResponseWriter, linkToken string, expires time.Time) {
+	http.SetCookie(w, s.cookie(LinkCookie, linkToken, "/api/auth/link", expires))
+}
+
+// ClearLinkCookie 删除绑定确认凭证 Cookie
+func (s *Service) ClearLinkCookie(w http.ResponseWriter) {
+	c := s.cookie(LinkCookie, "", "/api/auth/link", time.Unix(0, 0))
+	c.MaxAge = -1
+	http.SetCookie(w, c)
+}
+
+// SetCookies 将令牌写入 HttpOnly Cookie，供 Web 端使用
+func (s *Service) SetCookies(w http.ResponseWriter, access, refresh string, refreshExpires time.Time) {
+	http.SetCookie(w, s.cookie(AccessCookie, access, "/", s.now().Add(AccessTTL)))
+	http.SetCookie(w, s.cookie(RefreshCookie, refresh, "/api", refreshExpires))
+}
+
+// ClearCookies 删除令牌 Cookie（登出）
+func (s *Service) ClearCookies(w http.ResponseWriter) {
+	for _, c := range []*http.Cookie{
+		s.cookie(AccessCookie, "", "/", time.Unix(0, 0)),
+		s.cookie(RefreshCookie, "", "/api", time.Unix(0, 0)),
+	} {
+		c.MaxAge = -1
+		http.SetCookie(w, c)
+	}
+}
+
+func (s *Service) cookie(name, value, path string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   s.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
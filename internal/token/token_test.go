@@ -0,0 +1,187 @@
+package token
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestService(now time.Time) *Service {
+	s := New("access-secret", "refresh-secret", true)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestAccessRoundTrip(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+	tok, err := s.IssueAccess(42, "editor", "sess1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.ParseAccess(tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != 42 || claims.Role != "editor" || claims.SessionID != "sess1" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	s.now = func() time.Time { return now.Add(AccessTTL + time.Second) }
+	if _, err := s.ParseAccess(tok); err != ErrInvalid {
+		t.Errorf("expired token: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestSecretsAreSeparate(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+	access, _ := s.IssueAccess(1, "tourist", "")
+	refresh, _ := s.IssueRefresh(1, "jti", now.Add(RefreshTTL))
+
+	if _, err := s.ParseAccess(refresh); err == nil {
+		t.Error("refresh token accepted as access token")
+	}
+	if _, err := s.ParseRefresh(access); err == nil {
+		t.Error("access token accepted as refresh token")
+	}
+	claims, err := s.ParseRefresh(refresh)
+	if err != nil || claims.UserID != 1 || claims.ID != "jti" {
+		t.Errorf("ParseRefresh = %+v, %v", claims, err)
+	}
+
+	other := New("other", "other-refresh", false)
+	if _, err := other.ParseAccess(access); err == nil {
+		t.Error("token signed with another secret accepted")
+	}
+	if _, err := s.ParseAccess(""); err != ErrInvalid {
+		t.Errorf("empty token: err = %v", err)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	cases := []struct {
+		header string
+		cookie string
+		want   string
+	}{
+		{"Bearer abc", "", "abc"},
+		{"Bearer abc", "fromcookie", "abc"},
+		{"", "fromcookie", "fromcookie"},
+		{"Basic xyz", "fromcookie", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		if c.header != "" {
+			r.Header.Set("Authorization", c.header)
+		}
+		if c.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: AccessCookie, Value: c.cookie})
+		}
+		if got := FromRequest(r); got != c.want {
+			t.Errorf("FromRequest(%q, %q) = %q, want %q", c.header, c.cookie, got, c.want)
+		}
+	}
+}
+
+func TestCookies(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+	w := httptest.NewRecorder()
+	s.SetCookies(w, "a", "r", now.Add(RefreshTTL))
+	cookies := map[string]*http.Cookie{}
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	access, refresh := cookies[AccessCookie], cookies[RefreshCookie]
+	if access == nil || access.Value != "a" || access.Path != "/" || !access.HttpOnly || !access.Secure {
+		t.Errorf("access cookie = %+v", access)
+	}
+	if refresh == nil || refresh.Value != "r" || refresh.Path != "/api" || !refresh.HttpOnly {
+		t.Errorf("refresh cookie = %+v", refresh)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+	r.AddCookie(refresh)
+	if got := RefreshFromRequest(r); got != "r" {
+		t.Errorf("RefreshFromRequest = %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	s.ClearCookies(w)
+	for _, c := range w.Result().Cookies() {
+		if c.Value != "" || c.MaxAge >= 0 {
+			t.Errorf("cleared cookie = %+v", c)
+		}
+	}
+}
+
+func TestLinkCookie(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	s := newTestService(now)
+	w := httptest.NewRecorder()
+	s.SetLinkCookie(w, "link", now.Add(10*time.Minute))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %+v", cookies)
+	}
+	link := cookies[0]
+	if link.Name != LinkCookie || link.Value != "link" || link.Path != "/api/auth/link" || !link.HttpOnly || !link.Secure {
+		t.Errorf("link cookie = %+v", link)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/auth/link/confirm", nil)
+	r.AddCookie(link)
+	if got := LinkFromRequest(r); got != "link" {
+		t.Errorf("LinkFromRequest = %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	s.ClearLinkCookie(w)
+	if c := w.Result().Cookies()[0]; c.Value != "" || c.MaxAge >= 0 {
+		t.Errorf("cleared link cookie = %+v", c)
+	}
+}
+
+func TestFromEnvRequiresSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_REFRESH_SECRET", "")
+	t.Setenv("JWT_DEV_INSECURE", "")
+	if _, err := FromEnv(); err != ErrNoSecret {
+		t.Fatalf("err = %v, want ErrNoSecret", err)
+	}
+
+	t.Setenv("JWT_SECRET", "access-secret")
+	if _, err := FromEnv(); err != ErrNoSecret {
+		t.Fatalf("missing refresh secret: err = %v, want ErrNoSecret", err)
+	}
+
+	t.Setenv("JWT_REFRESH_SECRET", "refresh-secret")
+	if _, err := FromEnv(); err != nil {
+		t.Fatalf("configured secrets: err = %v", err)
+	}
+}
+
+func TestFromEnvDevInsecure(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_REFRESH_SECRET", "")
+	t.Setenv("JWT_DEV_INSECURE", "true")
+	a, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := FromEnv()
+	tok, err := a.IssueAccess(1, "tourist", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ParseAccess(tok); err != nil {
+		t.Fatalf("ParseAccess with same service: %v", err)
+	}
+	// 随机密钥，不同进程（服务实例）之间不能互认
+	if _, err := b.ParseAccess(tok); err != ErrInvalid {
+		t.Fatalf("ParseAccess with other random secret: err = %v, want ErrInvalid", err)
+	}
+}
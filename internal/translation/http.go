@@ -0,0 +1,73 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPTranslator 调用兼容 LibreTranslate 的 HTTP 翻译服务（POST {url}，请求体 q/source/target）
+type HTTPTranslator struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+// NewHTTPTranslator 创建 HTTP 翻译实现
+func NewHTTPTranslator(url, apiKey string, timeout time.Duration) *HTTPTranslator {
+	return &HTTPTranslator{
+		URL:    url,
+		APIKey: apiKey,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+type httpRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type httpResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+// Translate 发送翻译请求
+func (t *HTTPTranslator) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+	body, err := json.Marshal(httpRequest{
+		Q:      text,
+		Source: sourceLang,
+		Target: targetLang,
+		Format: "text",
+		APIKey: t.APIKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out httpResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("translator: decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("translator: status %d: %s", resp.StatusCode, out.Error)
+	}
+	return out.TranslatedText, nil
+}
@@ -0,0 +1,151 @@
+package translation
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/i18n"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobEntityTypes 后台任务自动补全译文的实体类型
+var JobEntityTypes = []string{model.EntityFacility, model.EntityStore, model.EntityArticle}
+
+const (
+	retryBaseDelay = 10 * time.Minute // 首次失败后的重试间隔
+	retryMaxDelay  = 24 * time.Hour   // 重试间隔上限
+)
+
+// RetryDelay 返回连续失败 attempts 次后到下次重试的间隔，从 10 分钟起逐次翻倍，最长 24 小时
+func RetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// pendingTranslation 待翻译的目标及其连续失败次数
+type pendingTranslation struct {
+	model.MissingTranslation
+	Attempts int `gorm:"column:attempts"`
+}
+
+// TranslateMissing 取最多 limit 条缺失译文进行机器翻译，结果标记为 machine、待审核
+// 单条翻译失败只记录日志并登记到 translation_failures，退避期内不再选取；失败次数少的目标优先，
+// 持续失败的目标不会挤占其他目标。返回成功写入的条数
+func TranslateMissing(ctx context.Context, db *gorm.DB, tr Translator, limit int) (int, error) {
+	now := time.Now()
+	var missing []pendingTranslation
+	if err := MissingQuery(db, JobEntityTypes, "").
+		Select("m.*, COALESCE(f.attempts, 0) AS attempts").
+		Joins("LEFT JOIN translation_failures f ON f.translatable_type = m.translatable_type AND f.translatable_id = m.translatable_id "+
+			"AND f.field_name = m.field_name AND f.language_code = m.language_code").
+		Where("f.next_attempt_at IS NULL OR f.next_attempt_at <= ?", now).
+		Order("attempts").Order("m.translatable_type").Order("m.translatable_id").Order("m.field_name").Order("m.language_code").
+		Limit(limit).Scan(&missing).Error; err != nil {
+		return 0, err
+	}
+
+	// 按实体字段批量读取原文
+	type key struct{ entityType, field string }
+	ids := map[key][]int{}
+	for _, m := range missing {
+		k := key{m.TranslatableType, m.FieldName}
+		ids[k] = append(ids[k], m.TranslatableID)
+	}
+	texts := map[key]map[int]string{}
+	for k, v := range ids {
+		t, err := SourceTexts(db, k.entityType, k.field, v)
+		if err != nil {
+			return 0, err
+		}
+		texts[k] = t
+	}
+
+	done := 0
+	for _, m := range missing {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		source := texts[key{m.TranslatableType, m.FieldName}][m.TranslatableID]
+		content, err := tr.Translate(ctx, source, i18n.DefaultLanguage, m.LanguageCode)
+		if err != nil {
+			if ctx.Err() != nil {
+				return done, ctx.Err()
+			}
+			log.Printf("translation: %s#%d.%s -> %s: %v", m.TranslatableType, m.TranslatableID, m.FieldName, m.LanguageCode, err)
+			if err := recordFailure(db, m, err, time.Now()); err != nil {
+				return done, err
+			}
+			continue
+		}
+		// 翻译期间编辑可能已人工录入，冲突时保留已有译文
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Translation{
+			TranslatableType: m.TranslatableType,
+			TranslatableID:   m.TranslatableID,
+			FieldName:        m.FieldName,
+			LanguageCode:     m.LanguageCode,
+			Content:          content,
+			Source:           model.TranslationSourceMachine,
+			IsApproved:       false,
+		}).Error; err != nil {
+			return done, err
+		}
+		if m.Attempts > 0 {
+			if err := db.Where("translatable_type = ? AND translatable_id = ? AND field_name = ? AND language_code = ?",
+				m.TranslatableType, m.TranslatableID, m.FieldName, m.LanguageCode).
+				Delete(&model.TranslationFailure{}).Error; err != nil {
+				return done, err
+			}
+		}
+		done++
+	}
+	return done, nil
+}
+
+// recordFailure 登记一次翻译失败，按连续失败次数推迟下次重试
+func recordFailure(db *gorm.DB, m pendingTranslation, cause error, now time.Time) error {
+	attempts := m.Attempts + 1
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "translatable_type"}, {Name: "translatable_id"}, {Name: "field_name"}, {Name: "language_code"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"attempts", "last_error", "next_attempt_at", "updated_at"}),
+	}).Create(&model.TranslationFailure{
+		TranslatableType: m.TranslatableType,
+		TranslatableID:   m.TranslatableID,
+		FieldName:        m.FieldName,
+		LanguageCode:     m.LanguageCode,
+		Attempts:         attempts,
+		LastError:        cause.Error(),
+		NextAttemptAt:    now.Add(RetryDelay(attempts)),
+		UpdatedAt:        now,
+	}).Error
+}
+
+// RunJob 每隔 interval 执行一次 TranslateMissing，直到 ctx 结束
+func RunJob(ctx context.Context, db *gorm.DB, tr Translator, interval time.Duration, limit int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := TranslateMissing(ctx, db, tr, limit)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("translation job: %v", err)
+		} else if n > 0 {
+			log.Printf("translation job: %d machine translations created", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"travel-ar-backend/internal/dbtest"
+	"travel-ar-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Minute},
+		{2, 20 * time.Minute},
+		{4, 80 * time.Minute},
+		{8, 1280 * time.Minute},
+		{9, 24 * time.Hour},
+		{100, 24 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := RetryDelay(tc.attempts); got != tc.want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+// translatorFunc 以函数实现 Translator
+type translatorFunc func(text string) (string, error)
+
+func (f translatorFunc) Translate(_ context.Context, text, _, _ string) (string, error) {
+	return f(text)
+}
+
+func TestTranslateMissingBacksOffFailures(t *testing.T) {
+	db := dbtest.Open(t)
+	if err := db.AutoMigrate(&model.Facility{}, &model.Store{}, &model.Article{}, &model.Language{},
+		&model.Translation{}, &model.TranslationFailure{}); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now()
+	lang := model.Language{LanguageName: "Test", LanguageCode: "x-backoff", IsActive: true}
+	if err := db.Create(&lang).Error; err != nil {
+		t.Fatal(err)
+	}
+	name := fmt.Sprintf("backoff-%d", started.UnixNano())
+	facility := model.Facility{FacilityName: name, Location: "浅草", Latitude: 35.7148, Longitude: 139.7967}
+	if err := db.Create(&facility).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Where("translatable_type = ? AND translatable_id = ?", model.EntityFacility, facility.FacilityID).Delete(&model.Translation{})
+		db.Where("updated_at >= ?", started).Delete(&model.TranslationFailure{})
+		db.Delete(&facility)
+		db.Delete(&lang)
+	})
+	target := func() *gorm.DB {
+		return db.Where("translatable_type = ? AND translatable_id = ? AND field_name = ? AND language_code = ?",
+			model.EntityFacility, facility.FacilityID, "facility_name", lang.LanguageCode)
+	}
+
+	var tried bool
+	failing := translatorFunc(func(text string) (string, error) {
+		tried = tried || text == name
+		return "", errors.New("service unavailable")
+	})
+	if _, err := TranslateMissing(context.Background(), db, failing, 1000); err != nil {
+		t.Fatal(err)
+	}
+	var failure model.TranslationFailure
+	if err := target().First(&failure).Error; err != nil {
+		t.Fatalf("failure not recorded: %v", err)
+	}
+	if !tried || failure.Attempts != 1 || failure.NextAttemptAt.Before(started.Add(RetryDelay(1))) {
+		t.Fatalf("tried = %v, failure = %+v", tried, failure)
+	}
+
+	// 退避期内不再选取
+	tried = false
+	if _, err := TranslateMissing(context.Background(), db, failing, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if tried {
+		t.Fatal("failed target retried before its backoff elapsed")
+	}
+
+	// 退避结束后重试，成功时清除失败记录
+	if err := db.Model(&failure).Update("next_attempt_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	ok := translatorFunc(func(text string) (string, error) {
+		if text != name {
+			return "", errors.New("service unavailable")
+		}
+		return "[x] " + text, nil
+	})
+	if _, err := TranslateMissing(context.Background(), db, ok, 1000); err != nil {
+		t.Fatal(err)
+	}
+	var left int64
+	target().Model(&model.TranslationFailure{}).Count(&left)
+	if left != 0 {
+		t.Fatalf("failure record kept after success")
+	}
+}
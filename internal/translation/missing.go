@@ -0,0 +1,82 @@
+package translation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"travel-ar-backend/internal/model"
+	"travel-ar-backend/pkg/i18n"
+
+	"gorm.io/gorm"
+)
+
+// MissingQuery 构造缺失译文查询：原文非空的可翻译字段 × 启用的非原文语言，排除已有译文
+// 结果列与 model.MissingTranslation 对应；types 为空时查询全部可翻译实体，lang 为空时不按语言筛选
+func MissingQuery(db *gorm.DB, types []string, lang string) *gorm.DB {
+	if len(types) == 0 {
+		for t := range model.TranslatableEntities {
+			types = append(types, t)
+		}
+	}
+	types = append([]string(nil), types...)
+	sort.Strings(types)
+
+	var parts []string
+	var args []interface{}
+	for _, t := range types {
+		entity, ok := model.TranslatableEntities[t]
+		if !ok {
+			continue
+		}
+		for _, field := range entity.Fields {
+			// 表名与列名均来自 TranslatableEntities 常量
+			parts = append(parts, fmt.Sprintf(
+				"SELECT CAST(? AS VARCHAR) AS translatable_type, e.%[2]s AS translatable_id, CAST(? AS VARCHAR) AS field_name, l.language_code "+
+					"FROM %[1]s e JOIN languages l ON l.is_active AND l.language_code <> ? "+
+					"WHERE COALESCE(e.%[3]s, '') <> '' AND NOT EXISTS ("+
+					"SELECT 1 FROM translations t WHERE t.translatable_type = ? AND t.translatable_id = e.%[2]s "+
+					"AND t.field_name = ? AND t.language_code = l.language_code)",
+				entity.Table, entity.PrimaryKey, field))
+			args = append(args, t, field, i18n.DefaultLanguage, t, field)
+		}
+	}
+
+	query := db.Table("(?) AS m", db.Raw(strings.Join(parts, " UNION ALL "), args...))
+	if lang != "" {
+		query = query.Where("language_code = ?", lang)
+	}
+	return query
+}
+
+// SourceTexts 查询实体字段的原文，返回 ID → 原文
+func SourceTexts(db *gorm.DB, entityType, field string, ids []int) (map[int]string, error) {
+	entity, ok := model.TranslatableEntities[entityType]
+	if !ok || !containsString(entity.Fields, field) {
+		return nil, fmt.Errorf("translation: unsupported field %s.%s", entityType, field)
+	}
+	var rows []struct {
+		ID   int
+		Text string
+	}
+	if err := db.Table(entity.Table).
+		Select(entity.PrimaryKey+" AS id, "+field+" AS text").
+		Where(entity.PrimaryKey+" IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	texts := make(map[int]string, len(rows))
+	for _, r := range rows {
+		texts[r.ID] = r.Text
+	}
+	return texts, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
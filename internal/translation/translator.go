@@ -0,0 +1,52 @@
+// Package translation 提供机器翻译接口及补全缺失译文的后台任务
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrNotFound 词典中没有对应译文
+var ErrNotFound = errors.New("translation not found")
+
+// Translator 机器翻译接口，sourceLang、targetLang 为 BCP 47 语言标签
+type Translator interface {
+	Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error)
+}
+
+// Stub 本地桩实现，返回带目标语言前缀的原文，便于开发环境确认流程
+type Stub struct{}
+
+// Translate 返回 "[targetLang] text"
+func (Stub) Translate(_ context.Context, text, _, targetLang string) (string, error) {
+	return fmt.Sprintf("[%s] %s", targetLang, text), nil
+}
+
+// Dictionary 基于词典的实现，用于测试
+// Entries 的键为目标语言，值为原文到译文的映射
+type Dictionary struct {
+	Entries map[string]map[string]string
+}
+
+// Translate 查词典，未收录时返回 ErrNotFound
+func (d Dictionary) Translate(_ context.Context, text, _, targetLang string) (string, error) {
+	if t, ok := d.Entries[targetLang][text]; ok {
+		return t, nil
+	}
+	return "", ErrNotFound
+}
+
+// FromEnv 根据环境变量创建翻译实现
+// TRANSLATOR=stub 使用桩实现；设置 TRANSLATOR_URL 时使用 HTTP 实现；否则返回 nil（不启用机器翻译）
+func FromEnv() Translator {
+	if os.Getenv("TRANSLATOR") == "stub" {
+		return Stub{}
+	}
+	if url := os.Getenv("TRANSLATOR_URL"); url != "" {
+		return NewHTTPTranslator(url, os.Getenv("TRANSLATOR_API_KEY"), 30*time.Second)
+	}
+	return nil
+}
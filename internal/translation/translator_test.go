@@ -0,0 +1,49 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDictionary(t *testing.T) {
+	d := Dictionary{Entries: map[string]map[string]string{
+		"en": {"浅草寺": "Senso-ji Temple"},
+	}}
+	got, err := d.Translate(context.Background(), "浅草寺", "ja", "en")
+	if err != nil || got != "Senso-ji Temple" {
+		t.Fatalf("Translate = %q, %v", got, err)
+	}
+	if _, err := d.Translate(context.Background(), "浅草寺", "ja", "fr"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing entry err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestHTTPTranslator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req httpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Source != "ja" || req.Target != "zh-TW" || req.APIKey != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(httpResponse{Error: "bad request"})
+			return
+		}
+		json.NewEncoder(w).Encode(httpResponse{TranslatedText: "淺草寺"})
+	}))
+	defer srv.Close()
+
+	tr := NewHTTPTranslator(srv.URL, "secret", time.Second)
+	got, err := tr.Translate(context.Background(), "浅草寺", "ja", "zh-TW")
+	if err != nil || got != "淺草寺" {
+		t.Fatalf("Translate = %q, %v", got, err)
+	}
+	if _, err := tr.Translate(context.Background(), "浅草寺", "ja", "en"); err == nil {
+		t.Fatal("expected error for non-200 response")
+	}
+}
@@ -43,6 +43,13 @@ func GetDB() *gorm.DB {
 
 func AutoMigrate() {
 	db := GetDB()
+	// 全文检索的三元组匹配依赖 pg_trgm
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	// refresh_tokens 改为只保存哈希：旧表中的明文令牌全部作废，用户需重新登录
+	if db.Migrator().HasColumn(&model.RefreshToken{}, "refresh_token") {
+		db.Exec("DELETE FROM refresh_tokens")
+		db.Migrator().DropColumn(&model.RefreshToken{}, "refresh_token")
+	}
 	db.AutoMigrate(
 		&model.Facility{},
 		&model.File{},
@@ -57,5 +64,27 @@ func AutoMigrate() {
 		&model.Comment{},
 		&model.Tag{},
 		&model.Tagging{},
+		&model.StoreMember{},
+		&model.StoreBusinessHour{},
+		&model.StoreHoliday{},
+		&model.StoreReview{},
+		&model.StoreMenuItem{},
+		&model.Translation{},
+		&model.TranslationFailure{},
+		&model.ARAnchor{},
+		&model.Campaign{},
+		&model.CampaignFacility{},
+		&model.CampaignStore{},
+		&model.CampaignReward{},
+		&model.Coupon{},
+		&model.UserCoupon{},
+		&model.Favorite{},
+		&model.Itinerary{},
+		&model.ItineraryDay{},
+		&model.ItineraryStop{},
+		&model.SearchSuggestion{},
+		&model.ItemCoVisitation{},
+		&model.PasswordResetToken{},
+		&model.AccountLink{},
 	)
 }
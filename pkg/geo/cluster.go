@@ -0,0 +1,52 @@
+package geo
+
+import "math"
+
+// Point 参与聚合的坐标点，Index 指向调用方的原始数据
+type Point struct {
+	Lat   float64
+	Lng   float64
+	Kind  string
+	Index int
+}
+
+// Cluster 聚合结果，Members 为组成该聚合的原始数据下标
+type Cluster struct {
+	Lat     float64
+	Lng     float64
+	Count   int
+	Kinds   map[string]int
+	Members []int
+}
+
+// cellsPerTile 每个 256px 瓦片在一个方向上切分的网格数（约64px一个网格）
+const cellsPerTile = 4
+
+// GridCluster 按缩放级别把点划分到固定网格中进行聚合，返回顺序与点首次出现的顺序一致
+func GridCluster(points []Point, zoom int) []Cluster {
+	if zoom < 0 {
+		zoom = 0
+	}
+	cellDeg := 360 / math.Pow(2, float64(zoom)) / cellsPerTile
+
+	type cellKey struct{ x, y int64 }
+	index := make(map[cellKey]int)
+	var clusters []Cluster
+	for _, p := range points {
+		key := cellKey{int64(math.Floor(p.Lng / cellDeg)), int64(math.Floor(p.Lat / cellDeg))}
+		i, ok := index[key]
+		if !ok {
+			i = len(clusters)
+			index[key] = i
+			clusters = append(clusters, Cluster{Kinds: make(map[string]int)})
+		}
+		cl := &clusters[i]
+		// 增量计算质心
+		cl.Count++
+		cl.Lat += (p.Lat - cl.Lat) / float64(cl.Count)
+		cl.Lng += (p.Lng - cl.Lng) / float64(cl.Count)
+		cl.Kinds[p.Kind]++
+		cl.Members = append(cl.Members, p.Index)
+	}
+	return clusters
+}
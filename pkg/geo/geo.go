@@ -0,0 +1,36 @@
+package geo
+
+import "math"
+
+// EarthRadiusM 地球平均半径（米）
+const EarthRadiusM = 6371000.0
+
+// Distance 使用 haversine 公式计算两点间的球面直线距离（米）
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return EarthRadiusM * 2 * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// BoundingBox 返回以 (lat, lng) 为中心、半径 radiusM 米的外接矩形，用于在 SQL 中先做索引友好的粗筛
+func BoundingBox(lat, lng, radiusM float64) (minLat, minLng, maxLat, maxLng float64) {
+	dLat := radiusM / EarthRadiusM * 180 / math.Pi
+	// 高纬度时经度跨度会迅速变大，cos 取下限避免除零
+	dLng := dLat / math.Max(math.Cos(toRadians(lat)), 0.01)
+	return lat - dLat, lng - dLng, lat + dLat, lng + dLng
+}
+
+// SQLDistance 返回计算距离（米）的 PostgreSQL 表达式
+// 表达式中依次包含三个占位符：中心点纬度、中心点纬度、中心点经度
+func SQLDistance(latCol, lngCol string) string {
+	return "(6371000 * 2 * ASIN(SQRT(LEAST(1, " +
+		"POWER(SIN(RADIANS(" + latCol + " - ?) / 2), 2) + " +
+		"COS(RADIANS(?)) * COS(RADIANS(" + latCol + ")) * " +
+		"POWER(SIN(RADIANS(" + lngCol + " - ?) / 2), 2)))))"
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
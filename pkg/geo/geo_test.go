@@ -0,0 +1,54 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	// 东京站 -> 浅草寺，约 4.6km
+	d := Distance(35.681236, 139.767125, 35.714765, 139.796655)
+	if math.Abs(d-4580) > 100 {
+		t.Errorf("expected about 4580m, got %.0fm", d)
+	}
+	if Distance(35, 139, 35, 139) != 0 {
+		t.Errorf("expected zero distance for identical points")
+	}
+}
+
+func TestBoundingBoxContainsRadius(t *testing.T) {
+	lat, lng, r := 35.68, 139.76, 1000.0
+	minLat, minLng, maxLat, maxLng := BoundingBox(lat, lng, r)
+	if d := Distance(lat, lng, maxLat, lng); math.Abs(d-r) > 1 {
+		t.Errorf("expected north edge at %.0fm, got %.0fm", r, d)
+	}
+	if d := Distance(lat, lng, lat, minLng); d < r-1 {
+		t.Errorf("expected west edge at least %.0fm away, got %.0fm", r, d)
+	}
+	if minLat >= lat || maxLng <= lng {
+		t.Errorf("bounding box does not surround the center")
+	}
+}
+
+func TestGridCluster(t *testing.T) {
+	points := []Point{
+		{Lat: 35.6800, Lng: 139.7600, Kind: "Store", Index: 0},
+		{Lat: 35.6801, Lng: 139.7601, Kind: "Facility", Index: 1},
+		{Lat: 34.6900, Lng: 135.5000, Kind: "Store", Index: 2},
+	}
+	clusters := GridCluster(points, 10)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].Count != 2 || clusters[0].Kinds["Store"] != 1 || clusters[0].Kinds["Facility"] != 1 {
+		t.Errorf("unexpected first cluster: %+v", clusters[0])
+	}
+	if clusters[1].Count != 1 || clusters[1].Members[0] != 2 {
+		t.Errorf("unexpected second cluster: %+v", clusters[1])
+	}
+
+	// 最大缩放级别下相邻点应分开
+	if got := len(GridCluster(points, 22)); got != 3 {
+		t.Errorf("expected 3 clusters at zoom 22, got %d", got)
+	}
+}
@@ -0,0 +1,154 @@
+package hours
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location 营业时间统一按日本时间计算（日本没有夏令时，使用固定时区避免依赖系统时区数据）
+var Location = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// DateLayout 例外日期格式
+const DateLayout = "2006-01-02"
+
+// searchDays 计算下次营业时间时向后查找的天数
+const searchDays = 14
+
+// Range 一天中的营业区间，以分钟表示；Close <= Open 表示跨越午夜，在次日 Close 结束
+type Range struct {
+	Open  int
+	Close int
+}
+
+// Contains 判断 t 时刻（按日本时间的钟点）是否落在区间内
+func (r Range) Contains(t time.Time) bool {
+	t = t.In(Location)
+	m := t.Hour()*60 + t.Minute()
+	if r.Close > r.Open {
+		return m >= r.Open && m < r.Close
+	}
+	return m >= r.Open || m < r.Close
+}
+
+// Interval 每周固定营业区间
+type Interval struct {
+	Weekday time.Weekday
+	Range
+}
+
+// Exception 特定日期的例外安排（节假日休息或临时营业时间）
+type Exception struct {
+	Closed bool    // 当天全天休息
+	Ranges []Range // 当天的特殊营业区间，Closed 为 true 时忽略
+}
+
+// Schedule 商铺的营业时间表
+type Schedule struct {
+	Weekly     []Interval
+	Exceptions map[string]Exception // key 为 DateLayout 格式的日期
+}
+
+// Empty 是否没有任何营业时间设置
+func (s Schedule) Empty() bool {
+	return len(s.Weekly) == 0 && len(s.Exceptions) == 0
+}
+
+// IsOpen 判断 t 时刻是否营业
+func (s Schedule) IsOpen(t time.Time) bool {
+	t = t.In(Location)
+	day := startOfDay(t)
+	// 前一天跨午夜的区间可能覆盖当前时刻
+	for _, d := range []time.Time{day.AddDate(0, 0, -1), day} {
+		for _, iv := range s.spans(d) {
+			if !t.Before(iv[0]) && t.Before(iv[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NextOpen 返回 t 之后最近一次开始营业的时间；当前正在营业或两周内没有营业安排时返回 nil
+func (s Schedule) NextOpen(t time.Time) *time.Time {
+	if s.IsOpen(t) {
+		return nil
+	}
+	t = t.In(Location)
+	day := startOfDay(t)
+	for i := 0; i <= searchDays; i++ {
+		var next *time.Time
+		for _, iv := range s.spans(day.AddDate(0, 0, i)) {
+			if iv[0].After(t) && (next == nil || iv[0].Before(*next)) {
+				start := iv[0]
+				next = &start
+			}
+		}
+		if next != nil {
+			return next
+		}
+	}
+	return nil
+}
+
+// spans 返回某一天开始的所有营业区间的绝对时间 [开始, 结束)
+func (s Schedule) spans(day time.Time) [][2]time.Time {
+	var ranges []Range
+	if ex, ok := s.Exceptions[day.Format(DateLayout)]; ok {
+		if !ex.Closed {
+			ranges = ex.Ranges
+		}
+	} else {
+		for _, iv := range s.Weekly {
+			if iv.Weekday == day.Weekday() {
+				ranges = append(ranges, iv.Range)
+			}
+		}
+	}
+
+	result := make([][2]time.Time, 0, len(ranges))
+	for _, r := range ranges {
+		start := day.Add(time.Duration(r.Open) * time.Minute)
+		end := day.Add(time.Duration(r.Close) * time.Minute)
+		if r.Close <= r.Open {
+			end = end.AddDate(0, 0, 1)
+		}
+		result = append(result, [2]time.Time{start, end})
+	}
+	return result
+}
+
+// ParseClock 解析 "HH:MM" 格式的时刻，返回自零点起的分钟数；允许 "24:00"
+func ParseClock(s string) (int, error) {
+	var h, m int
+	if len(s) != 5 || s[2] != ':' {
+		return 0, fmt.Errorf("时间格式应为HH:MM: %q", s)
+	}
+	if _, err := fmt.Sscanf(s, "%02d:%02d", &h, &m); err != nil {
+		return 0, fmt.Errorf("时间格式应为HH:MM: %q", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("时间超出范围: %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// ParseRange 解析开始、结束时刻
+func ParseRange(open, close string) (Range, error) {
+	o, err := ParseClock(open)
+	if err != nil {
+		return Range{}, err
+	}
+	c, err := ParseClock(close)
+	if err != nil {
+		return Range{}, err
+	}
+	if o == 24*60 {
+		return Range{}, fmt.Errorf("开始时间不能为24:00")
+	}
+	return Range{Open: o, Close: c}, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Location)
+}
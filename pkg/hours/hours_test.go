@@ -0,0 +1,95 @@
+package hours
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, Location)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestScheduleCrossMidnight(t *testing.T) {
+	// 每周五 18:00 - 次日 02:00（2025-06-06 为周五）
+	s := Schedule{Weekly: []Interval{{Weekday: time.Friday, Range: Range{Open: 18 * 60, Close: 2 * 60}}}}
+
+	cases := map[string]bool{
+		"2025-06-06 17:59": false,
+		"2025-06-06 18:00": true,
+		"2025-06-06 23:30": true,
+		"2025-06-07 01:59": true,
+		"2025-06-07 02:00": false,
+		"2025-06-07 18:30": false,
+	}
+	for ts, want := range cases {
+		if got := s.IsOpen(at(ts)); got != want {
+			t.Errorf("IsOpen(%s) = %v, want %v", ts, got, want)
+		}
+	}
+
+	next := s.NextOpen(at("2025-06-07 03:00"))
+	if next == nil || !next.Equal(at("2025-06-13 18:00")) {
+		t.Errorf("unexpected next open: %v", next)
+	}
+	if s.NextOpen(at("2025-06-06 19:00")) != nil {
+		t.Errorf("expected nil next open while open")
+	}
+}
+
+func TestScheduleExceptions(t *testing.T) {
+	weekly := []Interval{}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		weekly = append(weekly, Interval{Weekday: d, Range: Range{Open: 10 * 60, Close: 20 * 60}})
+	}
+	s := Schedule{
+		Weekly: weekly,
+		Exceptions: map[string]Exception{
+			"2025-01-01": {Closed: true},
+			"2025-01-02": {Ranges: []Range{{Open: 13 * 60, Close: 17 * 60}}},
+		},
+	}
+
+	if s.IsOpen(at("2025-01-01 12:00")) {
+		t.Errorf("expected closed on holiday")
+	}
+	if s.IsOpen(at("2025-01-02 11:00")) || !s.IsOpen(at("2025-01-02 14:00")) {
+		t.Errorf("expected special hours on 2025-01-02")
+	}
+	next := s.NextOpen(at("2025-01-01 09:00"))
+	if next == nil || !next.Equal(at("2025-01-02 13:00")) {
+		t.Errorf("unexpected next open: %v", next)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	if m, err := ParseClock("24:00"); err != nil || m != 1440 {
+		t.Errorf("ParseClock(24:00) = %d, %v", m, err)
+	}
+	for _, bad := range []string{"9:00", "25:00", "12:60", "24:30", "ab:cd"} {
+		if _, err := ParseClock(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	night := Range{Open: 22 * 60, Close: 2 * 60}
+	at := func(h, m int) time.Time { return time.Date(2025, 1, 1, h, m, 0, 0, Location) }
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(21, 59), false},
+		{at(22, 0), true},
+		{at(1, 59), true},
+		{at(2, 0), false},
+	} {
+		if got := night.Contains(tc.t); got != tc.want {
+			t.Errorf("Contains(%s) = %v, want %v", tc.t.Format("15:04"), got, tc.want)
+		}
+	}
+}
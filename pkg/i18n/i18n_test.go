@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"zh_tw":      "zh-TW",
+		"ZH-hant-tw": "zh-Hant-TW",
+		" EN ":       "en",
+		"*":          "",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("en;q=0.5, zh-TW, zh;q=0.8, fr;q=0, *;q=0.1")
+	want := []string{"zh-TW", "zh", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAcceptLanguage = %v, want %v", got, want)
+	}
+}
+
+func TestChain(t *testing.T) {
+	got := Chain("zh-TW", "en-US", "zh")
+	want := []string{"zh-TW", "zh", "en-US", "en", "ja"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain = %v, want %v", got, want)
+	}
+	if got := Chain(); !reflect.DeepEqual(got, []string{"ja"}) {
+		t.Errorf("Chain() = %v, want [ja]", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package randcode 生成用于兑换码等场景的随机字符串
+package randcode
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// alphabet 去掉了易混淆的 0/O、1/I/L
+const alphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// New 生成长度为 n 的随机码（加密安全）
+func New(n int) string {
+	max := big.NewInt(int64(len(alphabet)))
+	b := make([]byte, n)
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		b[i] = alphabet[v.Int64()]
+	}
+	return string(b)
+}
+
+// Digits 生成长度为 n 的随机数字串（加密安全），用于验证码
+func Digits(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		v, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			panic(err)
+		}
+		b[i] = byte('0' + v.Int64())
+	}
+	return string(b)
+}
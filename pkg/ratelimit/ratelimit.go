@@ -0,0 +1,56 @@
+// Package ratelimit 进程内按键限流
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter 按键计数的固定窗口限流器：每个键在 window 内最多允许 limit 次
+// 仅在单进程内生效，多实例部署时每个实例分别计数
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+type entry struct {
+	start time.Time
+	count int
+}
+
+// New 创建限流器
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, entries: map[string]*entry{}, now: time.Now}
+}
+
+// Allow 记录一次请求，未超过限额时返回 true
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	e, ok := l.entries[key]
+	if !ok || now.Sub(e.start) >= l.window {
+		if !ok && len(l.entries) >= 10000 {
+			l.sweep(now)
+		}
+		e = &entry{start: now}
+		l.entries[key] = e
+	}
+	if e.count >= l.limit {
+		return false
+	}
+	e.count++
+	return true
+}
+
+// sweep 清除已过窗口的键，避免内存无限增长
+func (l *Limiter) sweep(now time.Time) {
+	for k, e := range l.entries {
+		if now.Sub(e.start) >= l.window {
+			delete(l.entries, k)
+		}
+	}
+}
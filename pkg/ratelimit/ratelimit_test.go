@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	if !l.Allow("a") || !l.Allow("a") {
+		t.Fatal("first two requests should pass")
+	}
+	if l.Allow("a") {
+		t.Fatal("third request should be limited")
+	}
+	if !l.Allow("b") {
+		t.Fatal("other keys are counted separately")
+	}
+
+	now = now.Add(59 * time.Second)
+	if l.Allow("a") {
+		t.Fatal("still inside the window")
+	}
+	now = now.Add(time.Second)
+	if !l.Allow("a") {
+		t.Fatal("new window should pass")
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	l := New(1, time.Minute)
+	l.now = func() time.Time { return now }
+	l.Allow("old")
+	now = now.Add(time.Minute)
+	l.sweep(now)
+	if _, ok := l.entries["old"]; ok {
+		t.Error("expired entry should be removed")
+	}
+}
@@ -0,0 +1,238 @@
+// Package route 按直线距离优化停留点的访问顺序
+// 纯本地计算（最近邻构造 + 2-opt 改进），不依赖外部路径规划服务
+package route
+
+import (
+	"time"
+
+	"travel-ar-backend/pkg/geo"
+)
+
+// 默认参数
+const (
+	DefaultSpeedMPS = 1.2              // 步行速度（米/秒）
+	DefaultDwell    = 30 * time.Minute // 每个停留点的停留时间
+	DefaultMaxWait  = 2 * time.Hour    // 到达时未营业，最多等待开门的时间
+)
+
+// Schedule 营业时间，hours.Schedule 满足该接口
+type Schedule interface {
+	IsOpen(t time.Time) bool
+	NextOpen(t time.Time) *time.Time
+}
+
+// Stop 停留点
+type Stop struct {
+	ID    int
+	Lat   float64
+	Lng   float64
+	Hours Schedule // 营业时间，nil 表示不限
+}
+
+// Options 优化参数
+type Options struct {
+	Start    *Stop         // 固定起点，不参与排序
+	End      *Stop         // 固定终点，不参与排序
+	Depart   time.Time     // 出发时间；零值表示不考虑营业时间
+	SpeedMPS float64       // 移动速度，默认 DefaultSpeedMPS
+	Dwell    time.Duration // 停留时间，默认 DefaultDwell
+	MaxWait  time.Duration // 最长等待开门时间，默认 DefaultMaxWait
+}
+
+// Visit 优化结果中的一个停留点
+type Visit struct {
+	Stop
+	Arrive time.Time     // 到达时间（未指定出发时间时为零值）
+	Wait   time.Duration // 等待开门的时间
+	Closed bool          // 到达时未营业，且在 MaxWait 内不会开门
+}
+
+// Result 优化结果
+type Result struct {
+	Visits     []Visit // 按访问顺序排列，不含固定起点与终点
+	DistanceM  float64 // 含起点、终点在内的直线距离合计（米）
+	Violations int     // Closed 的停留点数
+}
+
+// Optimize 计算访问顺序：优先减少无法营业的停留点，其次最小化总距离
+func Optimize(stops []Stop, opt Options) Result {
+	if opt.SpeedMPS <= 0 {
+		opt.SpeedMPS = DefaultSpeedMPS
+	}
+	if opt.Dwell <= 0 {
+		opt.Dwell = DefaultDwell
+	}
+	if opt.MaxWait <= 0 {
+		opt.MaxWait = DefaultMaxWait
+	}
+	p := &planner{stops: stops, opt: opt}
+	if len(stops) == 0 {
+		return p.evaluate(nil)
+	}
+
+	// 最近邻构造；未固定起点时以每个停留点为起点各构造一次取最优
+	var best []int
+	var bestCost cost
+	firsts := []int{-1}
+	if opt.Start == nil {
+		firsts = make([]int, len(stops))
+		for i := range firsts {
+			firsts[i] = i
+		}
+	}
+	for _, first := range firsts {
+		order := p.nearestNeighbour(first)
+		if c := p.cost(order); best == nil || c.less(bestCost) {
+			best, bestCost = order, c
+		}
+	}
+	return p.evaluate(p.twoOpt(best))
+}
+
+type planner struct {
+	stops []Stop
+	opt   Options
+}
+
+// cost 先比较违反营业时间的停留点数，再比较距离
+type cost struct {
+	violations int
+	distance   float64
+}
+
+func (c cost) less(o cost) bool {
+	if c.violations != o.violations {
+		return c.violations < o.violations
+	}
+	return c.distance < o.distance-1e-6
+}
+
+func distance(a, b Stop) float64 {
+	return geo.Distance(a.Lat, a.Lng, b.Lat, b.Lng)
+}
+
+// nearestNeighbour 每一步前往最近且能按时营业的停留点，没有时前往最近的停留点
+// first 为 -1 时从固定起点出发
+func (p *planner) nearestNeighbour(first int) []int {
+	visited := make([]bool, len(p.stops))
+	order := make([]int, 0, len(p.stops))
+	var cur *Stop
+	var now time.Time
+	if p.opt.Start != nil {
+		cur, now = p.opt.Start, p.opt.Depart
+	}
+	if first >= 0 {
+		visited[first] = true
+		order = append(order, first)
+		cur, now = &p.stops[first], p.leave(nil, p.stops[first], p.opt.Depart)
+	}
+	for len(order) < len(p.stops) {
+		pick, pickOpen, pickDist := -1, false, 0.0
+		for i, s := range p.stops {
+			if visited[i] {
+				continue
+			}
+			d := 0.0
+			if cur != nil {
+				d = distance(*cur, s)
+			}
+			_, _, closed := p.arrive(d, s, now)
+			open := !closed
+			if pick < 0 || (open && !pickOpen) || (open == pickOpen && d < pickDist) {
+				pick, pickOpen, pickDist = i, open, d
+			}
+		}
+		visited[pick] = true
+		order = append(order, pick)
+		now = p.leave(cur, p.stops[pick], now)
+		cur = &p.stops[pick]
+	}
+	return order
+}
+
+// twoOpt 反转区间直到没有改进
+func (p *planner) twoOpt(order []int) []int {
+	best := p.cost(order)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for j := i + 1; j < len(order); j++ {
+				reverse(order, i, j)
+				if c := p.cost(order); c.less(best) {
+					best, improved = c, true
+				} else {
+					reverse(order, i, j)
+				}
+			}
+		}
+	}
+	return order
+}
+
+func reverse(order []int, i, j int) {
+	for ; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
+
+// arrive 计算到达时间、等待时间以及是否无法营业
+func (p *planner) arrive(d float64, s Stop, now time.Time) (time.Time, time.Duration, bool) {
+	if p.opt.Depart.IsZero() {
+		return time.Time{}, 0, false
+	}
+	at := now.Add(time.Duration(d / p.opt.SpeedMPS * float64(time.Second)))
+	if s.Hours == nil || s.Hours.IsOpen(at) {
+		return at, 0, false
+	}
+	if next := s.Hours.NextOpen(at); next != nil && next.Sub(at) <= p.opt.MaxWait {
+		return at, next.Sub(at), false
+	}
+	return at, 0, true
+}
+
+// leave 从 from 前往 s，返回离开 s 的时间
+func (p *planner) leave(from *Stop, s Stop, now time.Time) time.Time {
+	if p.opt.Depart.IsZero() {
+		return now
+	}
+	d := 0.0
+	if from != nil {
+		d = distance(*from, s)
+	}
+	at, wait, _ := p.arrive(d, s, now)
+	return at.Add(wait + p.opt.Dwell)
+}
+
+func (p *planner) cost(order []int) cost {
+	r := p.evaluate(order)
+	return cost{r.Violations, r.DistanceM}
+}
+
+// evaluate 按给定顺序模拟一遍行程
+func (p *planner) evaluate(order []int) Result {
+	r := Result{Visits: make([]Visit, 0, len(order))}
+	cur, now := p.opt.Start, p.opt.Depart
+	for _, idx := range order {
+		s := p.stops[idx]
+		d := 0.0
+		if cur != nil {
+			d = distance(*cur, s)
+		}
+		at, wait, closed := p.arrive(d, s, now)
+		r.DistanceM += d
+		r.Visits = append(r.Visits, Visit{Stop: s, Arrive: at, Wait: wait, Closed: closed})
+		if closed {
+			r.Violations++
+		}
+		if !p.opt.Depart.IsZero() {
+			now = at.Add(wait + p.opt.Dwell)
+		}
+		cur = &p.stops[idx]
+	}
+	if p.opt.End != nil && cur != nil {
+		r.DistanceM += distance(*cur, *p.opt.End)
+	} else if p.opt.End != nil && p.opt.Start != nil {
+		r.DistanceM += distance(*p.opt.Start, *p.opt.End)
+	}
+	return r
+}
@@ -0,0 +1,91 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	"travel-ar-backend/pkg/hours"
+)
+
+// 沿经线排列的点，每个间隔约 1.1km
+func line(ids ...int) []Stop {
+	stops := make([]Stop, len(ids))
+	for i, id := range ids {
+		stops[i] = Stop{ID: id, Lat: 35 + float64(id)*0.01, Lng: 139}
+	}
+	return stops
+}
+
+func ids(r Result) []int {
+	out := make([]int, len(r.Visits))
+	for i, v := range r.Visits {
+		out[i] = v.ID
+	}
+	return out
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOptimizeLine(t *testing.T) {
+	r := Optimize(line(3, 0, 4, 1, 2), Options{})
+	got := ids(r)
+	if !equal(got, []int{0, 1, 2, 3, 4}) && !equal(got, []int{4, 3, 2, 1, 0}) {
+		t.Errorf("unexpected order %v", got)
+	}
+	want := distance(line(0)[0], line(4)[0])
+	if r.DistanceM < want-1 || r.DistanceM > want+1 {
+		t.Errorf("expected distance %.0f, got %.0f", want, r.DistanceM)
+	}
+}
+
+func TestOptimizeFixedEnds(t *testing.T) {
+	start, end := line(5)[0], line(0)[0]
+	r := Optimize(line(1, 3, 2, 4), Options{Start: &start, End: &end})
+	if got := ids(r); !equal(got, []int{4, 3, 2, 1}) {
+		t.Errorf("unexpected order %v", got)
+	}
+	want := distance(start, end)
+	if r.DistanceM < want-1 || r.DistanceM > want+1 {
+		t.Errorf("expected distance %.0f, got %.0f", want, r.DistanceM)
+	}
+}
+
+func TestOptimizeOpeningHours(t *testing.T) {
+	depart := time.Date(2025, 6, 6, 9, 0, 0, 0, hours.Location) // 周五
+	stops := line(1, 2, 3)
+	// 1 号点 12:00 才开门，距离最优的顺序 1-2-3 会在开门前到达
+	stops[0].Hours = hours.Schedule{Weekly: []hours.Interval{{Weekday: time.Friday, Range: hours.Range{Open: 12 * 60, Close: 18 * 60}}}}
+	start := line(0)[0]
+
+	r := Optimize(stops, Options{Start: &start, Depart: depart, Dwell: time.Hour, MaxWait: time.Minute})
+	if r.Violations != 0 {
+		t.Fatalf("expected no violations, got %d (%v)", r.Violations, ids(r))
+	}
+	last := r.Visits[len(r.Visits)-1]
+	if last.ID != 1 || last.Arrive.Before(depart.Add(3*time.Hour)) {
+		t.Errorf("expected stop 1 visited last after opening, got %v arriving %v", ids(r), last.Arrive)
+	}
+
+	// 不考虑营业时间时按距离排序
+	if got := ids(Optimize(stops, Options{Start: &start})); !equal(got, []int{1, 2, 3}) {
+		t.Errorf("unexpected order without hours %v", got)
+	}
+}
+
+func TestOptimizeEmpty(t *testing.T) {
+	start, end := line(0)[0], line(1)[0]
+	r := Optimize(nil, Options{Start: &start, End: &end})
+	if len(r.Visits) != 0 || r.DistanceM < 1000 {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
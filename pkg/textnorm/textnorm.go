@@ -0,0 +1,42 @@
+// Package textnorm 检索用文本归一化
+//
+// 统一全角/半角（NFKC）、片假名/平假名与大小写，并压缩空白，
+// 使「ﾄｳｷｮｳ」「トウキョウ」「とうきょう」以及「ＴＯＫＹＯ」「tokyo」得到相同的键。
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold 返回 s 的检索键：NFKC 兼容分解后合成（全角英数→半角、半角片假名→全角并合成浊音），
+// 片假名转平假名，转小写，连续空白压缩为一个空格并去除首尾空白
+func Fold(s string) string {
+	s = norm.NFKC.String(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(toHiragana(unicode.ToLower(r)))
+	}
+	return b.String()
+}
+
+// toHiragana 片假名（ァ〜ヶ）转为对应平假名，其余字符原样返回
+// 「ヷ」等无对应平假名的字符以及长音符「ー」保持不变
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
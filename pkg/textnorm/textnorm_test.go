@@ -0,0 +1,22 @@
+package textnorm
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"トウキョウ", "とうきょう"},
+		{"ﾄｳｷｮｳ", "とうきょう"},
+		{"ｶﾞｲﾄﾞ", "がいど"},
+		{"ＴＯＫＹＯ　Ｔｏｗｅｒ", "tokyo tower"},
+		{"  Tokyo \t  Tower  ", "tokyo tower"},
+		{"浅草寺", "浅草寺"},
+		{"スカイツリー", "すかいつりー"},
+		{"①２３", "123"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := Fold(c.in); got != c.want {
+			t.Errorf("Fold(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
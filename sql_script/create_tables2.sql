@@ -1,4 +1,26 @@
 -- 既存のテーブルを削除（依存関係を考慮して逆順で削除）
+DROP TABLE IF EXISTS store_holidays;
+DROP TABLE IF EXISTS store_business_hours;
+DROP TABLE IF EXISTS store_reviews;
+DROP TABLE IF EXISTS store_menu_items;
+DROP TABLE IF EXISTS translation_failures;
+DROP TABLE IF EXISTS translations;
+DROP TABLE IF EXISTS ar_anchors;
+DROP TABLE IF EXISTS user_coupons;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS campaign_rewards;
+DROP TABLE IF EXISTS campaign_stores;
+DROP TABLE IF EXISTS campaign_facilities;
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS itinerary_stops;
+DROP TABLE IF EXISTS itinerary_days;
+DROP TABLE IF EXISTS itineraries;
+DROP TABLE IF EXISTS search_suggestions;
+DROP TABLE IF EXISTS item_co_visitations;
+DROP TABLE IF EXISTS password_reset_tokens;
+DROP TABLE IF EXISTS account_links;
+DROP TABLE IF EXISTS store_members;
 DROP TABLE IF EXISTS taggings;
 DROP TABLE IF EXISTS tags;
 DROP TABLE IF EXISTS comments;
@@ -17,6 +39,8 @@ DROP TABLE IF EXISTS facilities;
 CREATE TABLE facilities (
     facility_id SERIAL PRIMARY KEY,                   -- 施設ID: 施設を一意に識別するID
     facility_name VARCHAR(255) NOT NULL,              -- 施設名（全角）
+    facility_name_kana VARCHAR(255),                  -- 施設名（カナ）: 入力補完用の読み（全角、可空）
+    facility_category VARCHAR(100),                   -- 施設カテゴリ（例：神社, 博物館）（全角）
     location VARCHAR(255) NOT NULL,                   -- 所在地: 施設の住所（全角）
     description_text TEXT,                            -- 説明（全角）
     latitude DECIMAL(10,6) NOT NULL,                  -- 緯度: 施設の緯度情報（半角）
@@ -30,6 +54,8 @@ COMMENT ON TABLE facilities IS '施設情報管理テーブル';
 -- カラムコメント
 COMMENT ON COLUMN facilities.facility_id IS '施設ID: 施設を一意に識別するID';
 COMMENT ON COLUMN facilities.facility_name IS '施設名（全角）';
+COMMENT ON COLUMN facilities.facility_name_kana IS '施設名（カナ）: 入力補完用の読み（全角、可空）';
+COMMENT ON COLUMN facilities.facility_category IS '施設カテゴリ（例：神社, 博物館）（全角）';
 COMMENT ON COLUMN facilities.location IS '所在地: 施設の住所（全角）';
 COMMENT ON COLUMN facilities.description_text IS '説明（全角）';
 COMMENT ON COLUMN facilities.latitude IS '緯度: 施設の緯度情報（半角）';
@@ -37,6 +63,8 @@ COMMENT ON COLUMN facilities.longitude IS '経度: 施設の経度情報（半
 COMMENT ON COLUMN facilities.person_id IS '関連人物ID（半角）';
 COMMENT ON COLUMN facilities.created_at IS '作成日';
 COMMENT ON COLUMN facilities.updated_at IS '更新日';
+-- 位置検索用インデックス
+CREATE INDEX idx_facilities_lat_lng ON facilities (latitude, longitude);
 
 -- ファイルテーブル生成
 CREATE TABLE files (
@@ -47,6 +75,7 @@ CREATE TABLE files (
     file_data BYTEA NOT NULL,                         -- ファイルデータ: 画像データのバイナリ情報
     location VARCHAR(255) NOT NULL,                   -- 所在地（例：東京都北区）（全角）
     related_id INTEGER NOT NULL,                      -- 関連ID: 画像の関連データ（例: ユーザ, 店舗など）（半角）
+    related_type VARCHAR(50),                         -- 関連種別: related_idが指すテーブルの種類（例: Store）（半角）
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新日
 );
@@ -60,6 +89,7 @@ COMMENT ON COLUMN files.file_size IS 'ファイルサイズ（半角）';
 COMMENT ON COLUMN files.file_data IS 'ファイルデータ: 画像データのバイナリ情報';
 COMMENT ON COLUMN files.location IS '所在地（例：東京都北区）（全角）';
 COMMENT ON COLUMN files.related_id IS '関連ID: 画像の関連データ（例: ユーザ, 店舗など）（半角）';
+COMMENT ON COLUMN files.related_type IS '関連種別: related_idが指すテーブルの種類（例: Store）（半角）';
 COMMENT ON COLUMN files.created_at IS '作成日';
 COMMENT ON COLUMN files.updated_at IS '更新日';
 
@@ -110,11 +140,14 @@ COMMENT ON COLUMN visit_history.scan_at IS 'スキャン日時';
 COMMENT ON COLUMN visit_history.is_active IS '有効フラグ: 1=有効、0=無効（半角）';
 COMMENT ON COLUMN visit_history.created_at IS '作成日';
 COMMENT ON COLUMN visit_history.updated_at IS '更新日';
+-- QRチェックインの重複判定用インデックス
+CREATE INDEX idx_visit_history_user_facility ON visit_history (user_id, facility_id, scan_at);
 
 -- 言語テーブル生成
 CREATE TABLE languages (
     language_id SERIAL PRIMARY KEY,                   -- 言語ID: 言語を一意に識別するID
     language_name VARCHAR(50) NOT NULL,               -- 言語名（全角）
+    language_code VARCHAR(20) NOT NULL UNIQUE,        -- 言語コード: BCP 47 形式（例：ja, en, zh-TW）（半角）
     display_order INTEGER,                            -- 表示順: UIでの言語選択時の表示順（半角）
     is_active BOOLEAN NOT NULL DEFAULT TRUE,          -- 有効フラグ: 使用可能な言語かどうかを管理（1=有効、0=無効）（半角）
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
@@ -125,6 +158,7 @@ COMMENT ON TABLE languages IS '言語マスタテーブル';
 -- カラムコメント
 COMMENT ON COLUMN languages.language_id IS '言語ID: 言語を一意に識別するID';
 COMMENT ON COLUMN languages.language_name IS '言語名（全角）';
+COMMENT ON COLUMN languages.language_code IS '言語コード: BCP 47 形式（例：ja, en, zh-TW）（半角）';
 COMMENT ON COLUMN languages.display_order IS '表示順: UIでの言語選択時の表示順（半角）';
 COMMENT ON COLUMN languages.is_active IS '有効フラグ: 使用可能な言語かどうかを管理（1=有効、0=無効）（半角）';
 COMMENT ON COLUMN languages.created_at IS '作成日';
@@ -143,15 +177,20 @@ CREATE TABLE users (
     password VARCHAR(128),                            -- パスワード（半角、可空、メールログイン用）
     google_id VARCHAR(255),                           -- GoogleログインのユニークID（半角、可空）
     apple_id VARCHAR(255),                            -- AppleログインのユニークID（半角、可空）
+    line_id VARCHAR(255),                             -- LINEログインのユニークID（半角、可空）
     avatar VARCHAR(255),                              -- アバター（半角、可空）
-    provider VARCHAR(20) NOT NULL,                    -- ログイン方式: email, google, apple（半角）
+    provider VARCHAR(20) NOT NULL,                    -- 登録時のログイン方式: email, google, apple, line（半角）
     verify_code VARCHAR(255),                         -- 検証コード（半角、可空）
     verify_code_expire TIMESTAMP,                     -- 検証コード有効期限（半角、可空）
+    verify_attempts INTEGER NOT NULL DEFAULT 0,       -- 検証コード入力失敗回数（半角）
+    verify_sent_at TIMESTAMP,                         -- 検証コード最終送信日時（再送制限用、可空）
     status VARCHAR(20) NOT NULL,                       -- アカウント状態: pending active disabled inactive
+    role VARCHAR(20) NOT NULL DEFAULT 'tourist',       -- 権限ロール: admin, editor, store_owner, tourist（半角）
     created_at TIMESTAMP NOT NULL,                     -- 登録日
     updated_at TIMESTAMP,                              -- 更新日
     CONSTRAINT chk_gender CHECK (gender IN ('1', '2') OR gender IS NULL), -- 性別チェック制約
     CONSTRAINT chk_status CHECK (status IN ('pending', 'active', 'disabled')), -- ステータスチェック制約
+    CONSTRAINT chk_role CHECK (role IN ('admin', 'editor', 'store_owner', 'tourist')), -- ロールチェック制約
     CONSTRAINT chk_provider CHECK (provider IN ('email', 'google', 'apple')), -- ログイン方式チェック制約
     CONSTRAINT unique_email UNIQUE (email)             -- メールアドレスのユニーク制約
 );
@@ -169,19 +208,35 @@ COMMENT ON COLUMN users.email IS 'メールアドレス（半角、必須）';
 COMMENT ON COLUMN users.password IS 'パスワード（半角、可空、メールログイン用）';
 COMMENT ON COLUMN users.google_id IS 'GoogleログインのユニークID（半角、可空）';
 COMMENT ON COLUMN users.apple_id IS 'AppleログインのユニークID（半角、可空）';
-COMMENT ON COLUMN users.provider IS 'ログイン方式: email, google, apple（半角）';
+COMMENT ON COLUMN users.line_id IS 'LINEログインのユニークID（半角、可空）';
+COMMENT ON COLUMN users.provider IS '登録時のログイン方式: email, google, apple, line（半角）';
+COMMENT ON COLUMN users.verify_code IS '検証コード（半角、可空）';
+COMMENT ON COLUMN users.verify_code_expire IS '検証コード有効期限（半角、可空）';
+COMMENT ON COLUMN users.verify_attempts IS '検証コード入力失敗回数（半角）';
+COMMENT ON COLUMN users.verify_sent_at IS '検証コード最終送信日時（再送制限用、可空）';
 COMMENT ON COLUMN users.status IS 'アカウント状態: active=アクティブ、pending=未アクティブ、disabled=無効';
+COMMENT ON COLUMN users.role IS '権限ロール: admin=管理者、editor=コンテンツ編集者、store_owner=店舗オーナー、tourist=旅行者';
 COMMENT ON COLUMN users.created_at IS '登録日';
 COMMENT ON COLUMN users.updated_at IS '更新日';
+-- 外部ログインIDは 1 ユーザーにのみ紐付け可能（未連携は空文字のため部分インデックス）
+CREATE UNIQUE INDEX uq_users_google_id ON users (google_id) WHERE google_id <> '';
+CREATE UNIQUE INDEX uq_users_apple_id ON users (apple_id) WHERE apple_id <> '';
+CREATE UNIQUE INDEX uq_users_line_id ON users (line_id) WHERE line_id <> '';
 
--- リフレッシュトークンテーブル生成
+-- リフレッシュトークンテーブル生成（更新のたびに新トークンを発行して旧トークンを無効化、同一ログインのトークンは family_id で 1 セッションにまとめる）
 CREATE TABLE refresh_tokens (
     token_id SERIAL PRIMARY KEY,                      -- トークンID: リフレッシュトークンを一意に識別するID
     user_id INTEGER NOT NULL,                        -- ユーザID（ユーザテーブルのFK）
-    refresh_token VARCHAR(255) NOT NULL,              -- リフレッシュトークン（半角）
+    family_id VARCHAR(32) NOT NULL,                  -- セッションID: 同一ログインから更新されたトークン群の識別子（半角）
+    token_hash VARCHAR(64) NOT NULL UNIQUE,          -- トークンの SHA-256 ハッシュ（16進、半角）
+    device_name VARCHAR(100),                        -- 端末名: X-Device-Name ヘッダーの値
+    ip_address VARCHAR(45),                          -- 発行時のIPアドレス（半角）
+    user_agent VARCHAR(255),                         -- 発行時のユーザーエージェント（半角）
     expires_at TIMESTAMP NOT NULL,                    -- 有効期限
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
     revoked BOOLEAN NOT NULL DEFAULT FALSE,           -- 無効化フラグ: true=無効、false=有効
+    rotated_at TIMESTAMP,                            -- 更新日時: 新トークンに置き換えられた日時、置き換え後の再使用は盗用とみなしセッション全体を無効化
+    replaced_by INTEGER,                             -- 置き換え先のトークンID
     CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) -- ユーザIDの外部キー制約
 );
 -- テーブルコメント
@@ -189,15 +244,25 @@ COMMENT ON TABLE refresh_tokens IS 'リフレッシュトークン管理テー
 -- カラムコメント
 COMMENT ON COLUMN refresh_tokens.token_id IS 'トークンID: リフレッシュトークンを一意に識別するID';
 COMMENT ON COLUMN refresh_tokens.user_id IS 'ユーザID（ユーザテーブルのFK）';
-COMMENT ON COLUMN refresh_tokens.refresh_token IS 'リフレッシュトークン（半角）';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'セッションID: 同一ログインから更新されたトークン群の識別子（半角）';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'トークンの SHA-256 ハッシュ（16進、半角）';
+COMMENT ON COLUMN refresh_tokens.device_name IS '端末名: X-Device-Name ヘッダーの値';
+COMMENT ON COLUMN refresh_tokens.ip_address IS '発行時のIPアドレス（半角）';
+COMMENT ON COLUMN refresh_tokens.user_agent IS '発行時のユーザーエージェント（半角）';
 COMMENT ON COLUMN refresh_tokens.expires_at IS '有効期限';
 COMMENT ON COLUMN refresh_tokens.created_at IS '作成日';
 COMMENT ON COLUMN refresh_tokens.revoked IS '無効化フラグ: true=無効、false=有効';
+COMMENT ON COLUMN refresh_tokens.rotated_at IS '更新日時: 新トークンに置き換えられた日時、置き換え後の再使用は盗用とみなしセッション全体を無効化';
+COMMENT ON COLUMN refresh_tokens.replaced_by IS '置き換え先のトークンID';
+-- セッション一覧・一括無効化用インデックス
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
 
 -- 店舗テーブル生成
 CREATE TABLE stores (
     store_id SERIAL PRIMARY KEY,                      -- 店舗ID: 店舗を一意に識別するID
     store_name VARCHAR(255) NOT NULL,                 -- 店舗名（全角）
+    store_name_kana VARCHAR(255),                     -- 店舗名（カナ）: 入力補完用の読み（全角、可空）
     store_category VARCHAR(100) NOT NULL,             -- 店舗カテゴリ（例：飲食店, お土産）（半角）
     location VARCHAR(255) NOT NULL,                   -- 所在地（例：東京都北区）（全角）
     description_text TEXT,                            -- 説明（全角）
@@ -205,7 +270,8 @@ CREATE TABLE stores (
     latitude DECIMAL(10,6) NOT NULL,                  -- 緯度: 店舗の緯度情報（半角）
     longitude DECIMAL(10,6) NOT NULL,                 -- 経度: 店舗の経度情報（半角）
     business_hours VARCHAR(100) NOT NULL,             -- 営業時間（半角）
-    rating_score DECIMAL(3,2) NOT NULL,               -- 評価点数（半角）
+    rating_score DECIMAL(3,2) NOT NULL DEFAULT 0,     -- 評価点数: 公開レビューの平均点（半角）
+    review_count INTEGER NOT NULL DEFAULT 0,          -- レビュー件数: 公開レビューの件数（半角）
     phone_number VARCHAR(20) NOT NULL,                -- 電話番号（半角）
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新日
@@ -215,6 +281,7 @@ COMMENT ON TABLE stores IS '店舗情報管理テーブル';
 -- カラムコメント
 COMMENT ON COLUMN stores.store_id IS '店舗ID: 店舗を一意に識別するID';
 COMMENT ON COLUMN stores.store_name IS '店舗名（全角）';
+COMMENT ON COLUMN stores.store_name_kana IS '店舗名（カナ）: 入力補完用の読み（全角、可空）';
 COMMENT ON COLUMN stores.store_category IS '店舗カテゴリ（例：飲食店, お土産）（半角）';
 COMMENT ON COLUMN stores.location IS '所在地（例：東京都北区）（全角）';
 COMMENT ON COLUMN stores.description_text IS '説明（全角）';
@@ -222,10 +289,13 @@ COMMENT ON COLUMN stores.address IS '住所（全角）';
 COMMENT ON COLUMN stores.latitude IS '緯度: 店舗の緯度情報（半角）';
 COMMENT ON COLUMN stores.longitude IS '経度: 店舗の経度情報（半角）';
 COMMENT ON COLUMN stores.business_hours IS '営業時間（半角）';
-COMMENT ON COLUMN stores.rating_score IS '評価点数（半角）';
+COMMENT ON COLUMN stores.rating_score IS '評価点数: 公開レビューの平均点（半角）';
+COMMENT ON COLUMN stores.review_count IS 'レビュー件数: 公開レビューの件数（半角）';
 COMMENT ON COLUMN stores.phone_number IS '電話番号（半角）';
 COMMENT ON COLUMN stores.created_at IS '作成日';
 COMMENT ON COLUMN stores.updated_at IS '更新日';
+-- 位置検索用インデックス
+CREATE INDEX idx_stores_lat_lng ON stores (latitude, longitude);
 
 -- メニューテーブル生成
 CREATE TABLE menus (
@@ -317,21 +387,24 @@ COMMENT ON COLUMN tags.updated_at IS '更新日';
 CREATE TABLE taggings (
     tagging_id SERIAL PRIMARY KEY,                    -- 関連ID: タグとエンティティの関連を一意に識別するID
     tag_id INTEGER NOT NULL,                         -- タグID（タグテーブルのFK）
-    taggable_type VARCHAR(50) NOT NULL,              -- エ部分、エンティティ種別: 関連するテーブルの種類（例: Article, History, Store, Comment）
+    taggable_type VARCHAR(50) NOT NULL,              -- エ部分、エンティティ種別: 関連するテーブルの種類（例: Article, History, Store, Comment, Facility）
     taggable_id INTEGER NOT NULL,                    -- エンティティID: 関連するテーブルのレコードID
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
     updated_at TIMESTAMP,                            -- 更新日
-    CONSTRAINT fk_tag_id FOREIGN KEY (tag_id) REFERENCES tags(tag_id) -- タグIDの外部キー制約
+    CONSTRAINT fk_tag_id FOREIGN KEY (tag_id) REFERENCES tags(tag_id), -- タグIDの外部キー制約
+    CONSTRAINT uq_taggings_tag_target UNIQUE (tag_id, taggable_type, taggable_id) -- 同一エンティティへの重複付与防止
 );
 -- テーブルコメント
-COMMENT ON TABLE taggings IS 'タグとエンティティ（文章、履歴、店舗、コメント）の関連管理テーブル';
+COMMENT ON TABLE taggings IS 'タグとエンティティ（文章、履歴、店舗、コメント、施設）の関連管理テーブル';
 -- カラムコメント
 COMMENT ON COLUMN taggings.tagging_id IS '関連ID: タグとエンティティの関連を一意に識別するID';
 COMMENT ON COLUMN taggings.tag_id IS 'タグID（タグテーブルのFK）';
-COMMENT ON COLUMN taggings.taggable_type IS 'エンティティ種別: 関連するテーブルの種類（例: Article, History, Store, Comment）';
+COMMENT ON COLUMN taggings.taggable_type IS 'エンティティ種別: 関連するテーブルの種類（例: Article, History, Store, Comment, Facility）';
 COMMENT ON COLUMN taggings.taggable_id IS 'エンティティID: 関連するテーブルのレコードID';
 COMMENT ON COLUMN taggings.created_at IS '作成日';
 COMMENT ON COLUMN taggings.updated_at IS '更新日';
+-- エンティティ別の検索・絞り込み用インデックス
+CREATE INDEX idx_taggings_target ON taggings (taggable_type, taggable_id);
 
 -- トリガー関数: taggable_idの整合性をチェック
 CREATE OR REPLACE FUNCTION check_taggable_id() RETURNS TRIGGER AS $$
@@ -352,6 +425,12 @@ BEGIN
         SELECT 1 FROM comments WHERE comment_id = NEW.taggable_id
     ) THEN
         RAISE EXCEPTION 'Invalid taggable_id % for taggable_type Comment', NEW.taggable_id;
+    ELSIF NEW.taggable_type = 'Facility' AND NOT EXISTS (
+        SELECT 1 FROM facilities WHERE facility_id = NEW.taggable_id
+    ) THEN
+        RAISE EXCEPTION 'Invalid taggable_id % for taggable_type Facility', NEW.taggable_id;
+    ELSIF NEW.taggable_type NOT IN ('Article', 'History', 'Store', 'Comment', 'Facility') THEN
+        RAISE EXCEPTION 'Invalid taggable_type %', NEW.taggable_type;
     END IF;
     RETURN NEW;
 END;
@@ -361,4 +440,692 @@ $$ LANGUAGE plpgsql;
 CREATE TRIGGER taggings_check_taggable_id
     BEFORE INSERT OR UPDATE ON taggings
     FOR EACH ROW
-    EXECUTE FUNCTION check_taggable_id();
\ No newline at end of file
+    EXECUTE FUNCTION check_taggable_id();
+
+-- トリガー関数: 対象エンティティ削除時にタグ関連を削除
+CREATE OR REPLACE FUNCTION delete_taggings() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'articles' THEN
+        DELETE FROM taggings WHERE taggable_type = 'Article' AND taggable_id = OLD.article_id;
+    ELSIF TG_TABLE_NAME = 'visit_history' THEN
+        DELETE FROM taggings WHERE taggable_type = 'History' AND taggable_id = OLD.history_id;
+    ELSIF TG_TABLE_NAME = 'stores' THEN
+        DELETE FROM taggings WHERE taggable_type = 'Store' AND taggable_id = OLD.store_id;
+    ELSIF TG_TABLE_NAME = 'comments' THEN
+        DELETE FROM taggings WHERE taggable_type = 'Comment' AND taggable_id = OLD.comment_id;
+    ELSIF TG_TABLE_NAME = 'facilities' THEN
+        DELETE FROM taggings WHERE taggable_type = 'Facility' AND taggable_id = OLD.facility_id;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- トリガーの作成
+CREATE TRIGGER articles_delete_taggings
+    AFTER DELETE ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_taggings();
+CREATE TRIGGER visit_history_delete_taggings
+    AFTER DELETE ON visit_history
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_taggings();
+CREATE TRIGGER stores_delete_taggings
+    AFTER DELETE ON stores
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_taggings();
+CREATE TRIGGER comments_delete_taggings
+    AFTER DELETE ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_taggings();
+CREATE TRIGGER facilities_delete_taggings
+    AFTER DELETE ON facilities
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_taggings();
+
+-- 店舗メンバーテーブル生成
+CREATE TABLE store_members (
+    store_member_id SERIAL PRIMARY KEY,              -- 店舗メンバーID: 店舗とユーザの関連を一意に識別するID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    user_id INTEGER NOT NULL,                        -- ユーザID（ユーザテーブルのFK）
+    member_role VARCHAR(20) NOT NULL,                -- メンバー権限: owner=オーナー、staff=スタッフ（半角）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP,                            -- 更新日
+    CONSTRAINT fk_store_members_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE, -- 店舗IDの外部キー制約
+    CONSTRAINT fk_store_members_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,    -- ユーザIDの外部キー制約
+    CONSTRAINT chk_member_role CHECK (member_role IN ('owner', 'staff')), -- メンバー権限チェック制約
+    CONSTRAINT unique_store_member UNIQUE (store_id, user_id)              -- 店舗とユーザの組み合わせのユニーク制約
+);
+-- テーブルコメント
+COMMENT ON TABLE store_members IS '店舗メンバー（オーナー、スタッフ）管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN store_members.store_member_id IS '店舗メンバーID: 店舗とユーザの関連を一意に識別するID';
+COMMENT ON COLUMN store_members.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN store_members.user_id IS 'ユーザID（ユーザテーブルのFK）';
+COMMENT ON COLUMN store_members.member_role IS 'メンバー権限: owner=オーナー、staff=スタッフ（半角）';
+COMMENT ON COLUMN store_members.created_at IS '作成日';
+COMMENT ON COLUMN store_members.updated_at IS '更新日';
+CREATE INDEX idx_store_members_user_id ON store_members (user_id);
+
+-- 店舗営業時間テーブル生成
+CREATE TABLE store_business_hours (
+    business_hour_id SERIAL PRIMARY KEY,             -- 営業時間ID: 営業時間帯を一意に識別するID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    weekday SMALLINT NOT NULL,                       -- 曜日: 0=日曜 ... 6=土曜（半角）
+    open_time VARCHAR(5) NOT NULL,                   -- 開始時刻 HH:MM（半角）
+    close_time VARCHAR(5) NOT NULL,                  -- 終了時刻 HH:MM、開始時刻以下の場合は翌日まで営業（半角）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    CONSTRAINT fk_store_business_hours_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE, -- 店舗IDの外部キー制約
+    CONSTRAINT chk_weekday CHECK (weekday BETWEEN 0 AND 6) -- 曜日チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE store_business_hours IS '店舗の曜日別営業時間テーブル（日本時間）';
+-- カラムコメント
+COMMENT ON COLUMN store_business_hours.business_hour_id IS '営業時間ID: 営業時間帯を一意に識別するID';
+COMMENT ON COLUMN store_business_hours.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN store_business_hours.weekday IS '曜日: 0=日曜 ... 6=土曜（半角）';
+COMMENT ON COLUMN store_business_hours.open_time IS '開始時刻 HH:MM（半角）';
+COMMENT ON COLUMN store_business_hours.close_time IS '終了時刻 HH:MM、開始時刻以下の場合は翌日まで営業（半角）';
+COMMENT ON COLUMN store_business_hours.created_at IS '作成日';
+CREATE INDEX idx_store_business_hours_store_id ON store_business_hours (store_id, weekday);
+
+-- 店舗休業日・臨時営業テーブル生成
+CREATE TABLE store_holidays (
+    holiday_id SERIAL PRIMARY KEY,                   -- 休業日ID: 例外日を一意に識別するID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    holiday_date DATE NOT NULL,                      -- 対象日（日本時間）
+    is_closed BOOLEAN NOT NULL DEFAULT TRUE,         -- 終日休業フラグ: true=休業、false=臨時営業時間あり
+    open_time VARCHAR(5),                            -- 臨時営業開始時刻 HH:MM（半角）
+    close_time VARCHAR(5),                           -- 臨時営業終了時刻 HH:MM（半角）
+    note VARCHAR(255),                               -- 備考（全角）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    CONSTRAINT fk_store_holidays_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE -- 店舗IDの外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE store_holidays IS '店舗の休業日・臨時営業時間テーブル';
+-- カラムコメント
+COMMENT ON COLUMN store_holidays.holiday_id IS '休業日ID: 例外日を一意に識別するID';
+COMMENT ON COLUMN store_holidays.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN store_holidays.holiday_date IS '対象日（日本時間）';
+COMMENT ON COLUMN store_holidays.is_closed IS '終日休業フラグ: true=休業、false=臨時営業時間あり';
+COMMENT ON COLUMN store_holidays.open_time IS '臨時営業開始時刻 HH:MM（半角）';
+COMMENT ON COLUMN store_holidays.close_time IS '臨時営業終了時刻 HH:MM（半角）';
+COMMENT ON COLUMN store_holidays.note IS '備考（全角）';
+COMMENT ON COLUMN store_holidays.created_at IS '作成日';
+CREATE INDEX idx_store_holidays_store_id ON store_holidays (store_id, holiday_date);
+
+-- 店舗レビューテーブル生成
+CREATE TABLE store_reviews (
+    review_id SERIAL PRIMARY KEY,                    -- レビューID: レビューを一意に識別するID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    user_id INTEGER NOT NULL,                        -- ユーザーID（ユーザーテーブルのFK）
+    rating SMALLINT NOT NULL,                        -- 評価: 1〜5の星評価（半角）
+    review_text TEXT,                                -- レビュー本文（全角）
+    is_published BOOLEAN NOT NULL DEFAULT TRUE,      -- 公開フラグ: true=公開、false=非公開
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT fk_store_reviews_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE, -- 店舗IDの外部キー制約
+    CONSTRAINT fk_store_reviews_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE, -- ユーザーIDの外部キー制約
+    CONSTRAINT uq_store_reviews_store_user UNIQUE (store_id, user_id), -- 1ユーザー1店舗につき1レビュー
+    CONSTRAINT chk_rating CHECK (rating BETWEEN 1 AND 5) -- 評価チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE store_reviews IS '店舗レビュー管理テーブル（公開レビューから店舗の評価点数を算出）';
+-- カラムコメント
+COMMENT ON COLUMN store_reviews.review_id IS 'レビューID: レビューを一意に識別するID';
+COMMENT ON COLUMN store_reviews.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN store_reviews.user_id IS 'ユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN store_reviews.rating IS '評価: 1〜5の星評価（半角）';
+COMMENT ON COLUMN store_reviews.review_text IS 'レビュー本文（全角）';
+COMMENT ON COLUMN store_reviews.is_published IS '公開フラグ: true=公開、false=非公開';
+COMMENT ON COLUMN store_reviews.created_at IS '作成日';
+COMMENT ON COLUMN store_reviews.updated_at IS '更新日';
+CREATE INDEX idx_store_reviews_store_id ON store_reviews (store_id, is_published, created_at);
+
+-- 店舗メニュー項目テーブル生成（画面ナビゲーション用の menus テーブルとは別）
+CREATE TABLE store_menu_items (
+    menu_item_id SERIAL PRIMARY KEY,                 -- メニュー項目ID: メニュー項目を一意に識別するID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    section VARCHAR(100) NOT NULL DEFAULT '',        -- セクション（例：主食, ドリンク）（全角）
+    item_name VARCHAR(255) NOT NULL,                 -- 商品名（全角）
+    description TEXT,                                -- 説明（全角）
+    price DECIMAL(10,2) NOT NULL,                    -- 価格（半角）
+    currency VARCHAR(3) NOT NULL DEFAULT 'JPY',      -- 通貨コード ISO 4217（半角）
+    photo_file_id INTEGER,                           -- 写真ファイルID（ファイルテーブルのFK）
+    allergens JSONB,                                 -- アレルゲン一覧（例：["egg","milk"]）
+    is_vegan BOOLEAN NOT NULL DEFAULT FALSE,         -- ヴィーガン対応フラグ
+    is_halal BOOLEAN NOT NULL DEFAULT FALSE,         -- ハラール対応フラグ
+    is_gluten_free BOOLEAN NOT NULL DEFAULT FALSE,   -- グルテンフリー対応フラグ
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,      -- 提供可能フラグ
+    display_order INTEGER NOT NULL DEFAULT 0,        -- 表示順序（半角）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT fk_store_menu_items_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE, -- 店舗IDの外部キー制約
+    CONSTRAINT fk_store_menu_items_photo_file_id FOREIGN KEY (photo_file_id) REFERENCES files(file_id) ON DELETE SET NULL -- 写真ファイルIDの外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE store_menu_items IS '店舗メニュー項目管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN store_menu_items.menu_item_id IS 'メニュー項目ID: メニュー項目を一意に識別するID';
+COMMENT ON COLUMN store_menu_items.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN store_menu_items.section IS 'セクション（例：主食, ドリンク）（全角）';
+COMMENT ON COLUMN store_menu_items.item_name IS '商品名（全角）';
+COMMENT ON COLUMN store_menu_items.description IS '説明（全角）';
+COMMENT ON COLUMN store_menu_items.price IS '価格（半角）';
+COMMENT ON COLUMN store_menu_items.currency IS '通貨コード ISO 4217（半角）';
+COMMENT ON COLUMN store_menu_items.photo_file_id IS '写真ファイルID（ファイルテーブルのFK）';
+COMMENT ON COLUMN store_menu_items.allergens IS 'アレルゲン一覧（例：["egg","milk"]）';
+COMMENT ON COLUMN store_menu_items.is_vegan IS 'ヴィーガン対応フラグ';
+COMMENT ON COLUMN store_menu_items.is_halal IS 'ハラール対応フラグ';
+COMMENT ON COLUMN store_menu_items.is_gluten_free IS 'グルテンフリー対応フラグ';
+COMMENT ON COLUMN store_menu_items.is_available IS '提供可能フラグ';
+COMMENT ON COLUMN store_menu_items.display_order IS '表示順序（半角）';
+COMMENT ON COLUMN store_menu_items.created_at IS '作成日';
+COMMENT ON COLUMN store_menu_items.updated_at IS '更新日';
+CREATE INDEX idx_store_menu_items_store_id ON store_menu_items (store_id, display_order);
+
+-- 多言語翻訳テーブル生成
+CREATE TABLE translations (
+    translation_id SERIAL PRIMARY KEY,               -- 翻訳ID: 翻訳を一意に識別するID
+    translatable_type VARCHAR(50) NOT NULL,          -- 翻訳対象の種類（例：Facility, Store, Article, Notice）（半角）
+    translatable_id INTEGER NOT NULL,                -- 翻訳対象のID（半角）
+    field_name VARCHAR(50) NOT NULL,                 -- 翻訳対象のカラム名（例：description_text）（半角）
+    language_code VARCHAR(20) NOT NULL,              -- 言語コード（言語テーブルの language_code）（半角）
+    content TEXT NOT NULL,                           -- 翻訳文
+    source VARCHAR(20) NOT NULL DEFAULT 'human',     -- 翻訳元: human=人手入力、machine=機械翻訳（半角）
+    is_approved BOOLEAN NOT NULL DEFAULT TRUE,       -- 承認フラグ: 機械翻訳は編集者の確認後に true
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT uq_translations_target UNIQUE (translatable_type, translatable_id, field_name, language_code) -- 1項目1言語につき1件
+);
+-- テーブルコメント
+COMMENT ON TABLE translations IS '施設・店舗・記事・お知らせ等の多言語翻訳テーブル（原文は各テーブルに日本語で保持）';
+-- カラムコメント
+COMMENT ON COLUMN translations.translation_id IS '翻訳ID: 翻訳を一意に識別するID';
+COMMENT ON COLUMN translations.translatable_type IS '翻訳対象の種類（例：Facility, Store, Article, Notice）（半角）';
+COMMENT ON COLUMN translations.translatable_id IS '翻訳対象のID（半角）';
+COMMENT ON COLUMN translations.field_name IS '翻訳対象のカラム名（例：description_text）（半角）';
+COMMENT ON COLUMN translations.language_code IS '言語コード（言語テーブルの language_code）（半角）';
+COMMENT ON COLUMN translations.content IS '翻訳文';
+COMMENT ON COLUMN translations.source IS '翻訳元: human=人手入力、machine=機械翻訳（半角）';
+COMMENT ON COLUMN translations.is_approved IS '承認フラグ: 機械翻訳は編集者の確認後に true';
+COMMENT ON COLUMN translations.created_at IS '作成日';
+COMMENT ON COLUMN translations.updated_at IS '更新日';
+-- 機械翻訳レビュー待ち一覧用インデックス
+CREATE INDEX idx_translations_review ON translations (source, is_approved);
+
+-- 機械翻訳失敗記録テーブル生成
+CREATE TABLE translation_failures (
+    failure_id SERIAL PRIMARY KEY,                   -- ID
+    translatable_type VARCHAR(50) NOT NULL,          -- 翻訳対象の種類（半角）
+    translatable_id INTEGER NOT NULL,                -- 翻訳対象のID（半角）
+    field_name VARCHAR(50) NOT NULL,                 -- 翻訳対象のカラム名（半角）
+    language_code VARCHAR(20) NOT NULL,              -- 言語コード（半角）
+    attempts INTEGER NOT NULL,                       -- 連続失敗回数（半角）
+    last_error TEXT,                                 -- 直近の失敗理由
+    next_attempt_at TIMESTAMP NOT NULL,              -- 次回再試行日時: これより前は再試行しない
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT uq_translation_failures_target UNIQUE (translatable_type, translatable_id, field_name, language_code) -- 1項目1言語につき1件
+);
+-- テーブルコメント
+COMMENT ON TABLE translation_failures IS '機械翻訳バッチの失敗記録テーブル（失敗回数に応じて再試行を遅らせる）';
+-- カラムコメント
+COMMENT ON COLUMN translation_failures.failure_id IS 'ID';
+COMMENT ON COLUMN translation_failures.translatable_type IS '翻訳対象の種類（半角）';
+COMMENT ON COLUMN translation_failures.translatable_id IS '翻訳対象のID（半角）';
+COMMENT ON COLUMN translation_failures.field_name IS '翻訳対象のカラム名（半角）';
+COMMENT ON COLUMN translation_failures.language_code IS '言語コード（半角）';
+COMMENT ON COLUMN translation_failures.attempts IS '連続失敗回数（半角）';
+COMMENT ON COLUMN translation_failures.last_error IS '直近の失敗理由';
+COMMENT ON COLUMN translation_failures.next_attempt_at IS '次回再試行日時: これより前は再試行しない';
+COMMENT ON COLUMN translation_failures.updated_at IS '更新日';
+CREATE INDEX idx_translation_failures_next_attempt_at ON translation_failures (next_attempt_at);
+
+-- ARアンカーテーブル生成
+CREATE TABLE ar_anchors (
+    anchor_id SERIAL PRIMARY KEY,                    -- アンカーID: ARアンカーを一意に識別するID
+    facility_id INTEGER NOT NULL,                    -- 施設ID（施設テーブルのFK）
+    anchor_name VARCHAR(255) NOT NULL,               -- アンカー名（全角）
+    marker_type VARCHAR(20) NOT NULL,                -- マーカー種別: qr=QRコード、image=画像マーカー（半角）
+    marker_code VARCHAR(100) NOT NULL UNIQUE,        -- マーカー識別コード: QRコードの内容または画像マーカーID（半角）
+    marker_file_id INTEGER,                          -- 画像マーカーの参照画像ファイルID（ファイルテーブルのFK）
+    asset_file_id INTEGER NOT NULL,                  -- 3Dモデル・オーバーレイ素材のファイルID（ファイルテーブルのFK）
+    altitude DECIMAL(8,2) NOT NULL DEFAULT 0,        -- 高度（メートル）（半角）
+    heading DECIMAL(5,2) NOT NULL DEFAULT 0,         -- 方位: 真北から時計回りの角度（度）（半角）
+    scale DECIMAL(8,3) NOT NULL DEFAULT 1,           -- 表示倍率（半角）
+    active_from TIMESTAMP,                           -- 有効期間開始日時（NULLは制限なし）
+    active_until TIMESTAMP,                          -- 有効期間終了日時（NULLは制限なし）
+    daily_start VARCHAR(5),                          -- 毎日の有効開始時刻 HH:MM（日本時間）（半角）
+    daily_end VARCHAR(5),                            -- 毎日の有効終了時刻 HH:MM、開始時刻以前の場合は翌日まで（半角）
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,         -- 有効フラグ
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT fk_ar_anchors_facility_id FOREIGN KEY (facility_id) REFERENCES facilities(facility_id) ON DELETE CASCADE, -- 施設IDの外部キー制約
+    CONSTRAINT fk_ar_anchors_marker_file_id FOREIGN KEY (marker_file_id) REFERENCES files(file_id) ON DELETE SET NULL, -- 参照画像ファイルIDの外部キー制約
+    CONSTRAINT fk_ar_anchors_asset_file_id FOREIGN KEY (asset_file_id) REFERENCES files(file_id), -- 素材ファイルIDの外部キー制約
+    CONSTRAINT chk_marker_type CHECK (marker_type IN ('qr', 'image')) -- マーカー種別チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE ar_anchors IS '施設に設置するARアンカー（マーカーと表示コンテンツ）管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN ar_anchors.anchor_id IS 'アンカーID: ARアンカーを一意に識別するID';
+COMMENT ON COLUMN ar_anchors.facility_id IS '施設ID（施設テーブルのFK）';
+COMMENT ON COLUMN ar_anchors.anchor_name IS 'アンカー名（全角）';
+COMMENT ON COLUMN ar_anchors.marker_type IS 'マーカー種別: qr=QRコード、image=画像マーカー（半角）';
+COMMENT ON COLUMN ar_anchors.marker_code IS 'マーカー識別コード: QRコードの内容または画像マーカーID（半角）';
+COMMENT ON COLUMN ar_anchors.marker_file_id IS '画像マーカーの参照画像ファイルID（ファイルテーブルのFK）';
+COMMENT ON COLUMN ar_anchors.asset_file_id IS '3Dモデル・オーバーレイ素材のファイルID（ファイルテーブルのFK）';
+COMMENT ON COLUMN ar_anchors.altitude IS '高度（メートル）（半角）';
+COMMENT ON COLUMN ar_anchors.heading IS '方位: 真北から時計回りの角度（度）（半角）';
+COMMENT ON COLUMN ar_anchors.scale IS '表示倍率（半角）';
+COMMENT ON COLUMN ar_anchors.active_from IS '有効期間開始日時（NULLは制限なし）';
+COMMENT ON COLUMN ar_anchors.active_until IS '有効期間終了日時（NULLは制限なし）';
+COMMENT ON COLUMN ar_anchors.daily_start IS '毎日の有効開始時刻 HH:MM（日本時間）（半角）';
+COMMENT ON COLUMN ar_anchors.daily_end IS '毎日の有効終了時刻 HH:MM、開始時刻以前の場合は翌日まで（半角）';
+COMMENT ON COLUMN ar_anchors.is_active IS '有効フラグ';
+COMMENT ON COLUMN ar_anchors.created_at IS '作成日';
+COMMENT ON COLUMN ar_anchors.updated_at IS '更新日';
+CREATE INDEX idx_ar_anchors_facility_id ON ar_anchors (facility_id);
+
+-- スタンプラリー（集章キャンペーン）テーブル
+CREATE TABLE campaigns (
+    campaign_id SERIAL PRIMARY KEY,                  -- キャンペーンID
+    campaign_name VARCHAR(255) NOT NULL,             -- キャンペーン名（全角）
+    description TEXT,                                -- 説明（全角）
+    start_at TIMESTAMP NOT NULL,                     -- 開始日時
+    end_at TIMESTAMP NOT NULL,                       -- 終了日時
+    rule_type VARCHAR(20) NOT NULL,                  -- 達成条件: all=全施設、any=任意のN施設、ordered=指定順（半角）
+    required_count INTEGER NOT NULL DEFAULT 0,       -- any の場合に必要な施設数（半角）
+    reward_description VARCHAR(255),                 -- 特典内容（全角）
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,         -- 有効フラグ
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT chk_campaign_rule_type CHECK (rule_type IN ('all', 'any', 'ordered')), -- 達成条件チェック制約
+    CONSTRAINT chk_campaign_period CHECK (end_at > start_at) -- 期間チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE campaigns IS 'スタンプラリーキャンペーン管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN campaigns.campaign_id IS 'キャンペーンID';
+COMMENT ON COLUMN campaigns.campaign_name IS 'キャンペーン名（全角）';
+COMMENT ON COLUMN campaigns.description IS '説明（全角）';
+COMMENT ON COLUMN campaigns.start_at IS '開始日時';
+COMMENT ON COLUMN campaigns.end_at IS '終了日時';
+COMMENT ON COLUMN campaigns.rule_type IS '達成条件: all=全施設、any=任意のN施設、ordered=指定順（半角）';
+COMMENT ON COLUMN campaigns.required_count IS 'any の場合に必要な施設数（半角）';
+COMMENT ON COLUMN campaigns.reward_description IS '特典内容（全角）';
+COMMENT ON COLUMN campaigns.is_active IS '有効フラグ';
+COMMENT ON COLUMN campaigns.created_at IS '作成日';
+COMMENT ON COLUMN campaigns.updated_at IS '更新日';
+
+-- キャンペーン対象施設テーブル
+CREATE TABLE campaign_facilities (
+    campaign_facility_id SERIAL PRIMARY KEY,         -- ID
+    campaign_id INTEGER NOT NULL,                    -- キャンペーンID（キャンペーンテーブルのFK）
+    facility_id INTEGER NOT NULL,                    -- 施設ID（施設テーブルのFK）
+    step_order INTEGER NOT NULL,                     -- 順番: ordered の場合の訪問順（半角）
+    CONSTRAINT uq_campaign_facilities UNIQUE (campaign_id, facility_id), -- 同一施設の重複登録防止
+    CONSTRAINT fk_campaign_facilities_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns(campaign_id) ON DELETE CASCADE, -- キャンペーンIDの外部キー制約
+    CONSTRAINT fk_campaign_facilities_facility_id FOREIGN KEY (facility_id) REFERENCES facilities(facility_id) ON DELETE CASCADE -- 施設IDの外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE campaign_facilities IS 'キャンペーン対象施設テーブル';
+-- カラムコメント
+COMMENT ON COLUMN campaign_facilities.campaign_facility_id IS 'ID';
+COMMENT ON COLUMN campaign_facilities.campaign_id IS 'キャンペーンID（キャンペーンテーブルのFK）';
+COMMENT ON COLUMN campaign_facilities.facility_id IS '施設ID（施設テーブルのFK）';
+COMMENT ON COLUMN campaign_facilities.step_order IS '順番: ordered の場合の訪問順（半角）';
+CREATE INDEX idx_campaign_facilities_facility_id ON campaign_facilities (facility_id);
+
+-- キャンペーン提携店舗テーブル
+CREATE TABLE campaign_stores (
+    campaign_store_id SERIAL PRIMARY KEY,            -- ID
+    campaign_id INTEGER NOT NULL,                    -- キャンペーンID（キャンペーンテーブルのFK）
+    store_id INTEGER NOT NULL,                       -- 店舗ID: 特典を引き換えられる店舗（店舗テーブルのFK）
+    CONSTRAINT uq_campaign_stores UNIQUE (campaign_id, store_id), -- 同一店舗の重複登録防止
+    CONSTRAINT fk_campaign_stores_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns(campaign_id) ON DELETE CASCADE, -- キャンペーンIDの外部キー制約
+    CONSTRAINT fk_campaign_stores_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE -- 店舗IDの外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE campaign_stores IS 'キャンペーン特典の引換提携店舗テーブル';
+-- カラムコメント
+COMMENT ON COLUMN campaign_stores.campaign_store_id IS 'ID';
+COMMENT ON COLUMN campaign_stores.campaign_id IS 'キャンペーンID（キャンペーンテーブルのFK）';
+COMMENT ON COLUMN campaign_stores.store_id IS '店舗ID: 特典を引き換えられる店舗（店舗テーブルのFK）';
+CREATE INDEX idx_campaign_stores_store_id ON campaign_stores (store_id);
+
+-- キャンペーン特典テーブル
+CREATE TABLE campaign_rewards (
+    reward_id SERIAL PRIMARY KEY,                    -- 特典ID
+    campaign_id INTEGER NOT NULL,                    -- キャンペーンID（キャンペーンテーブルのFK）
+    user_id INTEGER NOT NULL,                        -- ユーザーID（ユーザーテーブルのFK）
+    coupon_code VARCHAR(20) NOT NULL UNIQUE,         -- 引換コード（半角）
+    issued_at TIMESTAMP NOT NULL,                    -- 発行日時
+    redeemed_at TIMESTAMP,                           -- 引換日時（NULLは未引換）
+    redeemed_by INTEGER,                             -- 引換処理したユーザーID（ユーザーテーブルのFK）
+    redeemed_store_id INTEGER,                       -- 引換店舗ID（店舗テーブルのFK）
+    CONSTRAINT uq_campaign_rewards_user UNIQUE (campaign_id, user_id), -- 1キャンペーン1ユーザー1件
+    CONSTRAINT fk_campaign_rewards_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns(campaign_id) ON DELETE CASCADE, -- キャンペーンIDの外部キー制約
+    CONSTRAINT fk_campaign_rewards_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE, -- ユーザーIDの外部キー制約
+    CONSTRAINT fk_campaign_rewards_redeemed_by FOREIGN KEY (redeemed_by) REFERENCES users(user_id) ON DELETE SET NULL, -- 引換処理者の外部キー制約
+    CONSTRAINT fk_campaign_rewards_redeemed_store_id FOREIGN KEY (redeemed_store_id) REFERENCES stores(store_id) ON DELETE SET NULL -- 引換店舗の外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE campaign_rewards IS 'キャンペーン達成特典（引換コード）テーブル';
+-- カラムコメント
+COMMENT ON COLUMN campaign_rewards.reward_id IS '特典ID';
+COMMENT ON COLUMN campaign_rewards.campaign_id IS 'キャンペーンID（キャンペーンテーブルのFK）';
+COMMENT ON COLUMN campaign_rewards.user_id IS 'ユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN campaign_rewards.coupon_code IS '引換コード（半角）';
+COMMENT ON COLUMN campaign_rewards.issued_at IS '発行日時';
+COMMENT ON COLUMN campaign_rewards.redeemed_at IS '引換日時（NULLは未引換）';
+COMMENT ON COLUMN campaign_rewards.redeemed_by IS '引換処理したユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN campaign_rewards.redeemed_store_id IS '引換店舗ID（店舗テーブルのFK）';
+CREATE INDEX idx_campaign_rewards_user_id ON campaign_rewards (user_id);
+
+-- クーポンテーブル
+CREATE TABLE coupons (
+    coupon_id SERIAL PRIMARY KEY,                    -- クーポンID
+    store_id INTEGER NOT NULL,                       -- 店舗ID（店舗テーブルのFK）
+    title VARCHAR(255) NOT NULL,                     -- タイトル（全角）
+    discount_description VARCHAR(255) NOT NULL,      -- 割引内容（全角）
+    terms TEXT,                                      -- 利用条件（全角）
+    valid_from TIMESTAMP NOT NULL,                   -- 有効期間開始日時
+    valid_until TIMESTAMP NOT NULL,                  -- 有効期間終了日時
+    total_limit INTEGER NOT NULL DEFAULT 0,          -- 配布・利用総数の上限、0は無制限（半角）
+    per_user_limit INTEGER NOT NULL DEFAULT 1,       -- 1ユーザーあたりの取得・利用上限、0は無制限（半角）
+    claimed_count INTEGER NOT NULL DEFAULT 0,        -- 取得数（半角）
+    redeemed_count INTEGER NOT NULL DEFAULT 0,       -- 利用数（半角）
+    target_language VARCHAR(10),                     -- 対象言語コード、NULLは制限なし（半角）
+    campaign_id INTEGER,                             -- 対象キャンペーンID: 達成者のみ取得可（キャンペーンテーブルのFK）
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,         -- 有効フラグ
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT fk_coupons_store_id FOREIGN KEY (store_id) REFERENCES stores(store_id) ON DELETE CASCADE, -- 店舗IDの外部キー制約
+    CONSTRAINT fk_coupons_campaign_id FOREIGN KEY (campaign_id) REFERENCES campaigns(campaign_id) ON DELETE SET NULL, -- キャンペーンIDの外部キー制約
+    CONSTRAINT chk_coupon_period CHECK (valid_until > valid_from), -- 有効期間チェック制約
+    CONSTRAINT chk_coupon_limits CHECK (total_limit >= 0 AND per_user_limit >= 0) -- 上限値チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE coupons IS '店舗クーポン管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN coupons.coupon_id IS 'クーポンID';
+COMMENT ON COLUMN coupons.store_id IS '店舗ID（店舗テーブルのFK）';
+COMMENT ON COLUMN coupons.title IS 'タイトル（全角）';
+COMMENT ON COLUMN coupons.discount_description IS '割引内容（全角）';
+COMMENT ON COLUMN coupons.terms IS '利用条件（全角）';
+COMMENT ON COLUMN coupons.valid_from IS '有効期間開始日時';
+COMMENT ON COLUMN coupons.valid_until IS '有効期間終了日時';
+COMMENT ON COLUMN coupons.total_limit IS '配布・利用総数の上限、0は無制限（半角）';
+COMMENT ON COLUMN coupons.per_user_limit IS '1ユーザーあたりの取得・利用上限、0は無制限（半角）';
+COMMENT ON COLUMN coupons.claimed_count IS '取得数（半角）';
+COMMENT ON COLUMN coupons.redeemed_count IS '利用数（半角）';
+COMMENT ON COLUMN coupons.target_language IS '対象言語コード、NULLは制限なし（半角）';
+COMMENT ON COLUMN coupons.campaign_id IS '対象キャンペーンID: 達成者のみ取得可（キャンペーンテーブルのFK）';
+COMMENT ON COLUMN coupons.is_active IS '有効フラグ';
+COMMENT ON COLUMN coupons.created_at IS '作成日';
+COMMENT ON COLUMN coupons.updated_at IS '更新日';
+CREATE INDEX idx_coupons_store_id ON coupons (store_id);
+
+-- ユーザークーポン（ウォレット）テーブル
+CREATE TABLE user_coupons (
+    user_coupon_id SERIAL PRIMARY KEY,               -- ID
+    coupon_id INTEGER NOT NULL,                      -- クーポンID（クーポンテーブルのFK）
+    user_id INTEGER NOT NULL,                        -- ユーザーID（ユーザーテーブルのFK）
+    redeem_code VARCHAR(20) NOT NULL UNIQUE,         -- ワンタイム利用コード（半角）
+    claimed_at TIMESTAMP NOT NULL,                   -- 取得日時
+    redeemed_at TIMESTAMP,                           -- 利用日時（NULLは未使用）
+    redeemed_by INTEGER,                             -- 利用処理した店舗スタッフのユーザーID（ユーザーテーブルのFK）
+    CONSTRAINT fk_user_coupons_coupon_id FOREIGN KEY (coupon_id) REFERENCES coupons(coupon_id) ON DELETE CASCADE, -- クーポンIDの外部キー制約
+    CONSTRAINT fk_user_coupons_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE, -- ユーザーIDの外部キー制約
+    CONSTRAINT fk_user_coupons_redeemed_by FOREIGN KEY (redeemed_by) REFERENCES users(user_id) ON DELETE SET NULL -- 利用処理者の外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE user_coupons IS 'ユーザーが取得したクーポン（ウォレット）テーブル';
+-- カラムコメント
+COMMENT ON COLUMN user_coupons.user_coupon_id IS 'ID';
+COMMENT ON COLUMN user_coupons.coupon_id IS 'クーポンID（クーポンテーブルのFK）';
+COMMENT ON COLUMN user_coupons.user_id IS 'ユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN user_coupons.redeem_code IS 'ワンタイム利用コード（半角）';
+COMMENT ON COLUMN user_coupons.claimed_at IS '取得日時';
+COMMENT ON COLUMN user_coupons.redeemed_at IS '利用日時（NULLは未使用）';
+COMMENT ON COLUMN user_coupons.redeemed_by IS '利用処理した店舗スタッフのユーザーID（ユーザーテーブルのFK）';
+CREATE INDEX idx_user_coupons_coupon_user ON user_coupons (coupon_id, user_id);
+CREATE INDEX idx_user_coupons_user_id ON user_coupons (user_id);
+
+-- お気に入りテーブル（多態関連）
+CREATE TABLE favorites (
+    favorite_id SERIAL PRIMARY KEY,                  -- お気に入りID
+    user_id INTEGER NOT NULL,                        -- ユーザーID（ユーザーテーブルのFK）
+    favoritable_type VARCHAR(50) NOT NULL,           -- エンティティ種別: Store, Facility, Article（半角）
+    favoritable_id INTEGER NOT NULL,                 -- エンティティID: 関連するテーブルのレコードID
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    CONSTRAINT uq_favorites_user_target UNIQUE (user_id, favoritable_type, favoritable_id), -- 同一対象の重複登録防止
+    CONSTRAINT fk_favorites_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE, -- ユーザーIDの外部キー制約
+    CONSTRAINT chk_favoritable_type CHECK (favoritable_type IN ('Store', 'Facility', 'Article')) -- エンティティ種別チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE favorites IS 'ユーザーのお気に入り（店舗、施設、記事）管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN favorites.favorite_id IS 'お気に入りID';
+COMMENT ON COLUMN favorites.user_id IS 'ユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN favorites.favoritable_type IS 'エンティティ種別: Store, Facility, Article（半角）';
+COMMENT ON COLUMN favorites.favoritable_id IS 'エンティティID: 関連するテーブルのレコードID';
+COMMENT ON COLUMN favorites.created_at IS '作成日';
+CREATE INDEX idx_favorites_target ON favorites (favoritable_type, favoritable_id);
+
+-- トリガー関数: 対象エンティティ削除時にお気に入りを削除
+CREATE OR REPLACE FUNCTION delete_favorites() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'stores' THEN
+        DELETE FROM favorites WHERE favoritable_type = 'Store' AND favoritable_id = OLD.store_id;
+    ELSIF TG_TABLE_NAME = 'facilities' THEN
+        DELETE FROM favorites WHERE favoritable_type = 'Facility' AND favoritable_id = OLD.facility_id;
+    ELSIF TG_TABLE_NAME = 'articles' THEN
+        DELETE FROM favorites WHERE favoritable_type = 'Article' AND favoritable_id = OLD.article_id;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- トリガーの作成
+CREATE TRIGGER stores_delete_favorites
+    AFTER DELETE ON stores
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_favorites();
+CREATE TRIGGER facilities_delete_favorites
+    AFTER DELETE ON facilities
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_favorites();
+CREATE TRIGGER articles_delete_favorites
+    AFTER DELETE ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_favorites();
+
+-- 旅程テーブル
+CREATE TABLE itineraries (
+    itinerary_id SERIAL PRIMARY KEY,                 -- 旅程ID
+    user_id INTEGER NOT NULL,                        -- ユーザーID（ユーザーテーブルのFK）
+    title VARCHAR(255) NOT NULL,                     -- タイトル（全角）
+    description TEXT,                                -- 説明（全角）
+    start_date DATE,                                 -- 1日目の日付
+    share_token VARCHAR(32) UNIQUE,                  -- 閲覧専用共有リンクのトークン、NULLは非共有（半角）
+    cloned_from INTEGER,                             -- 複製元の旅程ID（旅程テーブルのFK）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    CONSTRAINT fk_itineraries_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE, -- ユーザーIDの外部キー制約
+    CONSTRAINT fk_itineraries_cloned_from FOREIGN KEY (cloned_from) REFERENCES itineraries(itinerary_id) ON DELETE SET NULL -- 複製元の外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE itineraries IS 'ユーザーの旅程管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN itineraries.itinerary_id IS '旅程ID';
+COMMENT ON COLUMN itineraries.user_id IS 'ユーザーID（ユーザーテーブルのFK）';
+COMMENT ON COLUMN itineraries.title IS 'タイトル（全角）';
+COMMENT ON COLUMN itineraries.description IS '説明（全角）';
+COMMENT ON COLUMN itineraries.start_date IS '1日目の日付';
+COMMENT ON COLUMN itineraries.share_token IS '閲覧専用共有リンクのトークン、NULLは非共有（半角）';
+COMMENT ON COLUMN itineraries.cloned_from IS '複製元の旅程ID（旅程テーブルのFK）';
+COMMENT ON COLUMN itineraries.created_at IS '作成日';
+COMMENT ON COLUMN itineraries.updated_at IS '更新日';
+CREATE INDEX idx_itineraries_user_id ON itineraries (user_id);
+
+-- 旅程日テーブル
+CREATE TABLE itinerary_days (
+    day_id SERIAL PRIMARY KEY,                       -- 旅程日ID
+    itinerary_id INTEGER NOT NULL,                   -- 旅程ID（旅程テーブルのFK）
+    day_index INTEGER NOT NULL,                      -- 何日目か、1から（半角）
+    title VARCHAR(255),                              -- タイトル（全角）
+    CONSTRAINT fk_itinerary_days_itinerary_id FOREIGN KEY (itinerary_id) REFERENCES itineraries(itinerary_id) ON DELETE CASCADE -- 旅程IDの外部キー制約
+);
+-- テーブルコメント
+COMMENT ON TABLE itinerary_days IS '旅程の日別管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN itinerary_days.day_id IS '旅程日ID';
+COMMENT ON COLUMN itinerary_days.itinerary_id IS '旅程ID（旅程テーブルのFK）';
+COMMENT ON COLUMN itinerary_days.day_index IS '何日目か、1から（半角）';
+COMMENT ON COLUMN itinerary_days.title IS 'タイトル（全角）';
+CREATE INDEX idx_itinerary_days_itinerary_id ON itinerary_days (itinerary_id);
+
+-- 旅程立ち寄り先テーブル
+CREATE TABLE itinerary_stops (
+    stop_id SERIAL PRIMARY KEY,                      -- 立ち寄り先ID
+    day_id INTEGER NOT NULL,                         -- 旅程日ID（旅程日テーブルのFK）
+    stop_order INTEGER NOT NULL,                     -- 当日の順番、1から（半角）
+    stop_type VARCHAR(50) NOT NULL,                  -- エンティティ種別: Store, Facility（半角）
+    target_id INTEGER NOT NULL,                      -- エンティティID: 店舗IDまたは施設ID
+    planned_start VARCHAR(5),                        -- 到着予定時刻 HH:MM（半角）
+    planned_end VARCHAR(5),                          -- 出発予定時刻 HH:MM（半角）
+    note TEXT,                                       -- メモ（全角）
+    CONSTRAINT fk_itinerary_stops_day_id FOREIGN KEY (day_id) REFERENCES itinerary_days(day_id) ON DELETE CASCADE, -- 旅程日IDの外部キー制約
+    CONSTRAINT chk_stop_type CHECK (stop_type IN ('Store', 'Facility')) -- エンティティ種別チェック制約
+);
+-- テーブルコメント
+COMMENT ON TABLE itinerary_stops IS '旅程日ごとの立ち寄り先（店舗、施設）管理テーブル';
+-- カラムコメント
+COMMENT ON COLUMN itinerary_stops.stop_id IS '立ち寄り先ID';
+COMMENT ON COLUMN itinerary_stops.day_id IS '旅程日ID（旅程日テーブルのFK）';
+COMMENT ON COLUMN itinerary_stops.stop_order IS '当日の順番、1から（半角）';
+COMMENT ON COLUMN itinerary_stops.stop_type IS 'エンティティ種別: Store, Facility（半角）';
+COMMENT ON COLUMN itinerary_stops.target_id IS 'エンティティID: 店舗IDまたは施設ID';
+COMMENT ON COLUMN itinerary_stops.planned_start IS '到着予定時刻 HH:MM（半角）';
+COMMENT ON COLUMN itinerary_stops.planned_end IS '出発予定時刻 HH:MM（半角）';
+COMMENT ON COLUMN itinerary_stops.note IS 'メモ（全角）';
+CREATE INDEX idx_itinerary_stops_day_id ON itinerary_stops (day_id);
+
+-- 全文検索用の拡張機能とインデックス
+-- 日本語は simple 設定の全文検索では分かち書きされないため、pg_trgm による部分一致で補う
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_articles_title_trgm ON articles USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_articles_body_text_trgm ON articles USING GIN (body_text gin_trgm_ops);
+CREATE INDEX idx_stores_store_name_trgm ON stores USING GIN (store_name gin_trgm_ops);
+-- concat_ws は IMMUTABLE ではなく式インデックスに使えないため、列ごとに作成する
+CREATE INDEX idx_stores_description_text_trgm ON stores USING GIN (description_text gin_trgm_ops);
+CREATE INDEX idx_stores_address_trgm ON stores USING GIN (address gin_trgm_ops);
+CREATE INDEX idx_facilities_facility_name_trgm ON facilities USING GIN (facility_name gin_trgm_ops);
+CREATE INDEX idx_facilities_description_text_trgm ON facilities USING GIN ((COALESCE(description_text, '')) gin_trgm_ops);
+CREATE INDEX idx_tags_tag_name_trgm ON tags USING GIN (tag_name gin_trgm_ops);
+
+-- 入力補完候補テーブル（店舗名、施設名、タグ名、記事タイトルからバックグラウンドで定期再構築）
+CREATE TABLE search_suggestions (
+    entity_type VARCHAR(20) NOT NULL,                -- エンティティ種別: Store, Facility, Tag, Article（半角）
+    entity_id INTEGER NOT NULL,                      -- エンティティID: 関連するテーブルのレコードID
+    label VARCHAR(255) NOT NULL,                     -- 表示文字列（全角）
+    normalized VARCHAR(255) NOT NULL,                -- 検索キー: 表示文字列を NFKC 正規化・ひらがな化・小文字化したもの
+    reading VARCHAR(255) NOT NULL DEFAULT '',        -- 読みの検索キー: カナ読みを同様に正規化したもの（読みなしは空文字）
+    popularity BIGINT NOT NULL DEFAULT 0,            -- 人気度: 訪問数、お気に入り数、レビュー数などの加重合計（半角）
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    PRIMARY KEY (entity_type, entity_id)
+);
+-- テーブルコメント
+COMMENT ON TABLE search_suggestions IS '入力補完候補テーブル';
+-- カラムコメント
+COMMENT ON COLUMN search_suggestions.entity_type IS 'エンティティ種別: Store, Facility, Tag, Article（半角）';
+COMMENT ON COLUMN search_suggestions.entity_id IS 'エンティティID: 関連するテーブルのレコードID';
+COMMENT ON COLUMN search_suggestions.label IS '表示文字列（全角）';
+COMMENT ON COLUMN search_suggestions.normalized IS '検索キー: 表示文字列を NFKC 正規化・ひらがな化・小文字化したもの';
+COMMENT ON COLUMN search_suggestions.reading IS '読みの検索キー: カナ読みを同様に正規化したもの（読みなしは空文字）';
+COMMENT ON COLUMN search_suggestions.popularity IS '人気度: 訪問数、お気に入り数、レビュー数などの加重合計（半角）';
+COMMENT ON COLUMN search_suggestions.updated_at IS '更新日';
+-- 前方一致検索用インデックス（照合順序に依存しないパターン演算子クラス）
+CREATE INDEX idx_search_suggestions_normalized ON search_suggestions (normalized varchar_pattern_ops);
+CREATE INDEX idx_search_suggestions_reading ON search_suggestions (reading varchar_pattern_ops);
+
+-- 共起訪問テーブル（「X を訪れた人は Y も訪れています」、訪問履歴とお気に入りからバックグラウンドで定期再構築）
+CREATE TABLE item_co_visitations (
+    item_type VARCHAR(20) NOT NULL,                  -- 起点のエンティティ種別: Store, Facility（半角）
+    item_id INTEGER NOT NULL,                        -- 起点のエンティティID
+    other_type VARCHAR(20) NOT NULL,                 -- 共起先のエンティティ種別: Store, Facility（半角）
+    other_id INTEGER NOT NULL,                       -- 共起先のエンティティID
+    users INTEGER NOT NULL,                          -- 両方を訪問・お気に入り登録したユーザー数（半角）
+    score DOUBLE PRECISION NOT NULL,                 -- コサイン類似度: 共通ユーザー数 / sqrt(双方のユーザー数の積)（半角）
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新日
+    PRIMARY KEY (item_type, item_id, other_type, other_id)
+);
+-- テーブルコメント
+COMMENT ON TABLE item_co_visitations IS '共起訪問テーブル（レコメンド用、起点ごとに類似度上位のみ保持）';
+-- カラムコメント
+COMMENT ON COLUMN item_co_visitations.item_type IS '起点のエンティティ種別: Store, Facility（半角）';
+COMMENT ON COLUMN item_co_visitations.item_id IS '起点のエンティティID';
+COMMENT ON COLUMN item_co_visitations.other_type IS '共起先のエンティティ種別: Store, Facility（半角）';
+COMMENT ON COLUMN item_co_visitations.other_id IS '共起先のエンティティID';
+COMMENT ON COLUMN item_co_visitations.users IS '両方を訪問・お気に入り登録したユーザー数（半角）';
+COMMENT ON COLUMN item_co_visitations.score IS 'コサイン類似度: 共通ユーザー数 / sqrt(双方のユーザー数の積)（半角）';
+COMMENT ON COLUMN item_co_visitations.updated_at IS '更新日';
+
+-- パスワード再設定トークンテーブル（トークン本体は保存せず SHA-256 ハッシュのみ保持、一度限り有効）
+CREATE TABLE password_reset_tokens (
+    reset_id SERIAL PRIMARY KEY,                     -- 再設定ID
+    user_id INTEGER NOT NULL,                        -- ユーザID（ユーザテーブルのFK）
+    token_hash VARCHAR(64) NOT NULL UNIQUE,          -- トークンの SHA-256 ハッシュ（16進、半角）
+    expires_at TIMESTAMP NOT NULL,                   -- 有効期限
+    used_at TIMESTAMP,                               -- 使用日時: 使用済みまたは無効化された日時、NULL=未使用
+    request_ip VARCHAR(45),                          -- 申請元IPアドレス（半角）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    CONSTRAINT fk_password_reset_user FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+-- テーブルコメント
+COMMENT ON TABLE password_reset_tokens IS 'パスワード再設定トークンテーブル';
+-- カラムコメント
+COMMENT ON COLUMN password_reset_tokens.reset_id IS '再設定ID';
+COMMENT ON COLUMN password_reset_tokens.user_id IS 'ユーザID（ユーザテーブルのFK）';
+COMMENT ON COLUMN password_reset_tokens.token_hash IS 'トークンの SHA-256 ハッシュ（16進、半角）';
+COMMENT ON COLUMN password_reset_tokens.expires_at IS '有効期限';
+COMMENT ON COLUMN password_reset_tokens.used_at IS '使用日時: 使用済みまたは無効化された日時、NULL=未使用';
+COMMENT ON COLUMN password_reset_tokens.request_ip IS '申請元IPアドレス（半角）';
+COMMENT ON COLUMN password_reset_tokens.created_at IS '作成日';
+-- ユーザごとの申請回数集計用インデックス
+CREATE INDEX idx_password_reset_tokens_user ON password_reset_tokens (user_id, created_at);
+
+-- アカウント連携確認テーブル（外部ログインのメールアドレスが既存アカウントと重複した場合、自動統合せず確認を待つ）
+CREATE TABLE account_links (
+    link_id SERIAL PRIMARY KEY,                      -- 連携ID
+    user_id INTEGER NOT NULL,                        -- 連携先の既存ユーザID（ユーザテーブルのFK）
+    purpose VARCHAR(20) NOT NULL DEFAULT 'confirm',  -- 用途: confirm=パスワード等で確認、verify=メール認証後に自動連携（半角）
+    provider VARCHAR(20) NOT NULL,                   -- 連携するログイン方式: google, apple, line（半角）
+    subject VARCHAR(255) NOT NULL,                   -- 外部ログインのユニークID（半角）
+    email VARCHAR(255) NOT NULL,                     -- 外部ログインから取得したメールアドレス（半角）
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,   -- メール確認済みフラグ: ログイン提供元が確認済みの場合 true
+    token_hash VARCHAR(64) NOT NULL UNIQUE,          -- 確認トークンの SHA-256 ハッシュ（16進、半角）
+    attempts INTEGER NOT NULL DEFAULT 0,             -- パスワード入力失敗回数（半角）
+    expires_at TIMESTAMP NOT NULL,                   -- 有効期限
+    used_at TIMESTAMP,                               -- 使用日時: NULL=未使用
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 作成日
+    CONSTRAINT chk_account_links_purpose CHECK (purpose IN ('confirm', 'verify')), -- 用途チェック制約
+    CONSTRAINT fk_account_links_user FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+-- テーブルコメント
+COMMENT ON TABLE account_links IS 'アカウント連携確認テーブル（既存アカウントのパスワードまたはログイン済みセッションで確認、またはメール認証後に連携）';
+-- カラムコメント
+COMMENT ON COLUMN account_links.link_id IS '連携ID';
+COMMENT ON COLUMN account_links.user_id IS '連携先の既存ユーザID（ユーザテーブルのFK）';
+COMMENT ON COLUMN account_links.purpose IS '用途: confirm=パスワード等で確認、verify=メール認証後に自動連携（半角）';
+COMMENT ON COLUMN account_links.provider IS '連携するログイン方式: google, apple, line（半角）';
+COMMENT ON COLUMN account_links.subject IS '外部ログインのユニークID（半角）';
+COMMENT ON COLUMN account_links.email IS '外部ログインから取得したメールアドレス（半角）';
+COMMENT ON COLUMN account_links.email_verified IS 'メール確認済みフラグ: ログイン提供元が確認済みの場合 true';
+COMMENT ON COLUMN account_links.token_hash IS '確認トークンの SHA-256 ハッシュ（16進、半角）';
+COMMENT ON COLUMN account_links.attempts IS 'パスワード入力失敗回数（半角）';
+COMMENT ON COLUMN account_links.expires_at IS '有効期限';
+COMMENT ON COLUMN account_links.used_at IS '使用日時: NULL=未使用';
+COMMENT ON COLUMN account_links.created_at IS '作成日';
+CREATE INDEX idx_account_links_user_id ON account_links (user_id);